@@ -0,0 +1,139 @@
+package main // dnywonnt.me/alerts2incidents/cmd/a2ictl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a client of the alerts2incidents REST API.
+type Client struct {
+	server     string       // Base URL of the API server
+	token      string       // JWT token sent with the requests, if any
+	httpClient *http.Client // HTTP client the requests are made with
+}
+
+// APIError is an error response of the API.
+type APIError struct {
+	StatusCode int           `json:"-"`          // HTTP status code of the response
+	Code       string        `json:"code"`       // Machine-readable code of the error, e.g. not_found
+	Message    string        `json:"message"`    // Message of the error
+	Details    []*FieldError `json:"details"`    // Errors of the individual fields, for the validation errors
+	RequestID  string        `json:"request_id"` // ID of the request, to find it in the logs of the server
+}
+
+// FieldError is an error of a field of the request reported by the API.
+type FieldError struct {
+	Field   string `json:"field"`   // Path of the field, e.g. steps[0].delay
+	Message string `json:"message"` // Description of the error
+}
+
+// Error returns the message of the error and the errors of the fields with the status of the response.
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	if e.Code != "" {
+		message = fmt.Sprintf("%d %s (%s): %s", e.StatusCode, http.StatusText(e.StatusCode), e.Code, e.Message)
+	}
+	if e.StatusCode == http.StatusUnauthorized {
+		message += " (run 'a2ictl login' to log in again)"
+	}
+	for _, detail := range e.Details {
+		message += "\n  " + detail.Message
+	}
+	if e.RequestID != "" {
+		message += "\nrequest ID: " + e.RequestID
+	}
+	return message
+}
+
+// NewClient creates a client of the API server, authenticating the requests with the token if it's not empty.
+func NewClient(server, token string, timeout time.Duration) *Client {
+	return &Client{
+		server:     strings.TrimRight(server, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Request makes a request to the API path with the query and the body of the content type and returns the response body.
+// An error response of the API is returned as an APIError.
+func (c *Client) Request(method, path string, query url.Values, body io.Reader, contentType string, header http.Header) ([]byte, http.Header, error) {
+	requestURL := c.server + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating the request: %w", err)
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		request.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error making the request: %w", err)
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading the response: %w", err)
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		// The body of an error of the API is decoded into the error, anything else, e.g. an error page of a proxy, is kept as the message.
+		apiErr := &APIError{}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr = &APIError{Message: strings.TrimSpace(string(data))}
+		}
+		apiErr.StatusCode = response.StatusCode
+		return nil, nil, apiErr
+	}
+
+	return data, response.Header, nil
+}
+
+// RequestJSON makes a request to the API path with the value marshaled as the JSON body, unless it's nil,
+// and returns the decoded JSON response; the numbers are kept as json.Number, so they're printed as they are.
+func (c *Client) RequestJSON(method, path string, query url.Values, value interface{}, header http.Header) (interface{}, error) {
+	var body io.Reader
+	contentType := ""
+	if value != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling the request: %w", err)
+		}
+		body, contentType = bytes.NewReader(data), "application/json"
+	}
+
+	data, _, err := c.Request(method, path, query, body, contentType, header)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// decodeJSON decodes a JSON document keeping the numbers as json.Number.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding the response: %w", err)
+	}
+	return result, nil
+}
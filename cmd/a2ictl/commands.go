@@ -0,0 +1,544 @@
+package main // dnywonnt.me/alerts2incidents/cmd/a2ictl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Resource is a resource of the API managed by a2ictl.
+type Resource struct {
+	Name    string   // Name of the resource in the commands, e.g. incidents
+	Path    string   // Path of the resource in the API
+	Columns []string // Columns of the list table
+}
+
+// Resources of the API managed by a2ictl.
+var (
+	incidentsResource = &Resource{Name: "incidents", Path: "/api/v1/incidents", Columns: incidentColumns}
+	rulesResource     = &Resource{Name: "rules", Path: "/api/v1/rules", Columns: ruleColumns}
+)
+
+// bulkResultColumns are the columns of the table of the per-item results of a bulk action.
+var bulkResultColumns = []string{"id", "status", "message", "version"}
+
+// Options holds the flags common to all commands.
+type Options struct {
+	Server  string        // API server; the one logged in to if empty
+	Output  string        // Output format
+	Timeout time.Duration // Timeout of the requests
+}
+
+// newFlagSet creates the flags of a command with the common ones.
+func newFlagSet(name string, opts *Options) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("a2ictl "+name, pflag.ContinueOnError)
+	flags.StringVar(&opts.Server, "server", os.Getenv("A2ICTL_SERVER"), "API server; the server logged in to by default")
+	flags.StringVarP(&opts.Output, "output", "o", TableOutput, "output format: table, json or yaml")
+	flags.DurationVar(&opts.Timeout, "timeout", 30*time.Second, "timeout of the requests")
+	return flags
+}
+
+// authorizedClient returns a client of the server logged in to with the cached session.
+func (opts *Options) authorizedClient() (*Client, *Session, error) {
+	if err := checkOutputFormat(opts.Output); err != nil {
+		return nil, nil, err
+	}
+
+	session, err := loadSession()
+	if err != nil {
+		return nil, nil, err
+	}
+	if session == nil {
+		return nil, nil, errors.New("not logged in, run 'a2ictl login --server URL' first")
+	}
+	if opts.Server != "" && strings.TrimRight(opts.Server, "/") != session.Server {
+		return nil, nil, fmt.Errorf("logged in to %s, not to %s; run 'a2ictl login' to switch servers", session.Server, opts.Server)
+	}
+	if !session.ExpiresAt.IsZero() && time.Now().After(session.ExpiresAt) {
+		return nil, nil, fmt.Errorf("the session has expired at %s, run 'a2ictl login' to log in again", session.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return NewClient(session.Server, session.Token, opts.Timeout), session, nil
+}
+
+// runLogin logs in to the API server through LDAP and caches the session.
+func runLogin(args []string) error {
+	opts := &Options{}
+	flags := newFlagSet("login", opts)
+	login := flags.StringP("user", "u", os.Getenv("USER"), "LDAP login")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from the standard input")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if opts.Server == "" {
+		session, err := loadSession()
+		if err != nil {
+			return err
+		}
+		if session == nil {
+			return errors.New("the API server must be set with --server or $A2ICTL_SERVER")
+		}
+		opts.Server = session.Server
+	}
+	if *login == "" {
+		return errors.New("the LDAP login must be set with --user")
+	}
+
+	password, err := readPassword(fmt.Sprintf("Password for %s: ", *login), *passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	client := NewClient(opts.Server, "", opts.Timeout)
+	response, err := client.RequestJSON(http.MethodPost, "/api/v1/auth/ldap", nil, map[string]string{
+		"login":    *login,
+		"password": password,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	auth, _ := response.(map[string]interface{})
+	token, _ := auth["token"].(string)
+	if token == "" {
+		return errors.New("the server hasn't returned a token")
+	}
+	session := &Session{Server: strings.TrimRight(opts.Server, "/"), Token: token}
+	session.UserName, _ = auth["user_name"].(string)
+	if lifeTime, ok := auth["token_life_time"].(json.Number); ok {
+		if nanoseconds, err := lifeTime.Int64(); err == nil && nanoseconds > 0 {
+			session.ExpiresAt = time.Now().Add(time.Duration(nanoseconds)).UTC()
+		}
+	}
+	if err := saveSession(session); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in to %s as %s\n", session.Server, session.UserName)
+	if !session.ExpiresAt.IsZero() {
+		fmt.Printf("The session expires at %s\n", session.ExpiresAt.Local().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// runLogout removes the cached session.
+func runLogout(args []string) error {
+	flags := newFlagSet("logout", &Options{})
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	return deleteSession()
+}
+
+// readPassword reads the password from the standard input, prompting for it and hiding the input on terminals.
+func readPassword(prompt string, fromStdin bool) (string, error) {
+	if !fromStdin {
+		fmt.Fprint(os.Stderr, prompt)
+		// stty fails when the input isn't a terminal, then the password is read as is.
+		if err := runStty("-echo"); err == nil {
+			defer fmt.Fprintln(os.Stderr)
+			defer runStty("echo")
+		}
+	}
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && password != "") {
+		return "", fmt.Errorf("error reading the password: %w", err)
+	}
+	return strings.TrimRight(password, "\r\n"), nil
+}
+
+// runStty runs stty with the argument on the standard input.
+func runStty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// runResourceCommand runs a command on the resource.
+func runResourceCommand(resource *Resource, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("a command of %s is required, run 'a2ictl help' for usage", resource.Name)
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "list":
+		return runList(resource, args)
+	case "get":
+		return runGet(resource, args)
+	case "create":
+		return runCreate(resource, args)
+	case "update":
+		return runUpdate(resource, args)
+	}
+	if resource == rulesResource {
+		switch command {
+		case "mute", "unmute":
+			return runMute(command, args)
+		case "export":
+			return runExportRules(args)
+		case "import":
+			return runImportRules(args)
+		}
+	}
+	return fmt.Errorf("unknown command %q of %s, run 'a2ictl help' for usage", command, resource.Name)
+}
+
+// runList lists the resource with the filters, pagination and sorting of the API.
+func runList(resource *Resource, args []string) error {
+	opts := &Options{}
+	flags := newFlagSet(resource.Name+" list", opts)
+	filters := flags.StringArrayP("filter", "F", nil, "filter KEY=VALUE, e.g. status=actual; repeat a key to match several values")
+	page := flags.Int("page", 1, "page of the list")
+	pageSize := flags.Int("page-size", 10, "number of items per page")
+	sortBy := flags.String("sort-by", "", "field to sort by")
+	sortOrder := flags.String("sort-order", "", "sort order: asc or desc")
+	startTime := flags.String("start-time", "", "start of the creation time range, RFC3339")
+	endTime := flags.String("end-time", "", "end of the creation time range, RFC3339")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	client, _, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for _, filter := range *filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid filter %q, expected KEY=VALUE", filter)
+		}
+		query.Add(key, value)
+	}
+	query.Set("page", strconv.Itoa(*page))
+	query.Set("pageSize", strconv.Itoa(*pageSize))
+	setIfNotEmpty(query, "sortBy", *sortBy)
+	setIfNotEmpty(query, "sortOrder", *sortOrder)
+	setIfNotEmpty(query, "startTime", *startTime)
+	setIfNotEmpty(query, "endTime", *endTime)
+
+	response, err := client.RequestJSON(http.MethodGet, resource.Path+"/", query, nil, nil)
+	if err != nil {
+		return err
+	}
+	return printValue(os.Stdout, opts.Output, response, printListTable(resource.Name, resource.Columns))
+}
+
+// runGet prints a single item of the resource by ID.
+func runGet(resource *Resource, args []string) error {
+	opts := &Options{}
+	flags := newFlagSet(resource.Name+" get", opts)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("exactly one ID is required")
+	}
+
+	client, _, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	response, err := client.RequestJSON(http.MethodGet, resource.Path+"/"+url.PathEscape(flags.Arg(0)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return printValue(os.Stdout, opts.Output, response, printObjectTable)
+}
+
+// runCreate creates an item of the resource from a YAML or JSON file.
+func runCreate(resource *Resource, args []string) error {
+	opts := &Options{}
+	flags := newFlagSet(resource.Name+" create", opts)
+	file := flags.StringP("file", "f", "", "YAML or JSON file of the item; - for the standard input")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("the file of the item must be set with --file")
+	}
+
+	object, err := readObjectFile(*file)
+	if err != nil {
+		return err
+	}
+
+	client, _, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	response, err := client.RequestJSON(http.MethodPost, resource.Path+"/", nil, object, nil)
+	if err != nil {
+		return err
+	}
+	return printValue(os.Stdout, opts.Output, response, printObjectTable)
+}
+
+// runUpdate updates the fields of an item of the resource given by a YAML or JSON file and by the --set flags.
+func runUpdate(resource *Resource, args []string) error {
+	opts := &Options{}
+	flags := newFlagSet(resource.Name+" update", opts)
+	file := flags.StringP("file", "f", "", "YAML or JSON file of the fields to update; - for the standard input")
+	sets := flags.StringArray("set", nil, "field to update FIELD=VALUE; the value is parsed as YAML, e.g. labels=[db,net]")
+	ifMatch := flags.Int("if-match", 0, "version the item must still have, see the version field")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("exactly one ID is required")
+	}
+
+	object := map[string]interface{}{}
+	if *file != "" {
+		var err error
+		if object, err = readObjectFile(*file); err != nil {
+			return err
+		}
+	}
+	for _, set := range *sets {
+		field, value, ok := strings.Cut(set, "=")
+		if !ok || field == "" {
+			return fmt.Errorf("invalid --set %q, expected FIELD=VALUE", set)
+		}
+		object[field] = parseYAMLValue(value)
+	}
+	if len(object) == 0 {
+		return errors.New("the fields to update must be set with --file or --set")
+	}
+
+	client, _, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	if *ifMatch > 0 {
+		header.Set("If-Match", fmt.Sprintf(`"%d"`, *ifMatch))
+	}
+	response, err := client.RequestJSON(http.MethodPut, resource.Path+"/"+url.PathEscape(flags.Arg(0)), nil, object, header)
+	if err != nil {
+		return err
+	}
+	return printValue(os.Stdout, opts.Output, response, printObjectTable)
+}
+
+// runMute mutes or unmutes the rules by IDs with a single bulk action.
+func runMute(action string, args []string) error {
+	opts := &Options{}
+	flags := newFlagSet("rules "+action, opts)
+	until := new(string)
+	reason := new(string)
+	if action == "mute" {
+		until = flags.String("until", "", "time the rules are unmuted at: a duration from now, e.g. 2h, or RFC3339")
+		reason = flags.String("reason", "", "reason the rules are muted for")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("at least one rule ID is required")
+	}
+
+	client, session, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	request := map[string]interface{}{"ids": flags.Args(), "action": action}
+	if action == "mute" {
+		request["muted_by"] = session.UserName
+		if *reason != "" {
+			request["mute_reason"] = *reason
+		}
+		if *until != "" {
+			muteUntil, err := parseUntil(*until)
+			if err != nil {
+				return err
+			}
+			request["mute_until"] = muteUntil.UTC().Format(time.RFC3339)
+		}
+	}
+
+	response, err := client.RequestJSON(http.MethodPost, rulesResource.Path+"/bulk", nil, request, nil)
+	if err != nil {
+		return err
+	}
+	return printValue(os.Stdout, opts.Output, response, printListTable("results", bulkResultColumns))
+}
+
+// runExportRules exports all rules as a YAML or JSON document to a file or the standard output.
+func runExportRules(args []string) error {
+	opts := &Options{}
+	flags := newFlagSet("rules export", opts)
+	file := flags.StringP("file", "f", "-", "file to write the rules to; - for the standard output")
+	format := flags.String("format", "", "document format: yaml or json; by the extension of the file, yaml by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	client, _, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"format": {documentFormat(*format, *file)}}
+	data, _, err := client.Request(http.MethodGet, rulesResource.Path+"/export", query, nil, "", nil)
+	if err != nil {
+		return err
+	}
+
+	if *file == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*file, data, 0o644); err != nil {
+		return fmt.Errorf("error writing the rules: %w", err)
+	}
+	return nil
+}
+
+// runImportRules imports the rules of a YAML or JSON document, printing the changes made or planned.
+func runImportRules(args []string) error {
+	opts := &Options{}
+	flags := newFlagSet("rules import", opts)
+	file := flags.StringP("file", "f", "", "file to read the rules from; - for the standard input")
+	format := flags.String("format", "", "document format: yaml or json; by the extension of the file, yaml by default")
+	dryRun := flags.Bool("dry-run", false, "only show the changes without making them")
+	prune := flags.Bool("prune", false, "delete the rules absent from the document")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("the file of the rules must be set with --file")
+	}
+
+	data, err := readFile(*file)
+	if err != nil {
+		return err
+	}
+
+	client, _, err := opts.authorizedClient()
+	if err != nil {
+		return err
+	}
+
+	documentFormat := documentFormat(*format, *file)
+	query := url.Values{
+		"format":  {documentFormat},
+		"dry_run": {strconv.FormatBool(*dryRun)},
+		"prune":   {strconv.FormatBool(*prune)},
+	}
+	responseData, _, err := client.Request(http.MethodPost, rulesResource.Path+"/import", query,
+		bytes.NewReader(data), "application/"+documentFormat, nil)
+	if err != nil {
+		return err
+	}
+	response, err := decodeJSON(responseData)
+	if err != nil {
+		return err
+	}
+
+	return printValue(os.Stdout, opts.Output, response, func(w io.Writer, value interface{}) {
+		printListTable("changes", []string{"id", "action", "fields"})(w, value)
+		result, _ := value.(map[string]interface{})
+		fmt.Fprintf(w, "\nUnchanged: %s\n", formatCell(result["unchanged"]))
+		if *dryRun {
+			fmt.Fprintln(w, "Dry run: the changes haven't been made")
+		}
+	})
+}
+
+// documentFormat returns the format of a rules document: the given one or the one of the extension of the file, yaml by default.
+func documentFormat(format, file string) string {
+	if format != "" {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// readFile reads a file or, for "-", the standard input.
+func readFile(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the standard input: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the file: %w", err)
+	}
+	return data, nil
+}
+
+// readObjectFile reads an object from a YAML or JSON file.
+func readObjectFile(path string) (map[string]interface{}, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is YAML, so both are decoded the same way.
+	object := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return object, nil
+}
+
+// parseYAMLValue parses the value of a --set flag as YAML, so numbers, booleans and lists keep their types;
+// the value is used as a string if it isn't valid YAML.
+func parseYAMLValue(value string) interface{} {
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+	return parsed
+}
+
+// parseUntil parses the end of a mute: a duration from now or an RFC3339 time.
+func parseUntil(value string) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(duration), nil
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --until %q, expected a duration or an RFC3339 time", value)
+	}
+	return until, nil
+}
+
+// setIfNotEmpty sets the query parameter unless the value is empty.
+func setIfNotEmpty(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,77 @@
+package main // dnywonnt.me/alerts2incidents/cmd/a2ictl
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+// usage is the help of a2ictl.
+const usage = `a2ictl is a command-line client of the alerts2incidents REST API.
+
+Usage:
+  a2ictl login --server URL [--user LOGIN] [--password-stdin]
+  a2ictl logout
+
+  a2ictl incidents list [--filter KEY=VALUE]... [--page N] [--page-size N] [--sort-by FIELD] [--sort-order asc|desc]
+                        [--start-time RFC3339 --end-time RFC3339]
+  a2ictl incidents get ID
+  a2ictl incidents create --file FILE
+  a2ictl incidents update ID [--file FILE] [--set FIELD=VALUE]... [--if-match VERSION]
+
+  a2ictl rules list|get|create|update ...   the same as for incidents
+  a2ictl rules mute ID... [--until DURATION|RFC3339] [--reason TEXT]
+  a2ictl rules unmute ID...
+  a2ictl rules export [--file FILE] [--format yaml|json]
+  a2ictl rules import --file FILE [--format yaml|json] [--dry-run] [--prune]
+
+Common flags:
+  --server URL        API server, e.g. http://alerts2incidents:8080; $A2ICTL_SERVER or the server logged in to by default
+  -o, --output FORMAT output format: table, json or yaml (default table)
+  --timeout DURATION  timeout of the requests (default 30s)
+
+The filters are the query parameters of the lists of the API, e.g. --filter status=actual --filter labels=db.
+The files of the objects are YAML or JSON with the fields of the API; FILE "-" is the standard input.
+The session is cached in a2ictl/session.json of the user config directory or in $A2ICTL_SESSION.
+Run 'a2ictl COMMAND --help' for the flags of a command.
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "a2ictl:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command of the arguments.
+func run(args []string) error {
+	if len(args) == 0 {
+		fmt.Print(usage)
+		return nil
+	}
+
+	var err error
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	case "login":
+		err = runLogin(args[1:])
+	case "logout":
+		err = runLogout(args[1:])
+	case "incidents":
+		err = runResourceCommand(incidentsResource, args[1:])
+	case "rules":
+		err = runResourceCommand(rulesResource, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, run 'a2ictl help' for usage", args[0])
+	}
+
+	// The help of a command has been printed by its flags.
+	if errors.Is(err, pflag.ErrHelp) {
+		return nil
+	}
+	return err
+}
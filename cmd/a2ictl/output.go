@@ -0,0 +1,166 @@
+package main // dnywonnt.me/alerts2incidents/cmd/a2ictl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats of a2ictl.
+const (
+	TableOutput = "table"
+	JSONOutput  = "json"
+	YAMLOutput  = "yaml"
+)
+
+// maxCellWidth is the number of characters the values are truncated to in the list tables.
+const maxCellWidth = 60
+
+// Columns of the list tables of incidents and rules.
+var (
+	incidentColumns = []string{"id", "status", "severity", "type", "summary", "from_at", "to_at", "is_confirmed"}
+	ruleColumns     = []string{"id", "is_muted", "set_incident_severity", "set_incident_summary", "description"}
+)
+
+// checkOutputFormat checks that the output format is known.
+func checkOutputFormat(format string) error {
+	switch format {
+	case TableOutput, JSONOutput, YAMLOutput:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected one of: table, json, yaml", format)
+}
+
+// printValue prints a decoded JSON value as JSON or YAML; printTable is used for the table format.
+func printValue(w io.Writer, format string, value interface{}, printTable func(io.Writer, interface{})) error {
+	switch format {
+	case JSONOutput:
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling the output: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case YAMLOutput:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(toYAMLValue(value)); err != nil {
+			return fmt.Errorf("error marshaling the output: %w", err)
+		}
+		return encoder.Close()
+	}
+
+	printTable(w, value)
+	return nil
+}
+
+// printListTable returns a table printer of the items of a list response under the key with the columns,
+// followed by the page of the list.
+func printListTable(key string, columns []string) func(io.Writer, interface{}) {
+	return func(w io.Writer, value interface{}) {
+		response, _ := value.(map[string]interface{})
+		items, _ := response[key].([]interface{})
+
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			object, _ := item.(map[string]interface{})
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				row[i] = truncate(formatCell(object[column]), maxCellWidth)
+			}
+			rows = append(rows, row)
+		}
+		writeTable(w, columns, rows)
+
+		if totalPages, ok := response["total_pages"]; ok {
+			fmt.Fprintf(w, "\nPage %s of %s\n", formatCell(response["current_page"]), formatCell(totalPages))
+		}
+	}
+}
+
+// printObjectTable prints the fields of an object as a two-column table, sorted by name.
+func printObjectTable(w io.Writer, value interface{}) {
+	object, _ := value.(map[string]interface{})
+
+	fields := make([]string, 0, len(object))
+	for field := range object {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	rows := make([][]string, len(fields))
+	for i, field := range fields {
+		rows[i] = []string{field, formatCell(object[field])}
+	}
+	writeTable(w, []string{"FIELD", "VALUE"}, rows)
+}
+
+// writeTable writes the rows as a table aligned by columns under the upper-cased header.
+func writeTable(w io.Writer, header []string, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
+
+// formatCell formats a decoded JSON value as a single-line table cell.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatCell(item)
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// truncate truncates the text to the number of characters, marking the cut with an ellipsis.
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// toYAMLValue converts the json.Number values of a decoded JSON value to YAML numbers,
+// as they would be encoded as strings otherwise.
+func toYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}
+		if _, err := v.Int64(); err != nil {
+			node.Tag = "!!float"
+		}
+		return node
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = toYAMLValue(item)
+		}
+		return items
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = toYAMLValue(item)
+		}
+		return object
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package main // dnywonnt.me/alerts2incidents/cmd/a2ictl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Session is the login session of a2ictl cached between its runs.
+type Session struct {
+	Server    string    `json:"server"`     // Base URL of the API server, e.g. http://alerts2incidents:8080
+	UserName  string    `json:"user_name"`  // Name of the logged in LDAP user
+	Token     string    `json:"token"`      // JWT token of the API
+	ExpiresAt time.Time `json:"expires_at"` // Time the token expires at
+}
+
+// sessionPath returns the path of the cached session: $A2ICTL_SESSION or a2ictl/session.json in the user config directory.
+func sessionPath() (string, error) {
+	if path := os.Getenv("A2ICTL_SESSION"); path != "" {
+		return path, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating the user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "a2ictl", "session.json"), nil
+}
+
+// loadSession loads the cached session; it returns nil if there is none.
+func loadSession() (*Session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading the session: %w", err)
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return nil, fmt.Errorf("error parsing the session %s: %w", path, err)
+	}
+	return session, nil
+}
+
+// saveSession caches the session, readable by the current user only, as it holds the token.
+func saveSession(session *Session) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling the session: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating the session directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing the session: %w", err)
+	}
+	return nil
+}
+
+// deleteSession removes the cached session, if any.
+func deleteSession() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error removing the session: %w", err)
+	}
+	return nil
+}
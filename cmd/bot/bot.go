@@ -1,18 +1,15 @@
 package main // dnywonnt.me/alerts2incidents/cmd/bot
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
 	"strings"
 	"syscall"
-	"text/template"
 	"time"
 
 	"dnywonnt.me/alerts2incidents/internal/cache"
@@ -20,6 +17,9 @@ import (
 	"dnywonnt.me/alerts2incidents/internal/database"
 	"dnywonnt.me/alerts2incidents/internal/database/repositories"
 	"dnywonnt.me/alerts2incidents/internal/models"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+	"dnywonnt.me/alerts2incidents/internal/notifier/impl"
+	"dnywonnt.me/alerts2incidents/internal/oncall"
 	"dnywonnt.me/alerts2incidents/internal/utils"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -34,8 +34,23 @@ type Bot struct {
 	dbPool        *pgxpool.Pool
 	tgo           *telego.Bot
 	incidentsRepo *repositories.IncidentsRepository
+	eventsRepo    *repositories.IncidentEventsRepository
+	policiesRepo  *repositories.EscalationPoliciesRepository
+	slosRepo      *repositories.SLOsRepository
+	onCall        *oncall.Resolver
 	messageCache  *cache.Cache
-	messageTmpl   *template.Template
+	messageTmpls  map[string]*notifier.TemplateStore
+	digestTmpls   map[string]*notifier.TemplateStore
+	digestSched   *utils.CronSchedule
+	sloTmpls      map[string]*notifier.TemplateStore
+	notifiers     []notifier.Notifier
+	emailNotifier *impl.EmailNotifier
+}
+
+// sentMessage represents a message sent for an incident together with the locale it was rendered in
+type sentMessage struct {
+	message *telego.Message
+	locale  string
 }
 
 // InitializeBot initializes and returns a new Bot instance
@@ -69,13 +84,73 @@ func InitializeBot() *Bot {
 		}).Fatal("Failed to create database pool")
 	}
 
-	// Load message template from file
-	tmpl, err := loadMessageTemplateFromFile(tgConfig.MessageTemplateFilepath)
+	// Load additional notifiers configuration
+	notifiersConfig, err := config.LoadNotifiersConfig()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Fatal("Failed to load notifiers config")
+	}
+
+	// Load message templates of each locale from directory
+	messageTmpls, err := notifier.LoadLocaleTemplateStores(tgConfig.MessageTemplatesDirpath, &models.Incident{})
 	if err != nil {
 		log.WithFields(log.Fields{
-			"error":    err.Error(),
-			"filePath": tgConfig.MessageTemplateFilepath,
-		}).Fatal("Failed to load message template file")
+			"error":   err.Error(),
+			"dirPath": tgConfig.MessageTemplatesDirpath,
+		}).Fatal("Failed to load message templates directory")
+	}
+
+	// Ensure the templates exist for the locale of each chat
+	if err := validateChatLocales(tgConfig.Chats, tgConfig.MessageDefaultLocale, messageTmpls); err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Fatal("Failed to validate the chats")
+	}
+
+	// Load digest schedule and templates of each locale, if the digests are active
+	var digestTmpls map[string]*notifier.TemplateStore
+	var digestSched *utils.CronSchedule
+	if tgConfig.DigestIsActive {
+		digestSched, err = utils.ParseCronSchedule(tgConfig.DigestSchedule)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":    err.Error(),
+				"schedule": tgConfig.DigestSchedule,
+			}).Fatal("Failed to parse digest schedule")
+		}
+
+		digestTmpls, err = notifier.LoadLocaleTemplateStores(tgConfig.DigestTemplatesDirpath, &notifier.Digest{})
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"dirPath": tgConfig.DigestTemplatesDirpath,
+			}).Fatal("Failed to load digest templates directory")
+		}
+
+		if err := validateChatLocales(tgConfig.DigestChats, tgConfig.MessageDefaultLocale, digestTmpls); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to validate the digest chats")
+		}
+	}
+
+	// Load error budget alert templates of each locale, if the alerts are active
+	var sloTmpls map[string]*notifier.TemplateStore
+	if tgConfig.SLOIsActive {
+		sloTmpls, err = notifier.LoadLocaleTemplateStores(tgConfig.SLOTemplatesDirpath, &models.SLOStatus{SLO: &models.SLO{}})
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"dirPath": tgConfig.SLOTemplatesDirpath,
+			}).Fatal("Failed to load SLO templates directory")
+		}
+
+		if err := validateChatLocales(tgConfig.SLOChats, tgConfig.MessageDefaultLocale, sloTmpls); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to validate the SLO chats")
+		}
 	}
 
 	// Create a new Telegram bot instance
@@ -86,14 +161,61 @@ func InitializeBot() *Bot {
 		}).Fatal("Failed to create Telegram bot instance")
 	}
 
+	// Create the resolver of the "oncall:<schedule>" targets
+	onCall := oncall.NewResolver(
+		repositories.NewOnCallUsersRepository(dbPool),
+		repositories.NewOnCallSchedulesRepository(dbPool),
+		repositories.NewOnCallOverridesRepository(dbPool),
+	)
+
+	// Create the active additional notifiers
+	notifiers := []notifier.Notifier{}
+	var emailNotifier *impl.EmailNotifier
+	if notifiersConfig.Email.IsActive {
+		emailNotifier, err = impl.NewEmailNotifier(notifiersConfig.Email, onCall)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to create email notifier")
+		}
+		notifiers = append(notifiers, emailNotifier)
+	}
+	if notifiersConfig.Mattermost.IsActive {
+		mattermostNotifier, err := impl.NewMattermostNotifier(notifiersConfig.Mattermost)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to create Mattermost notifier")
+		}
+		notifiers = append(notifiers, mattermostNotifier)
+	}
+	if notifiersConfig.Teams.IsActive {
+		teamsNotifier, err := impl.NewTeamsNotifier(notifiersConfig.Teams)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Fatal("Failed to create Teams notifier")
+		}
+		notifiers = append(notifiers, teamsNotifier)
+	}
+
 	// Return a new Bot instance
 	return &Bot{
 		cfg:           tgConfig,
 		dbPool:        dbPool,
 		tgo:           tgo,
 		incidentsRepo: repositories.NewIncidentsRepository(dbPool),
+		eventsRepo:    repositories.NewIncidentEventsRepository(dbPool),
+		policiesRepo:  repositories.NewEscalationPoliciesRepository(dbPool),
+		slosRepo:      repositories.NewSLOsRepository(dbPool),
+		onCall:        onCall,
 		messageCache:  cache.NewCache(tgConfig.MessageCacheMaxSize, "messages"),
-		messageTmpl:   tmpl,
+		messageTmpls:  messageTmpls,
+		digestTmpls:   digestTmpls,
+		digestSched:   digestSched,
+		sloTmpls:      sloTmpls,
+		notifiers:     notifiers,
+		emailNotifier: emailNotifier,
 	}
 }
 
@@ -106,6 +228,32 @@ func (bot *Bot) Run() {
 	// Create a context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// Reload message templates when their files change
+	for _, tmpls := range bot.messageTmpls {
+		go tmpls.Watch(ctx)
+	}
+
+	// Start sending digests on schedule
+	if bot.cfg.DigestIsActive {
+		for _, tmpls := range bot.digestTmpls {
+			go tmpls.Watch(ctx)
+		}
+		go bot.runDigests(ctx)
+	}
+
+	// Start escalating the unconfirmed auto incidents
+	if bot.cfg.EscalationIsActive {
+		go bot.runEscalations(ctx)
+	}
+
+	// Start alerting about the burned error budgets
+	if bot.cfg.SLOIsActive {
+		for _, tmpls := range bot.sloTmpls {
+			go tmpls.Watch(ctx)
+		}
+		go bot.runSLOChecks(ctx)
+	}
+
 	// Start listening to database notifications
 	go database.ListenToNotifications(ctx, bot.dbPool, database.IncidentsChannel, func(notification *pgconn.Notification) {
 		if err := bot.handleMessagesForNotification(ctx, notification); err != nil {
@@ -137,53 +285,66 @@ func (bot *Bot) Run() {
 // handleMessagesForNotification processes notifications from the database
 func (bot *Bot) handleMessagesForNotification(ctx context.Context, notification *pgconn.Notification) error {
 	// Split the notification payload into action and ID
-	parts := strings.SplitN(notification.Payload, ":", 2)
-	if len(parts) < 2 {
-		return fmt.Errorf("invalid payload in notification: %s", notification.Payload)
+	action, id, err := notifier.ParseEventPayload(notification.Payload)
+	if err != nil {
+		return err
 	}
 
-	action, id := parts[0], parts[1]
+	event := &notifier.Event{
+		Action:     action,
+		IncidentID: id,
+	}
 
 	// Handle different actions based on the notification
 	switch action {
-	case "INSERT", "UPDATE":
+	case notifier.InsertAction, notifier.UpdateAction:
 		// Fetch the incident details from the repository
 		incident, err := bot.incidentsRepo.GetIncident(ctx, id)
 		if err != nil {
 			return fmt.Errorf("error getting incident: %w", err)
 		}
-
-		// Render the incident to message text
-		text, err := bot.renderIncidentToMessageText(incident)
-		if err != nil {
-			return fmt.Errorf("error rendering incident model to message text: %w", err)
-		}
+		event.Incident = incident
 
 		// Send or update messages based on the action
-		if action == "INSERT" {
-			bot.sendMessagesForIncident(incident.ID, text)
-		} else if action == "UPDATE" {
-			bot.updateMessagesForIncident(incident.ID, text)
+		if action == notifier.InsertAction {
+			bot.sendMessagesForIncident(ctx, incident)
+		} else {
+			bot.updateMessagesForIncident(incident)
 		}
 
-	case "DELETE":
+	case notifier.DeleteAction:
 		// Handle delete messages
 		bot.deleteMessagesForIncident(id)
-
-	default:
-		return fmt.Errorf("unknown action: %s", action)
 	}
 
+	// Pass the incident change to the additional notifiers
+	bot.notifyNotifiers(ctx, event)
+
 	return nil
 }
 
-// sendMessagesForIncident sends messages to all configured chats
-func (bot *Bot) sendMessagesForIncident(incidentID, text string) {
-	messages := []*telego.Message{}
+// notifyNotifiers passes the incident change to each of the additional notifiers
+func (bot *Bot) notifyNotifiers(ctx context.Context, event *notifier.Event) {
+	for _, n := range bot.notifiers {
+		if err := n.Notify(ctx, event); err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"notifier":   n.Name(),
+				"incidentID": event.IncidentID,
+			}).Error("Failed to notify about the incident change")
+		}
+	}
+}
+
+// sendMessagesForIncident sends messages to all configured chats in the locale of each chat
+func (bot *Bot) sendMessagesForIncident(ctx context.Context, incident *models.Incident) {
+	incidentID := incident.ID
+	messages := []*sentMessage{}
+	texts := make(map[string]string)
 
 	for _, chatStr := range bot.cfg.Chats {
-		// Parse chat ID and thread ID from configuration
-		chatID, threadID, err := parseChatStr(chatStr)
+		// Parse chat ID, thread ID and locale from configuration, resolving the on-call user if needed
+		chatID, threadID, locale, err := bot.resolveAndParseChatStr(ctx, chatStr)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"error":   err.Error(),
@@ -192,18 +353,19 @@ func (bot *Bot) sendMessagesForIncident(incidentID, text string) {
 			continue
 		}
 
-		// Prepare parameters for sending the message
-		sendParams := &telego.SendMessageParams{
-			ChatID:    telego.ChatID{ID: chatID},
-			Text:      text,
-			ParseMode: bot.cfg.MessageParseMode,
-		}
-		if threadID != nil {
-			sendParams.MessageThreadID = *threadID
+		// Render the incident to message text once per locale
+		text, err := bot.renderIncidentToMessageText(incident, locale, texts)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"incidentID": incidentID,
+				"locale":     locale,
+			}).Error("Failed to render incident model to message text")
+			continue
 		}
 
 		// Send the message
-		msg, err := bot.tgo.SendMessage(sendParams)
+		msg, err := bot.sendMessageToChat(chatID, threadID, text)
 		if err != nil {
 			logFields := log.Fields{
 				"error":  err.Error(),
@@ -225,7 +387,7 @@ func (bot *Bot) sendMessagesForIncident(incidentID, text string) {
 		}
 		log.WithFields(logFields).Info("The message has been sent to the chat for incident")
 
-		messages = append(messages, msg)
+		messages = append(messages, &sentMessage{message: msg, locale: locale})
 
 		// Adding a delay between requests to prevent exceeding rate limits
 		time.Sleep(bot.cfg.RequestDelay)
@@ -237,8 +399,25 @@ func (bot *Bot) sendMessagesForIncident(incidentID, text string) {
 	}
 }
 
-// updateMessagesForIncident updates messages for an existing incident
-func (bot *Bot) updateMessagesForIncident(incidentID, text string) {
+// sendMessageToChat sends a message to the chat and, if set, to the thread of the chat
+func (bot *Bot) sendMessageToChat(chatID int64, threadID *int, text string) (*telego.Message, error) {
+	sendParams := &telego.SendMessageParams{
+		ChatID:    telego.ChatID{ID: chatID},
+		Text:      text,
+		ParseMode: bot.cfg.MessageParseMode,
+	}
+	if threadID != nil {
+		sendParams.MessageThreadID = *threadID
+	}
+
+	return bot.tgo.SendMessage(sendParams)
+}
+
+// updateMessagesForIncident updates messages for an existing incident in the locale they were sent in
+func (bot *Bot) updateMessagesForIncident(incident *models.Incident) {
+	incidentID := incident.ID
+	texts := make(map[string]string)
+
 	item, exists := bot.messageCache.GetItem(incidentID)
 	if !exists {
 		log.WithFields(log.Fields{
@@ -246,13 +425,25 @@ func (bot *Bot) updateMessagesForIncident(incidentID, text string) {
 		}).Warn("The messages not found in the cache for incident; skipping update")
 		return
 	}
-	messages, ok := item.Value.([]*telego.Message)
+	messages, ok := item.Value.([]*sentMessage)
 	if !ok {
 		return
 	}
 
 	// Update each message in the chat
-	for _, msg := range messages {
+	for _, sent := range messages {
+		msg := sent.message
+
+		text, err := bot.renderIncidentToMessageText(incident, sent.locale, texts)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"incidentID": incidentID,
+				"locale":     sent.locale,
+			}).Error("Failed to render incident model to message text")
+			continue
+		}
+
 		if _, err := bot.tgo.EditMessageText(&telego.EditMessageTextParams{
 			ChatID:    telego.ChatID{ID: msg.Chat.ID},
 			MessageID: msg.MessageID,
@@ -289,13 +480,14 @@ func (bot *Bot) deleteMessagesForIncident(incidentID string) {
 	}
 	defer bot.messageCache.DeleteItem(incidentID)
 
-	messages, ok := item.Value.([]*telego.Message)
+	messages, ok := item.Value.([]*sentMessage)
 	if !ok {
 		return
 	}
 
 	// Delete each message in the chat
-	for _, msg := range messages {
+	for _, sent := range messages {
+		msg := sent.message
 		if err := bot.tgo.DeleteMessage(&telego.DeleteMessageParams{
 			ChatID:    telego.ChatID{ID: msg.Chat.ID},
 			MessageID: msg.MessageID,
@@ -319,63 +511,194 @@ func (bot *Bot) deleteMessagesForIncident(incidentID string) {
 	}
 }
 
-// renderIncidentToMessageText renders an incident to a message text using the template
-func (bot *Bot) renderIncidentToMessageText(incident *models.Incident) (string, error) {
-	buf := bytes.Buffer{}
-	if err := bot.messageTmpl.Execute(&buf, incident); err != nil {
-		return "", err
-	}
+// runDigests sends the digests on schedule until the context is cancelled
+func (bot *Bot) runDigests(ctx context.Context) {
+	for {
+		next := bot.digestSched.Next(time.Now())
+		if next.IsZero() {
+			log.WithFields(log.Fields{
+				"schedule": bot.cfg.DigestSchedule,
+			}).Error("The digest schedule never fires; digests won't be sent")
+			return
+		}
 
-	return buf.String(), nil
+		log.WithFields(log.Fields{
+			"nextRun": next,
+		}).Debug("Waiting for the next digest")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := bot.sendDigest(ctx, next); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to send the digest")
+		}
+	}
 }
 
-// loadMessageTemplateFromFile loads the message template from a file
-func loadMessageTemplateFromFile(filepath string) (*template.Template, error) {
-	tmpl := template.New("messageTemplate").Funcs(template.FuncMap{
-		"joinWithCommas":      utils.JoinWithCommas,
-		"escapeMDV2":          utils.EscapeMarkdownV2,
-		"derefStr":            utils.DerefStr,
-		"prettyJSON":          utils.PrettyJSON,
-		"formatNumWithCommas": utils.FormatNumberWithCommas,
-	})
+// sendDigest sends the digest of the window ending at the given time to all configured digest chats
+func (bot *Bot) sendDigest(ctx context.Context, to time.Time) error {
+	from := to.Add(-bot.cfg.DigestWindow)
 
-	file, err := os.Open(filepath)
+	incidents, err := bot.incidentsRepo.GetIncidentsForDigest(ctx, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
+		return fmt.Errorf("error getting incidents: %w", err)
 	}
-	defer file.Close()
+	digest := notifier.BuildDigest(from, to, incidents)
 
-	content, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+	texts := make(map[string]string)
+	for _, chatStr := range bot.cfg.DigestChats {
+		// Parse chat ID, thread ID and locale from configuration, resolving the on-call user if needed
+		chatID, threadID, locale, err := bot.resolveAndParseChatStr(ctx, chatStr)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"chatStr": chatStr,
+			}).Error("Failed to parse chat string")
+			continue
+		}
+
+		// Render the digest once per locale
+		text, ok := texts[locale]
+		if !ok {
+			text, err = bot.digestTmpls[locale].RenderDefault(digest)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":  err.Error(),
+					"locale": locale,
+				}).Error("Failed to render the digest to message text")
+				continue
+			}
+			texts[locale] = text
+		}
+
+		logFields := log.Fields{
+			"chatID": chatID,
+		}
+		if threadID != nil {
+			logFields["threadID"] = *threadID
+		}
+
+		if _, err := bot.sendMessageToChat(chatID, threadID, text); err != nil {
+			logFields["error"] = err.Error()
+			log.WithFields(logFields).Error("Failed to send the digest to the chat")
+			continue
+		}
+		log.WithFields(logFields).Info("The digest has been sent to the chat")
+
+		// Adding a delay between requests to prevent exceeding rate limits
+		time.Sleep(bot.cfg.RequestDelay)
+	}
+
+	return nil
+}
+
+// renderIncidentToMessageText renders an incident to a message text in the locale using the template selected for it.
+// The rendered texts are memoized by locale, so each locale is rendered once per incident change
+func (bot *Bot) renderIncidentToMessageText(incident *models.Incident, locale string, texts map[string]string) (string, error) {
+	if text, ok := texts[locale]; ok {
+		return text, nil
+	}
+
+	tmpls, ok := bot.messageTmpls[locale]
+	if !ok {
+		return "", fmt.Errorf("no message templates for locale %s", locale)
 	}
 
-	tmpl, err = tmpl.Parse(string(content))
+	text, err := tmpls.Render(incident)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing file: %w", err)
+		return "", err
+	}
+	texts[locale] = text
+
+	return text, nil
+}
+
+// validateChatLocales ensures that the chat strings are valid and the templates exist for the locale of each chat
+func validateChatLocales(chats []string, defaultLocale string, tmpls map[string]*notifier.TemplateStore) error {
+	for _, chatStr := range chats {
+		// The on-call user is only known when sending, so a placeholder chat ID is checked instead
+		if _, localeSuffix, ok := splitOnCallChatStr(chatStr); ok {
+			chatStr = "0" + localeSuffix
+		}
+
+		_, _, locale, err := parseChatStr(chatStr, defaultLocale)
+		if err != nil {
+			return fmt.Errorf("error parsing chat string %s: %w", chatStr, err)
+		}
+		if _, ok := tmpls[locale]; !ok {
+			return fmt.Errorf("no templates found for locale %s of chat %s", locale, chatStr)
+		}
+	}
+	return nil
+}
+
+// resolveAndParseChatStr parses a chat string like parseChatStr, replacing the "oncall:<schedule>[@Locale]" chat string
+// with the Telegram ID of the user currently on call for the schedule, so the user is messaged directly
+func (bot *Bot) resolveAndParseChatStr(ctx context.Context, chatStr string) (int64, *int, string, error) {
+	if scheduleName, localeSuffix, ok := splitOnCallChatStr(chatStr); ok {
+		user, err := bot.onCall.ResolveOnCallUser(ctx, scheduleName)
+		if err != nil {
+			return 0, nil, "", fmt.Errorf("error resolving on-call user: %w", err)
+		}
+		if user.TelegramID == 0 {
+			return 0, nil, "", fmt.Errorf("on-call user %s has no Telegram ID", user.ID)
+		}
+		chatStr = fmt.Sprintf("%d%s", user.TelegramID, localeSuffix)
+	}
+
+	return parseChatStr(chatStr, bot.cfg.MessageDefaultLocale)
+}
+
+// splitOnCallChatStr splits an "oncall:<schedule>[@Locale]" chat string into the schedule name and the locale suffix.
+// It reports false if the chat string isn't an on-call one
+func splitOnCallChatStr(chatStr string) (string, string, bool) {
+	if !strings.HasPrefix(chatStr, notifier.OnCallTargetPrefix) {
+		return "", "", false
 	}
 
-	return tmpl, nil
+	scheduleName := strings.TrimPrefix(chatStr, notifier.OnCallTargetPrefix)
+	localeSuffix := ""
+	if at := strings.LastIndex(scheduleName, "@"); at != -1 {
+		scheduleName, localeSuffix = scheduleName[:at], scheduleName[at:]
+	}
+	return scheduleName, localeSuffix, true
 }
 
-// parseChatStr parses a chat string into chat ID and thread ID
-func parseChatStr(chatStr string) (int64, *int, error) {
+// parseChatStr parses a chat string in the "ChatID[:ThreadID][@Locale]" format into chat ID, thread ID and locale.
+// The default locale is returned if the chat string doesn't declare one
+func parseChatStr(chatStr, defaultLocale string) (int64, *int, string, error) {
+	locale := defaultLocale
+	if at := strings.LastIndex(chatStr, "@"); at != -1 {
+		locale = chatStr[at+1:]
+		chatStr = chatStr[:at]
+		if locale == "" {
+			return 0, nil, "", errors.New("invalid chat string format: empty locale")
+		}
+	}
+
 	parts := strings.SplitN(chatStr, ":", 2)
 	if len(parts) < 1 {
-		return 0, nil, errors.New("invalid chat string format")
+		return 0, nil, "", errors.New("invalid chat string format")
 	}
 	chatID, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		return 0, nil, fmt.Errorf("error parsing chatID string to int64: %w", err)
+		return 0, nil, "", fmt.Errorf("error parsing chatID string to int64: %w", err)
 	}
 
 	var threadID *int
 	if len(parts) == 2 {
 		id, err := strconv.Atoi(parts[1])
 		if err != nil {
-			return 0, nil, fmt.Errorf("error parsing threadID string to int: %w", err)
+			return 0, nil, "", fmt.Errorf("error parsing threadID string to int: %w", err)
 		}
 		threadID = &id
 	}
-	return chatID, threadID, nil
+	return chatID, threadID, locale, nil
 }
@@ -0,0 +1,220 @@
+package main // dnywonnt.me/alerts2incidents/cmd/bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/google/uuid"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// escalationTemplateName is the name of the optional message template used for the escalation messages
+	escalationTemplateName = "escalation"
+	// escalationEventCreator is the creator of the escalation events
+	escalationEventCreator = "escalator"
+)
+
+// escalationEventData represents the details of an executed escalation step stored in the incident event
+type escalationEventData struct {
+	PolicyID string   `json:"policy_id"` // Escalation policy the step belongs to
+	Level    int      `json:"level"`     // Number of the step, starting from 1
+	After    string   `json:"after"`     // Delay of the step since the incident creation
+	Chats    []string `json:"chats"`     // Telegram chats notified by the step
+	Emails   []string `json:"emails"`    // Email addresses notified by the step
+}
+
+// runEscalations checks the unconfirmed auto incidents on every interval until the context is cancelled
+func (bot *Bot) runEscalations(ctx context.Context) {
+	log.WithFields(log.Fields{
+		"checkInterval": bot.cfg.EscalationCheckInterval.String(),
+	}).Info("Starting escalating the unconfirmed auto incidents")
+
+	ticker := time.NewTicker(bot.cfg.EscalationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := bot.checkEscalations(ctx); err != nil {
+				log.WithFields(log.Fields{
+					"error": err.Error(),
+				}).Error("Failed to check the escalations")
+			}
+		}
+	}
+}
+
+// checkEscalations executes the due escalation steps of the unconfirmed auto incidents.
+// The executed steps are counted by the escalation events of the incident, so a step runs once even across restarts,
+// and the confirmed incidents aren't selected anymore, which stops their escalation
+func (bot *Bot) checkEscalations(ctx context.Context) error {
+	policies, err := bot.policiesRepo.GetEscalationPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting escalation policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	// Index the policies by their rule and departament
+	ruleIDPolicies := make(map[string]*models.EscalationPolicy)
+	departamentPolicies := make(map[string]*models.EscalationPolicy)
+	for _, policy := range policies {
+		if policy.RuleID != nil {
+			ruleIDPolicies[*policy.RuleID] = policy
+		} else if policy.Departament != nil {
+			departamentPolicies[*policy.Departament] = policy
+		}
+	}
+
+	incidents, err := bot.incidentsRepo.GetUnconfirmedAutoIncidents(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting unconfirmed auto incidents: %w", err)
+	}
+
+	for _, incident := range incidents {
+		// A policy of the rule takes precedence over a policy of the departament
+		policy := departamentPolicies[incident.Departament]
+		if incident.RuleID != nil {
+			if rulePolicy, ok := ruleIDPolicies[*incident.RuleID]; ok {
+				policy = rulePolicy
+			}
+		}
+		if policy == nil {
+			continue
+		}
+
+		// A failure with an incident doesn't stop the escalation of the others; it's retried on the next check
+		executedSteps, err := bot.eventsRepo.CountIncidentEvents(ctx, incident.ID, models.EscalationEventType)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"incidentID": incident.ID,
+			}).Error("Failed to count the escalation events of the incident")
+			continue
+		}
+
+		elapsed := time.Since(incident.CreatedAt)
+		for level := executedSteps; level < len(policy.Steps) && elapsed >= policy.Steps[level].After; level++ {
+			if err := bot.executeEscalationStep(ctx, incident, policy, level); err != nil {
+				// The next steps wait for this one to be recorded, so they don't run out of order
+				log.WithFields(log.Fields{
+					"error":      err.Error(),
+					"incidentID": incident.ID,
+					"level":      level + 1,
+				}).Error("Failed to execute the escalation step")
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// executeEscalationStep notifies the chats and emails of the step about the incident and records the step as an incident event.
+// Failures to notify a single target are logged, since the step must be recorded anyway to avoid notifying the others again
+func (bot *Bot) executeEscalationStep(ctx context.Context, incident *models.Incident, policy *models.EscalationPolicy, level int) error {
+	step := policy.Steps[level]
+
+	logFields := log.Fields{
+		"incidentID": incident.ID,
+		"policyID":   policy.ID,
+		"level":      level + 1,
+	}
+	log.WithFields(logFields).Info("Escalating the unconfirmed incident")
+
+	// Notify the chats in the locale of each chat
+	texts := make(map[string]string)
+	for _, chatStr := range step.Chats {
+		chatID, threadID, locale, err := bot.resolveAndParseChatStr(ctx, chatStr)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"chatStr": chatStr,
+			}).Error("Failed to parse chat string of the escalation step")
+			continue
+		}
+
+		text, ok := texts[locale]
+		if !ok {
+			tmpls, ok := bot.messageTmpls[locale]
+			if !ok {
+				log.WithFields(log.Fields{
+					"chatStr": chatStr,
+					"locale":  locale,
+				}).Error("No message templates found for the chat locale of the escalation step")
+				continue
+			}
+			text, err = tmpls.RenderNamed(escalationTemplateName, incident)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":  err.Error(),
+					"locale": locale,
+				}).Error("Failed to render the escalation message text")
+				continue
+			}
+			texts[locale] = text
+		}
+
+		if _, err := bot.sendMessageToChat(chatID, threadID, text); err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"chatID":     chatID,
+				"incidentID": incident.ID,
+			}).Error("Failed to send the escalation message to the chat")
+			continue
+		}
+
+		// Adding a delay between requests to prevent exceeding rate limits
+		time.Sleep(bot.cfg.RequestDelay)
+	}
+
+	// Notify the emails through the email notifier
+	if len(step.Emails) > 0 {
+		if bot.emailNotifier == nil {
+			log.WithFields(logFields).Warn("The email notifier isn't active; skipping the emails of the escalation step")
+		} else if err := bot.emailNotifier.Escalate(ctx, incident, step.Emails); err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"incidentID": incident.ID,
+			}).Error("Failed to send the escalation email")
+		}
+	}
+
+	// Record the step in the incident history
+	data, err := json.Marshal(&escalationEventData{
+		PolicyID: policy.ID,
+		Level:    level + 1,
+		After:    step.After.String(),
+		Chats:    step.Chats,
+		Emails:   step.Emails,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshalling event data: %w", err)
+	}
+	event := &models.IncidentEvent{
+		ID:         uuid.NewString(),
+		IncidentID: incident.ID,
+		Type:       models.EscalationEventType,
+		Data:       string(data),
+		Creator:    escalationEventCreator,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("error validating event: %w", err)
+	}
+	if err := bot.eventsRepo.CreateIncidentEvent(ctx, event); err != nil {
+		return fmt.Errorf("error creating event: %w", err)
+	}
+
+	log.WithFields(logFields).Info("The incident has been escalated")
+
+	return nil
+}
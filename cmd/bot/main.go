@@ -1,5 +1,10 @@
 package main //dnywonnt.me/alerts2incidents/cmd/bot
 
+import (
+	// Embed the time zone database for the on-call schedules and the digest schedule, as the image has no tzdata
+	_ "time/tzdata"
+)
+
 func main() {
 	// Initialize the bot using the InitializeBot function
 	// This function configures and returns a new instance of Bot with all dependencies set up
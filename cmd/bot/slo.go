@@ -0,0 +1,113 @@
+package main // dnywonnt.me/alerts2incidents/cmd/bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// runSLOChecks checks the error budgets of the service-level objectives on every interval until the context is cancelled
+func (bot *Bot) runSLOChecks(ctx context.Context) {
+	log.WithFields(log.Fields{
+		"checkInterval": bot.cfg.SLOCheckInterval.String(),
+		"burnThreshold": bot.cfg.SLOBurnThreshold,
+	}).Info("Starting checking the error budgets of the SLOs")
+
+	ticker := time.NewTicker(bot.cfg.SLOCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := bot.checkSLOs(ctx); err != nil {
+				log.WithFields(log.Fields{
+					"error": err.Error(),
+				}).Error("Failed to check the SLOs")
+			}
+		}
+	}
+}
+
+// checkSLOs alerts about the objectives that burned more than the threshold of their error budgets within the current period.
+// The time of the alert is stored with the objective, so an objective is alerted about once per period even across restarts
+func (bot *Bot) checkSLOs(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	statuses, err := bot.slosRepo.GetSLOStatuses(ctx, now)
+	if err != nil {
+		return fmt.Errorf("error getting SLO statuses: %w", err)
+	}
+
+	for _, status := range statuses {
+		if status.BurnedFraction < bot.cfg.SLOBurnThreshold {
+			continue
+		}
+		if alertedAt := status.SLO.BurnAlertedAt; alertedAt != nil && !alertedAt.Before(status.PeriodFromAt) {
+			continue
+		}
+
+		bot.sendSLOAlert(ctx, status)
+
+		if err := bot.slosRepo.SetSLOBurnAlertedAt(ctx, status.SLO.ID, now); err != nil {
+			return fmt.Errorf("error recording SLO burn alert: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sendSLOAlert sends the alert about the burned error budget of the objective to all configured SLO chats.
+// Failures to notify a single chat are logged, since the alert must be recorded anyway to avoid notifying the others again
+func (bot *Bot) sendSLOAlert(ctx context.Context, status *models.SLOStatus) {
+	logFields := log.Fields{
+		"sloID":          status.SLO.ID,
+		"target":         status.SLO.Target,
+		"burnedFraction": status.BurnedFraction,
+	}
+	log.WithFields(logFields).Info("Alerting about the burned error budget of the SLO")
+
+	texts := make(map[string]string)
+	for _, chatStr := range bot.cfg.SLOChats {
+		// Parse chat ID, thread ID and locale from configuration, resolving the on-call user if needed
+		chatID, threadID, locale, err := bot.resolveAndParseChatStr(ctx, chatStr)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"chatStr": chatStr,
+			}).Error("Failed to parse chat string")
+			continue
+		}
+
+		// Render the alert once per locale
+		text, ok := texts[locale]
+		if !ok {
+			text, err = bot.sloTmpls[locale].RenderDefault(status)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":  err.Error(),
+					"locale": locale,
+				}).Error("Failed to render the SLO alert to message text")
+				continue
+			}
+			texts[locale] = text
+		}
+
+		if _, err := bot.sendMessageToChat(chatID, threadID, text); err != nil {
+			log.WithFields(log.Fields{
+				"error":  err.Error(),
+				"chatID": chatID,
+				"sloID":  status.SLO.ID,
+			}).Error("Failed to send the SLO alert to the chat")
+			continue
+		}
+
+		// Adding a delay between requests to prevent exceeding rate limits
+		time.Sleep(bot.cfg.RequestDelay)
+	}
+}
@@ -35,10 +35,16 @@ type Handler struct {
 	incidentsCache *cache.Cache
 	rulesRepo      *repositories.RulesRepository
 	incidentsRepo  *repositories.IncidentsRepository
+	windowsRepo    *repositories.MaintenanceWindowsRepository
+	suppressRepo   *repositories.MaintenanceSuppressionsRepository
+	alertsRepo     *repositories.IncidentAlertsRepository
+	windows        []*models.MaintenanceWindow
+	windowsMu      sync.RWMutex
 	dataCh         chan map[service.CollectorType][]byte
 	alertsCh       chan []models.Alert
 	collectors     []service.Collector
 	alertsParser   *service.AlertsParser
+	unmuteInterval time.Duration
 }
 
 // InitializeHandler initializes the Handler with necessary configurations and returns it
@@ -84,6 +90,9 @@ func InitializeHandler() *Handler {
 		dbPool:         dbPool,
 		rulesRepo:      repositories.NewRulesRepository(dbPool),
 		incidentsRepo:  repositories.NewIncidentsRepository(dbPool),
+		windowsRepo:    repositories.NewMaintenanceWindowsRepository(dbPool),
+		suppressRepo:   repositories.NewMaintenanceSuppressionsRepository(dbPool),
+		alertsRepo:     repositories.NewIncidentAlertsRepository(dbPool),
 		rulesCache:     cache.NewCache(serviceConfig.RulesCacheMaxSize, "rules"),
 		incidentsCache: cache.NewCache(serviceConfig.IncidentsCacheMaxSize, "incidents"),
 		dataCh:         make(chan map[service.CollectorType][]byte, serviceConfig.DataChanMaxSize),
@@ -92,7 +101,8 @@ func InitializeHandler() *Handler {
 			impl.NewGrafanaCollector(serviceConfig.GrafanaCollector),
 			impl.NewZabbixCollector(serviceConfig.ZabbixCollector),
 		},
-		alertsParser: service.NewAlertsParser(serviceConfig.AlertsParser),
+		alertsParser:   service.NewAlertsParser(serviceConfig.AlertsParser),
+		unmuteInterval: serviceConfig.RulesUnmuteInterval,
 	}
 }
 
@@ -122,6 +132,20 @@ func (h *Handler) Run() {
 		h.updateIncidentsCache(ctx)
 	}()
 
+	// Unmute the rules whose mutes have expired periodically
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.unmuteExpiredRules(ctx)
+	}()
+
+	// Reload maintenance windows on their changes
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.updateMaintenanceWindows(ctx)
+	}()
+
 	// Start data collectors
 	for _, col := range h.collectors {
 		wg.Add(1)
@@ -201,6 +225,9 @@ func (h *Handler) initializeCaches(ctx context.Context) {
 		}
 	}
 
+	// Load maintenance windows
+	h.loadMaintenanceWindows(ctx)
+
 	// Initialize incidents cache
 	cacheMaxSize := h.incidentsCache.GetMaxSize()
 	incidents, err := h.incidentsRepo.GetIncidents(ctx, nil, "created_at", "desc", 1, cacheMaxSize, zeroTime, zeroTime)
@@ -223,23 +250,41 @@ func (h *Handler) processAlerts(ctx context.Context, alerts []models.Alert) {
 	totalRules := h.rulesCache.GetTotalItems()
 	rulesTotalPages := utils.CalculatePages(totalRules, pageSize)
 
+	currentTimeUTC := time.Now().UTC()
+	resolvedFingerprints := service.FindResolvedFingerprints(alerts)
+
 	// Iterate through each page of cached rules
 	for i := 1; i <= rulesTotalPages; i++ {
 		cachedRules := h.rulesCache.GetItems(i, pageSize)
 		for _, item := range cachedRules {
-			// Type assert the cached item to a Rule and skip if the rule is muted
+			// Type assert the cached item to a Rule and skip if the rule is muted; an expired mute doesn't count
+			// even before the rule is unmuted in the database
 			rule, ok := item.Value.(*models.Rule)
-			if !ok || (ok && rule.IsMuted) {
+			if !ok || (ok && rule.IsMutedAt(currentTimeUTC)) {
 				continue
 			}
 			// Process each valid rule with the alerts
-			h.processRule(ctx, alerts, rule)
+			h.processRule(ctx, alerts, resolvedFingerprints, rule)
+		}
+	}
+
+	// Keep the resolved alerts, as the sources may report them once while the other alerts of an incident are still firing;
+	// the alerts matched again in this batch have already been recorded, so the resolutions come after them
+	if len(resolvedFingerprints) > 0 {
+		fingerprints := make([]string, 0, len(resolvedFingerprints))
+		for fingerprint := range resolvedFingerprints {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		if err := h.alertsRepo.ResolveIncidentAlerts(ctx, fingerprints, currentTimeUTC); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to resolve the incident alerts in the database")
 		}
 	}
 }
 
 // processRule manages incidents based on incoming alerts and a specific rule.
-func (h *Handler) processRule(ctx context.Context, alerts []models.Alert, rule *models.Rule) {
+func (h *Handler) processRule(ctx context.Context, alerts []models.Alert, resolvedFingerprints map[string]struct{}, rule *models.Rule) {
 	// Find alerts matching the rule
 	matchingAlerts, err := service.FindMatchingAlerts(alerts, rule)
 	if err != nil {
@@ -253,10 +298,25 @@ func (h *Handler) processRule(ctx context.Context, alerts []models.Alert, rule *
 	incident, exists := h.getLatestIncidentFromCacheForRule(rule)
 
 	if matchingAlerts != nil {
+		// During the planned work the matches neither create, update nor reopen incidents, they're only recorded for the audit
+		currentTimeUTC := time.Now().UTC()
+		if window, windowFromAt, ok := h.findActiveMaintenanceWindow(rule, currentTimeUTC); ok {
+			h.recordSuppression(ctx, window, windowFromAt, rule, matchingAlerts, currentTimeUTC)
+			return
+		}
+
+		// The matches of an incident merged as a duplicate go to the incident it has been merged into while that one is open
+		if exists && incident.MergedIntoID != nil {
+			if primary, ok := h.getIncidentFromCache(*incident.MergedIntoID); ok && primary.Status != "closed" {
+				h.updateIncident(ctx, primary, matchingAlerts, rule)
+				return
+			}
+		}
+
 		if exists {
 			// If an incident exists and is within its lifetime and not closed, update it
 			if time.Since(incident.CreatedAt) <= rule.IncidentLifeTime && incident.Status != "closed" {
-				h.updateIncident(ctx, incident, rule)
+				h.updateIncident(ctx, incident, matchingAlerts, rule)
 			} else {
 				// If the incident is active but outside its lifetime, finish it
 				if incident.Status == "actual" {
@@ -269,14 +329,24 @@ func (h *Handler) processRule(ctx context.Context, alerts []models.Alert, rule *
 			// If no existing incident, create a new one for the matching alerts
 			h.createIncident(ctx, matchingAlerts, rule)
 		}
-	} else if exists && time.Since(incident.LastMatchingTime) >= rule.IncidentFinishingInterval && incident.Status == "actual" {
-		// If no matching alerts and the existing incident is stale, finish it
-		h.finishIncident(ctx, incident, rule)
+	} else if exists && incident.Status == "actual" {
+		if time.Since(incident.LastMatchingTime) >= finishingInterval(incident, rule) {
+			// If no matching alerts and the existing incident is stale, finish it
+			h.finishIncident(ctx, incident, rule)
+		} else if !incident.IsFlapping && h.isResolvedBySources(ctx, incident, resolvedFingerprints) {
+			// If the sources report all alerts of the incident as resolved, finish it without waiting for the timeout
+			log.WithFields(log.Fields{
+				"id":     incident.ID,
+				"ruleID": rule.ID,
+			}).Info("All alerts of the incident are reported resolved by their sources")
+
+			h.finishIncident(ctx, incident, rule)
+		}
 	}
 }
 
 // updateIncident updates an existing incident with new matching alert information
-func (h *Handler) updateIncident(ctx context.Context, incident *models.Incident, rule *models.Rule) {
+func (h *Handler) updateIncident(ctx context.Context, incident *models.Incident, matchingAlerts []models.Alert, rule *models.Rule) {
 	log.WithFields(log.Fields{
 		"id":     incident.ID,
 		"ruleID": rule.ID,
@@ -289,6 +359,12 @@ func (h *Handler) updateIncident(ctx context.Context, incident *models.Incident,
 	incident.LastMatchingTime = currentTimeUTC
 	incident.UpdatedAt = currentTimeUTC
 
+	// Raise the severity if the new matching alerts are more severe; it's never lowered, as it may have been raised by hand
+	derivedSeverity := ""
+	if rule.DeriveIncidentSeverity {
+		derivedSeverity = rule.IncidentSeverityFor(matchingAlerts)
+	}
+
 	// Reopen incident if it was finished
 	if incident.Status == "finished" {
 		log.WithFields(log.Fields{
@@ -298,14 +374,32 @@ func (h *Handler) updateIncident(ctx context.Context, incident *models.Incident,
 
 		incident.Status = "actual"
 		incident.ToAt = time.Time{}
+		incident.FlapCount += 1
+		recordStateChange(incident, rule, currentTimeUTC)
+
+		// Hold the incident open if it bounces between actual and finished too often
+		if rule.FlappingThreshold > 0 && len(incident.StateChangesAt) >= rule.FlappingThreshold && !incident.IsFlapping {
+			log.WithFields(log.Fields{
+				"id":         incident.ID,
+				"ruleID":     rule.ID,
+				"flapCount":  incident.FlapCount,
+				"changes":    len(incident.StateChangesAt),
+				"flapWindow": rule.FlappingWindow,
+			}).Warn("Incident is flapping; holding it open until it stays quiet for the flapping window")
+
+			incident.IsFlapping = true
+		}
 	}
 
-	// Persist the updated incident to the repository
-	if err := h.incidentsRepo.UpdateIncident(ctx, incident); err != nil {
+	// Persist the columns of the incident owned by the handler, leaving the ones edited by hand intact
+	if err := h.incidentsRepo.UpdateIncidentMatching(ctx, incident, derivedSeverity); err != nil {
 		log.WithFields(log.Fields{
 			"error": err.Error(),
 		}).Error("Failed to update incident in the database")
+		return
 	}
+
+	h.recordIncidentAlerts(ctx, incident, matchingAlerts, currentTimeUTC)
 }
 
 // finishIncident updates the status of the given incident to "finished" and persists the update to the repository.
@@ -318,13 +412,15 @@ func (h *Handler) finishIncident(ctx context.Context, incident *models.Incident,
 
 	currentTimeUTC := time.Now().UTC()
 
-	// Update incident status to finished
+	// Update incident status to finished; a flapping incident is only finished once it has calmed down
 	incident.Status = "finished"
 	incident.ToAt = currentTimeUTC
 	incident.UpdatedAt = currentTimeUTC
+	incident.IsFlapping = false
+	recordStateChange(incident, rule, currentTimeUTC)
 
-	// Persist the updated incident to the repository
-	if err := h.incidentsRepo.UpdateIncident(ctx, incident); err != nil {
+	// Persist the columns of the incident owned by the handler, leaving the ones edited by hand intact
+	if err := h.incidentsRepo.UpdateIncidentMatching(ctx, incident, ""); err != nil {
 		log.WithFields(log.Fields{
 			"error": err.Error(),
 		}).Error("Failed to update incident in the database")
@@ -343,6 +439,25 @@ func (h *Handler) createIncident(ctx context.Context, matchingAlerts []models.Al
 	}
 
 	currentTimeUTC := time.Now().UTC()
+
+	// Evaluate the incident texts of the rule against the matching alerts, falling back to the raw texts
+	summary, err := rule.RenderIncidentSummary(matchingAlerts)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"ruleID": rule.ID,
+		}).Warn("Failed to render the incident summary; using the raw rule text")
+		summary = rule.SetIncidentSummary
+	}
+	description, err := rule.RenderIncidentDescription(matchingAlerts)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"ruleID": rule.ID,
+		}).Warn("Failed to render the incident description; using the raw rule text")
+		description = rule.SetIncidentDescription
+	}
+
 	zeroTime := time.Time{}
 
 	// Create a new incident object with the relevant details
@@ -350,8 +465,8 @@ func (h *Handler) createIncident(ctx context.Context, matchingAlerts []models.Al
 		ID:               uuid.NewString(),
 		Type:             "auto",
 		Status:           "actual",
-		Summary:          rule.SetIncidentSummary,
-		Description:      rule.SetIncidentDescription,
+		Summary:          summary,
+		Description:      description,
 		FromAt:           currentTimeUTC,
 		ToAt:             zeroTime,
 		IsConfirmed:      false,
@@ -367,12 +482,14 @@ func (h *Handler) createIncident(ctx context.Context, matchingAlerts []models.Al
 		DeployLink:       "",
 		Labels:           rule.SetIncidentLabels,
 		IsDowntime:       rule.SetIncidentIsDowntime,
+		Severity:         rule.IncidentSeverityFor(matchingAlerts),
 		PostmortemLink:   "",
 		Creator:          "handler",
 		RuleID:           &rule.ID,
 		MatchingCount:    1,
 		LastMatchingTime: currentTimeUTC,
 		AlertsData:       string(alertsData),
+		Version:          1,
 		CreatedAt:        currentTimeUTC,
 		UpdatedAt:        currentTimeUTC,
 	}
@@ -394,6 +511,8 @@ func (h *Handler) createIncident(ctx context.Context, matchingAlerts []models.Al
 		return
 	}
 
+	h.recordIncidentAlerts(ctx, newIncident, matchingAlerts, currentTimeUTC)
+
 	log.WithFields(log.Fields{
 		"id":     newIncident.ID,
 		"ruleID": rule.ID,
@@ -426,6 +545,118 @@ func (h *Handler) updateRulesCache(ctx context.Context) {
 	})
 }
 
+// unmuteExpiredRules periodically unmutes the rules whose mutes have expired in the database.
+// The changes come back through the rules channel notifications, so the caches of all services are refreshed.
+func (h *Handler) unmuteExpiredRules(ctx context.Context) {
+	ticker := time.NewTicker(h.unmuteInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ids, err := h.rulesRepo.UnmuteExpiredRules(ctx, time.Now().UTC())
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error": err.Error(),
+				}).Error("Failed to unmute the rules with expired mutes")
+				continue
+			}
+			for _, id := range ids {
+				log.WithFields(log.Fields{
+					"ruleID": id,
+				}).Info("The rule has been unmuted as its mute has expired")
+			}
+		}
+	}
+}
+
+// updateMaintenanceWindows listens for notifications and reloads the maintenance windows on any change
+func (h *Handler) updateMaintenanceWindows(ctx context.Context) {
+	database.ListenToNotifications(ctx, h.dbPool, database.MaintenanceWindowsChannel, func(notification *pgconn.Notification) {
+		h.loadMaintenanceWindows(ctx)
+	})
+}
+
+// loadMaintenanceWindows replaces the loaded maintenance windows with the ones from the database that haven't ended yet
+func (h *Handler) loadMaintenanceWindows(ctx context.Context) {
+	currentTimeUTC := time.Now().UTC()
+	windows, err := h.windowsRepo.GetMaintenanceWindows(ctx, &currentTimeUTC)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Error("Failed to get maintenance windows")
+		return
+	}
+
+	h.windowsMu.Lock()
+	h.windows = windows
+	h.windowsMu.Unlock()
+}
+
+// findActiveMaintenanceWindow returns the maintenance window active at the given time that applies to the rule,
+// together with the start of its active occurrence. It returns false if there is no such window.
+func (h *Handler) findActiveMaintenanceWindow(rule *models.Rule, at time.Time) (*models.MaintenanceWindow, time.Time, bool) {
+	h.windowsMu.RLock()
+	defer h.windowsMu.RUnlock()
+
+	for _, window := range h.windows {
+		if !window.AppliesTo(rule) {
+			continue
+		}
+		if fromAt, _, ok := window.OccurrenceAt(at); ok {
+			return window, fromAt, true
+		}
+	}
+
+	return nil, time.Time{}, false
+}
+
+// recordSuppression records the match of the rule suppressed by the occurrence of the maintenance window
+func (h *Handler) recordSuppression(ctx context.Context, window *models.MaintenanceWindow, windowFromAt time.Time, rule *models.Rule, matchingAlerts []models.Alert, at time.Time) {
+	log.WithFields(log.Fields{
+		"windowID": window.ID,
+		"ruleID":   rule.ID,
+	}).Info("The match of the rule is suppressed by the maintenance window")
+
+	// Serialize the matching alerts to JSON
+	alertsData, err := json.Marshal(matchingAlerts)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"ruleID": rule.ID,
+		}).Error("Failed to marshal alerts for a maintenance suppression")
+		return
+	}
+
+	suppression := &models.MaintenanceSuppression{
+		ID:               uuid.NewString(),
+		WindowID:         window.ID,
+		WindowFromAt:     windowFromAt,
+		RuleID:           rule.ID,
+		LastMatchingTime: at,
+		AlertsData:       string(alertsData),
+	}
+	if err := h.suppressRepo.RecordMaintenanceSuppression(ctx, suppression); err != nil {
+		log.WithFields(log.Fields{
+			"error":    err.Error(),
+			"windowID": window.ID,
+			"ruleID":   rule.ID,
+		}).Error("Failed to record the maintenance suppression in the database")
+	}
+}
+
+// recordIncidentAlerts records the alerts of a match of the incident, so the full set of contributing alerts is kept
+func (h *Handler) recordIncidentAlerts(ctx context.Context, incident *models.Incident, matchingAlerts []models.Alert, at time.Time) {
+	if err := h.alertsRepo.RecordIncidentAlerts(ctx, incident.ID, matchingAlerts, at); err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"id":    incident.ID,
+		}).Error("Failed to record the incident alerts in the database")
+	}
+}
+
 // updateCacheFromNotification updates the cache based on the notification received
 func updateCacheFromNotification(ctx context.Context, cache *cache.Cache, notification *pgconn.Notification, fetchItem func(context.Context, string) (interface{}, error)) error {
 	parts := strings.SplitN(notification.Payload, ":", 2)
@@ -453,6 +684,67 @@ func updateCacheFromNotification(ctx context.Context, cache *cache.Cache, notifi
 	return nil
 }
 
+// isResolvedBySources reports whether all alerts that have contributed to the incident are reported resolved by their sources,
+// either in the current batch or in the previous ones. The incident isn't considered resolved if any of its alerts
+// can't be identified at its source, as such an alert is never reported resolved.
+func (h *Handler) isResolvedBySources(ctx context.Context, incident *models.Incident, resolvedFingerprints map[string]struct{}) bool {
+	alerts, err := h.alertsRepo.GetIncidentAlerts(ctx, incident.ID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"id":    incident.ID,
+		}).Error("Failed to get the incident alerts from the database")
+		return false
+	}
+	if len(alerts) == 0 {
+		return false
+	}
+
+	for _, alert := range alerts {
+		if _, ok := resolvedFingerprints[alert.Fingerprint]; !ok && alert.ResolvedAt == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// finishingInterval returns the interval without matching alerts after which the incident is finished.
+// A flapping incident is held open until it stays quiet for the whole flapping window of the rule.
+func finishingInterval(incident *models.Incident, rule *models.Rule) time.Duration {
+	if incident.IsFlapping && rule.FlappingWindow > rule.IncidentFinishingInterval {
+		return rule.FlappingWindow
+	}
+	return rule.IncidentFinishingInterval
+}
+
+// recordStateChange records that the incident has been finished or reopened at the given time,
+// keeping only the state changes within the flapping window of the rule.
+func recordStateChange(incident *models.Incident, rule *models.Rule, at time.Time) {
+	if rule.FlappingThreshold == 0 {
+		incident.StateChangesAt = nil
+		return
+	}
+
+	stateChangesAt := []time.Time{}
+	for _, changedAt := range incident.StateChangesAt {
+		if at.Sub(changedAt) < rule.FlappingWindow {
+			stateChangesAt = append(stateChangesAt, changedAt)
+		}
+	}
+	incident.StateChangesAt = append(stateChangesAt, at)
+}
+
+// getIncidentFromCache retrieves the incident with the given ID from the cache.
+func (h *Handler) getIncidentFromCache(id string) (*models.Incident, bool) {
+	item, exists := h.incidentsCache.GetItem(id)
+	if !exists {
+		return nil, false
+	}
+	incident, ok := item.Value.(*models.Incident)
+	return incident, ok
+}
+
 // getLatestIncidentFromCacheForRule retrieves the latest incident from the cache that matches the given rule.
 // It returns the incident and true if found, otherwise returns nil and false.
 func (h *Handler) getLatestIncidentFromCacheForRule(rule *models.Rule) (*models.Incident, bool) {
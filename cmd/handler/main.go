@@ -1,5 +1,10 @@
 package main // dnywonnt.me/alerts2incidents/cmd/handler
 
+import (
+	// Embed the time zone database for the maintenance windows, as the image has no tzdata
+	_ "time/tzdata"
+)
+
 func main() {
 	// Initialize the handler by calling InitializeHandler function which sets up
 	// logging, configurations, database connections, caching mechanisms,
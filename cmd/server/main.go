@@ -1,5 +1,10 @@
 package main // dnywonnt.me/alerts2incidents/cmd/server
 
+import (
+	// Embed the time zone database for validating the time zones of the on-call schedules and maintenance windows, as the image has no tzdata
+	_ "time/tzdata"
+)
+
 func main() {
 	// Initialize the server using the InitializeServer function, which sets up all necessary configurations,
 	// database connections, routes, and middleware.
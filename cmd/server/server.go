@@ -12,21 +12,31 @@ import (
 
 	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
 	"dnywonnt.me/alerts2incidents/internal/api/v1/handlers"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/openapi"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/stream"
 	"dnywonnt.me/alerts2incidents/internal/config"
 	"dnywonnt.me/alerts2incidents/internal/database"
 	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/oncall"
+	"dnywonnt.me/alerts2incidents/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// incidentsStreamHistorySize is the number of recent incident changes kept for the reconnecting stream clients.
+const incidentsStreamHistorySize = 1000
+
 // Server structure holds the server's runtime configuration and state.
 type Server struct {
 	srv           *http.Server                      // HTTP server
 	dbPool        *pgxpool.Pool                     // Connection pool to the PostgreSQL database
 	incidentsRepo *repositories.IncidentsRepository // Repository for incidents data
 	rulesRepo     *repositories.RulesRepository     // Repository for rules data
+	incidentsHub  *stream.Hub                       // Hub of the incident changes streamed to the clients
 }
 
 // InitializeServer initializes a new server with configuration and database connection.
@@ -69,17 +79,20 @@ func InitializeServer() *Server {
 
 	// Set Gin to release mode for production
 	gin.SetMode(gin.ReleaseMode)
-	router := gin.New()
-	router.Use(v1.LoggerMiddleware())
 
-	// Initialize repositories
+	// Initialize the repositories of the relay of the incident changes and the hub streaming them to the clients
 	incidentsRepo := repositories.NewIncidentsRepository(dbPool)
 	rulesRepo := repositories.NewRulesRepository(dbPool)
+	incidentsHub := stream.NewHub(incidentsStreamHistorySize)
 
-	// Register HTTP routes
-	handlers.RegisterAuthRoutes(router, apiCfg)
-	handlers.RegisterIncidentsRoutes(router, incidentsRepo, apiCfg)
-	handlers.RegisterRulesRoutes(router, rulesRepo, apiCfg)
+	router, openAPIDocument := newRouter(dbPool, incidentsHub, apiCfg)
+
+	// The routes must match the OpenAPI document, which the tests verify; a mismatch is reported, but doesn't stop the server
+	if err := openapi.CheckRoutes(openAPIDocument, router.Routes()); err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Warn("The OpenAPI document is out of sync with the routes")
+	}
 
 	// Returning Server instance with initialized components
 	return &Server{
@@ -90,7 +103,59 @@ func InitializeServer() *Server {
 		dbPool:        dbPool,
 		incidentsRepo: incidentsRepo,
 		rulesRepo:     rulesRepo,
+		incidentsHub:  incidentsHub,
+	}
+}
+
+// newRouter creates the router with the middlewares and all the routes of the API, along with the OpenAPI document describing them.
+// It doesn't use the database pool until the requests are handled, so the tests build it with a nil pool.
+func newRouter(dbPool *pgxpool.Pool, incidentsHub *stream.Hub, apiCfg *config.ApiConfig) (*gin.Engine, *openapi.Document) {
+	router := gin.New()
+
+	// Every request gets an ID, is logged, and its error, if any, is rendered as the JSON error response
+	router.Use(v1.RequestIDMiddleware(), v1.LoggerMiddleware(), v1.ErrorMiddleware())
+	router.NoRoute(v1.NoRouteHandler())
+
+	// The request bodies bound by Gin report the invalid fields by their JSON names, like the validated models
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		utils.RegisterJSONFieldNames(engine)
 	}
+
+	// The OpenAPI document describes the routes, so the query parameters of the requests are validated against it
+	openAPIDocument := handlers.OpenAPIDocument()
+	router.Use(v1.QueryValidationMiddleware(openAPIDocument))
+
+	// Initialize repositories
+	incidentsRepo := repositories.NewIncidentsRepository(dbPool)
+	rulesRepo := repositories.NewRulesRepository(dbPool)
+	incidentEventsRepo := repositories.NewIncidentEventsRepository(dbPool)
+	escalationPoliciesRepo := repositories.NewEscalationPoliciesRepository(dbPool)
+	onCallUsersRepo := repositories.NewOnCallUsersRepository(dbPool)
+	onCallSchedulesRepo := repositories.NewOnCallSchedulesRepository(dbPool)
+	onCallOverridesRepo := repositories.NewOnCallOverridesRepository(dbPool)
+	onCallResolver := oncall.NewResolver(onCallUsersRepo, onCallSchedulesRepo, onCallOverridesRepo)
+	maintenanceWindowsRepo := repositories.NewMaintenanceWindowsRepository(dbPool)
+	maintenanceSuppressionsRepo := repositories.NewMaintenanceSuppressionsRepository(dbPool)
+	incidentAlertsRepo := repositories.NewIncidentAlertsRepository(dbPool)
+	incidentLinksRepo := repositories.NewIncidentLinksRepository(dbPool)
+	slosRepo := repositories.NewSLOsRepository(dbPool)
+
+	// Register HTTP routes
+	handlers.RegisterAuthRoutes(router, apiCfg)
+	handlers.RegisterIncidentsRoutes(router, incidentsRepo, apiCfg)
+	handlers.RegisterIncidentsStreamRoutes(router, incidentsHub, apiCfg)
+	handlers.RegisterRulesRoutes(router, rulesRepo, apiCfg)
+	handlers.RegisterIncidentEventsRoutes(router, incidentEventsRepo, apiCfg)
+	handlers.RegisterIncidentAlertsRoutes(router, incidentAlertsRepo, apiCfg)
+	handlers.RegisterIncidentLinksRoutes(router, incidentsRepo, incidentLinksRepo, apiCfg)
+	handlers.RegisterReportsRoutes(router, incidentsRepo, apiCfg)
+	handlers.RegisterSLOsRoutes(router, slosRepo, apiCfg)
+	handlers.RegisterEscalationPoliciesRoutes(router, escalationPoliciesRepo, apiCfg)
+	handlers.RegisterOnCallRoutes(router, onCallUsersRepo, onCallSchedulesRepo, onCallOverridesRepo, onCallResolver, apiCfg)
+	handlers.RegisterMaintenanceWindowsRoutes(router, maintenanceWindowsRepo, maintenanceSuppressionsRepo, apiCfg)
+	handlers.RegisterOpenAPIRoutes(router, openAPIDocument)
+
+	return router, openAPIDocument
 }
 
 // Run starts the HTTP server and handles graceful shutdown on system signals.
@@ -99,6 +164,14 @@ func (s *Server) Run() {
 		"addr": s.srv.Addr,
 	}).Info("Starting the server")
 
+	// Relay the incident changes to the stream clients; the hub is closed on shutdown, which ends the streaming requests
+	streamCtx, cancelStream := context.WithCancel(context.Background())
+	go stream.RelayIncidents(streamCtx, s.dbPool, s.incidentsRepo, s.incidentsHub)
+	s.srv.RegisterOnShutdown(func() {
+		cancelStream()
+		s.incidentsHub.Close()
+	})
+
 	go func() {
 		if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.WithFields(log.Fields{
@@ -0,0 +1,22 @@
+package main // dnywonnt.me/alerts2incidents/cmd/server
+
+import (
+	"testing"
+
+	"dnywonnt.me/alerts2incidents/internal/api/v1/openapi"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/stream"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRoutesMatchOpenAPIDocument(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := stream.NewHub(incidentsStreamHistorySize)
+	defer hub.Close()
+	router, document := newRouter(nil, hub, &config.ApiConfig{})
+
+	if err := openapi.CheckRoutes(document, router.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
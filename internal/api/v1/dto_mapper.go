@@ -54,15 +54,22 @@ func MapCreateIncidentDTOToModel(dto *dtos.CreateIncidentDTO) (*models.Incident,
 		Labels:           dto.Labels,                // Map labels.
 		IsDowntime:       dto.IsDowntime,            // Map downtime status.
 		PostmortemLink:   dto.PostmortemLink,        // Map postmortem link.
+		Severity:         dto.Severity,              // Map severity.
 		Creator:          dto.Creator,               // Map creator.
 		RuleID:           nil,                       // Initialize RuleID as nil.
 		MatchingCount:    0,                         // Initialize matching count as 0.
 		LastMatchingTime: zeroTime,                  // Initialize last matching time.
 		AlertsData:       "",                        // Initialize alerts data.
+		Version:          1,                         // Start with the first version.
 		CreatedAt:        currentTimeUTC,            // Set creation time.
 		UpdatedAt:        currentTimeUTC,            // Set update time.
 	}
 
+	// Incidents are the least severe unless the severity is set.
+	if incident.Severity == "" {
+		incident.Severity = models.DefaultSeverity
+	}
+
 	// Validate the newly created incident model.
 	if err := incident.Validate(); err != nil {
 		return nil, err
@@ -95,6 +102,7 @@ func MapUpdateIncidentDTOToModel(dto *dtos.UpdateIncidentDTO, incident *models.I
 	updateField(dto.Labels, &incident.Labels, &anyFieldUpdated)
 	updateField(dto.IsDowntime, &incident.IsDowntime, &anyFieldUpdated)
 	updateField(dto.PostmortemLink, &incident.PostmortemLink, &anyFieldUpdated)
+	updateField(dto.Severity, &incident.Severity, &anyFieldUpdated)
 
 	// If no fields were updated, return an error.
 	if !anyFieldUpdated {
@@ -117,6 +125,8 @@ func MapCreateRuleDTOToModel(dto *dtos.CreateRuleDTO) (*models.Rule, error) {
 	rule := &models.Rule{
 		ID:                               uuid.NewString(),                     // Generate a new unique ID for the rule.
 		IsMuted:                          dto.IsMuted,                          // Map the mute status from DTO.
+		MuteReason:                       dto.MuteReason,                       // Map the mute reason from DTO.
+		MutedBy:                          dto.MutedBy,                          // Map the muting user from DTO.
 		Description:                      dto.Description,                      // Map the description from DTO.
 		AlertsSummaryConditions:          dto.AlertsSummaryConditions,          // Map summary conditions from DTO.
 		AlertsActivityIntervalConditions: dto.AlertsActivityIntervalConditions, // Map activity interval conditions from DTO.
@@ -132,10 +142,29 @@ func MapCreateRuleDTOToModel(dto *dtos.CreateRuleDTO) (*models.Rule, error) {
 		SetIncidentFailureType:           dto.SetIncidentFailureType,           // Map the type of failure from DTO.
 		SetIncidentLabels:                dto.SetIncidentLabels,                // Map the incident labels from DTO.
 		SetIncidentIsDowntime:            dto.SetIncidentIsDowntime,            // Map the downtime status from DTO.
+		FlappingWindow:                   dto.FlappingWindow,                   // Map the flapping detection window from DTO.
+		FlappingThreshold:                dto.FlappingThreshold,                // Map the flapping detection threshold from DTO.
+		SetIncidentSeverity:              dto.SetIncidentSeverity,              // Map the incident severity to be set from DTO.
+		DeriveIncidentSeverity:           dto.DeriveIncidentSeverity,           // Map whether the severity is derived from the alerts from DTO.
+		Version:                          1,                                    // Start with the first version.
 		CreatedAt:                        currentTime,                          // Set the creation time.
 		UpdatedAt:                        currentTime,                          // Set the update time.
 	}
 
+	// Rules set the least severe level unless the severity is set.
+	if rule.SetIncidentSeverity == "" {
+		rule.SetIncidentSeverity = models.DefaultSeverity
+	}
+
+	// Map the time the rule is automatically unmuted at, which must be in the future.
+	if dto.MuteUntil != nil {
+		if !dto.MuteUntil.After(currentTime) {
+			return nil, errors.New("mute_until must be in the future")
+		}
+		muteUntil := dto.MuteUntil.UTC()
+		rule.MuteUntil = &muteUntil
+	}
+
 	// Validate the newly created rule model.
 	if err := rule.Validate(); err != nil {
 		return nil, err // Return error if validation fails.
@@ -148,8 +177,25 @@ func MapCreateRuleDTOToModel(dto *dtos.CreateRuleDTO) (*models.Rule, error) {
 func MapUpdateRuleDTOToModel(dto *dtos.UpdateRuleDTO, rule *models.Rule) error {
 	anyFieldUpdated := false // Track if any field has been updated.
 
+	// Muting or unmuting the rule resets its mute details, so a new mute doesn't inherit the details of an old one.
+	if dto.IsMuted != nil && *dto.IsMuted != rule.IsMuted {
+		rule.MuteUntil = nil
+		rule.MuteReason = ""
+		rule.MutedBy = ""
+	}
+	if dto.MuteUntil != nil {
+		if !dto.MuteUntil.After(time.Now().UTC()) {
+			return errors.New("mute_until must be in the future")
+		}
+		muteUntil := dto.MuteUntil.UTC()
+		rule.MuteUntil = &muteUntil
+		anyFieldUpdated = true
+	}
+
 	// Update each field from the DTO if provided, and mark the record as updated.
 	updateField(dto.IsMuted, &rule.IsMuted, &anyFieldUpdated)
+	updateField(dto.MuteReason, &rule.MuteReason, &anyFieldUpdated)
+	updateField(dto.MutedBy, &rule.MutedBy, &anyFieldUpdated)
 	updateField(dto.Description, &rule.Description, &anyFieldUpdated)
 	updateField(dto.AlertsSummaryConditions, &rule.AlertsSummaryConditions, &anyFieldUpdated)
 	updateField(dto.AlertsActivityIntervalConditions, &rule.AlertsActivityIntervalConditions, &anyFieldUpdated)
@@ -165,6 +211,10 @@ func MapUpdateRuleDTOToModel(dto *dtos.UpdateRuleDTO, rule *models.Rule) error {
 	updateField(dto.SetIncidentFailureType, &rule.SetIncidentFailureType, &anyFieldUpdated)
 	updateField(dto.SetIncidentLabels, &rule.SetIncidentLabels, &anyFieldUpdated)
 	updateField(dto.SetIncidentIsDowntime, &rule.SetIncidentIsDowntime, &anyFieldUpdated)
+	updateField(dto.FlappingWindow, &rule.FlappingWindow, &anyFieldUpdated)
+	updateField(dto.FlappingThreshold, &rule.FlappingThreshold, &anyFieldUpdated)
+	updateField(dto.SetIncidentSeverity, &rule.SetIncidentSeverity, &anyFieldUpdated)
+	updateField(dto.DeriveIncidentSeverity, &rule.DeriveIncidentSeverity, &anyFieldUpdated)
 
 	// If no fields were updated, return an error indicating no update was made.
 	if !anyFieldUpdated {
@@ -179,3 +229,361 @@ func MapUpdateRuleDTOToModel(dto *dtos.UpdateRuleDTO, rule *models.Rule) error {
 
 	return nil
 }
+
+// MapCreateEscalationPolicyDTOToModel converts a CreateEscalationPolicyDTO into an EscalationPolicy model and validates it.
+func MapCreateEscalationPolicyDTOToModel(dto *dtos.CreateEscalationPolicyDTO) (*models.EscalationPolicy, error) {
+	currentTime := time.Now().UTC() // Capture the current time in UTC for timestamps.
+
+	policy := &models.EscalationPolicy{
+		ID:          uuid.NewString(), // Generate a new unique ID for the policy.
+		Description: dto.Description,  // Map the description from DTO.
+		RuleID:      dto.RuleID,       // Map the rule from DTO.
+		Departament: dto.Departament,  // Map the departament from DTO.
+		Steps:       dto.Steps,        // Map the escalation steps from DTO.
+		CreatedAt:   currentTime,      // Set the creation time.
+		UpdatedAt:   currentTime,      // Set the update time.
+	}
+
+	// Validate the newly created policy model.
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// MapUpdateEscalationPolicyDTOToModel updates an existing escalation policy model with data from an UpdateEscalationPolicyDTO.
+func MapUpdateEscalationPolicyDTOToModel(dto *dtos.UpdateEscalationPolicyDTO, policy *models.EscalationPolicy) error {
+	if dto.RuleID != nil && dto.Departament != nil {
+		return errors.New("rule_id and departament are mutually exclusive")
+	}
+
+	anyFieldUpdated := false // Track if any field has been updated.
+
+	// A policy is attached either to a rule or to a departament, so setting one of them clears the other.
+	if dto.RuleID != nil {
+		policy.RuleID = dto.RuleID
+		policy.Departament = nil
+		anyFieldUpdated = true
+	}
+	if dto.Departament != nil {
+		policy.Departament = dto.Departament
+		policy.RuleID = nil
+		anyFieldUpdated = true
+	}
+	updateField(dto.Description, &policy.Description, &anyFieldUpdated)
+	updateField(dto.Steps, &policy.Steps, &anyFieldUpdated)
+
+	// If no fields were updated, return an error indicating no update was made.
+	if !anyFieldUpdated {
+		return errors.New("no fields provided for update")
+	} else {
+		// Validate the updated policy and update the timestamp.
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+		policy.UpdatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+// MapCreateOnCallUserDTOToModel converts a CreateOnCallUserDTO into an OnCallUser model and validates it.
+func MapCreateOnCallUserDTOToModel(dto *dtos.CreateOnCallUserDTO) (*models.OnCallUser, error) {
+	currentTime := time.Now().UTC() // Capture the current time in UTC for timestamps.
+
+	user := &models.OnCallUser{
+		ID:         uuid.NewString(), // Generate a new unique ID for the user.
+		Name:       dto.Name,         // Map the name from DTO.
+		TelegramID: dto.TelegramID,   // Map the Telegram ID from DTO.
+		Email:      dto.Email,        // Map the email from DTO.
+		LDAPLogin:  dto.LDAPLogin,    // Map the LDAP login from DTO.
+		CreatedAt:  currentTime,      // Set the creation time.
+		UpdatedAt:  currentTime,      // Set the update time.
+	}
+
+	// Validate the newly created user model.
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// MapUpdateOnCallUserDTOToModel updates an existing on-call user model with data from an UpdateOnCallUserDTO.
+func MapUpdateOnCallUserDTOToModel(dto *dtos.UpdateOnCallUserDTO, user *models.OnCallUser) error {
+	anyFieldUpdated := false // Track if any field has been updated.
+
+	updateField(dto.Name, &user.Name, &anyFieldUpdated)
+	updateField(dto.TelegramID, &user.TelegramID, &anyFieldUpdated)
+	updateField(dto.Email, &user.Email, &anyFieldUpdated)
+	updateField(dto.LDAPLogin, &user.LDAPLogin, &anyFieldUpdated)
+
+	// If no fields were updated, return an error indicating no update was made.
+	if !anyFieldUpdated {
+		return errors.New("no fields provided for update")
+	} else {
+		// Validate the updated user and update the timestamp.
+		if err := user.Validate(); err != nil {
+			return err
+		}
+		user.UpdatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+// MapCreateOnCallScheduleDTOToModel converts a CreateOnCallScheduleDTO into an OnCallSchedule model and validates it.
+func MapCreateOnCallScheduleDTOToModel(dto *dtos.CreateOnCallScheduleDTO) (*models.OnCallSchedule, error) {
+	currentTime := time.Now().UTC() // Capture the current time in UTC for timestamps.
+
+	schedule := &models.OnCallSchedule{
+		ID:            uuid.NewString(),        // Generate a new unique ID for the schedule.
+		Name:          dto.Name,                // Map the name from DTO.
+		Description:   dto.Description,         // Map the description from DTO.
+		Timezone:      dto.Timezone,            // Map the time zone from DTO.
+		Rotation:      dto.Rotation,            // Map the rotation from DTO.
+		RotationStart: dto.RotationStart.UTC(), // Map the rotation start from DTO.
+		UserIDs:       dto.UserIDs,             // Map the users of the rotation from DTO.
+		CreatedAt:     currentTime,             // Set the creation time.
+		UpdatedAt:     currentTime,             // Set the update time.
+	}
+
+	// Validate the newly created schedule model.
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// MapUpdateOnCallScheduleDTOToModel updates an existing on-call schedule model with data from an UpdateOnCallScheduleDTO.
+func MapUpdateOnCallScheduleDTOToModel(dto *dtos.UpdateOnCallScheduleDTO, schedule *models.OnCallSchedule) error {
+	anyFieldUpdated := false // Track if any field has been updated.
+
+	updateField(dto.Name, &schedule.Name, &anyFieldUpdated)
+	updateField(dto.Description, &schedule.Description, &anyFieldUpdated)
+	updateField(dto.Timezone, &schedule.Timezone, &anyFieldUpdated)
+	updateField(dto.Rotation, &schedule.Rotation, &anyFieldUpdated)
+	updateTimeField(dto.RotationStart, &schedule.RotationStart, &anyFieldUpdated)
+	updateField(dto.UserIDs, &schedule.UserIDs, &anyFieldUpdated)
+
+	// If no fields were updated, return an error indicating no update was made.
+	if !anyFieldUpdated {
+		return errors.New("no fields provided for update")
+	} else {
+		// Validate the updated schedule and update the timestamp.
+		if err := schedule.Validate(); err != nil {
+			return err
+		}
+		schedule.UpdatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+// MapCreateOnCallOverrideDTOToModel converts a CreateOnCallOverrideDTO into an OnCallOverride model of the schedule and validates it.
+func MapCreateOnCallOverrideDTOToModel(dto *dtos.CreateOnCallOverrideDTO, scheduleID string) (*models.OnCallOverride, error) {
+	override := &models.OnCallOverride{
+		ID:         uuid.NewString(), // Generate a new unique ID for the override.
+		ScheduleID: scheduleID,       // Attach the override to the schedule.
+		UserID:     dto.UserID,       // Map the user from DTO.
+		FromAt:     dto.FromAt.UTC(), // Map the start of the override from DTO.
+		ToAt:       dto.ToAt.UTC(),   // Map the end of the override from DTO.
+		CreatedAt:  time.Now().UTC(), // Set the creation time.
+	}
+
+	// Validate the newly created override model.
+	if err := override.Validate(); err != nil {
+		return nil, err
+	}
+
+	return override, nil
+}
+
+// MapCreateMaintenanceWindowDTOToModel converts a CreateMaintenanceWindowDTO into a MaintenanceWindow model and validates it.
+func MapCreateMaintenanceWindowDTOToModel(dto *dtos.CreateMaintenanceWindowDTO) (*models.MaintenanceWindow, error) {
+	currentTime := time.Now().UTC() // Capture the current time in UTC for timestamps.
+
+	window := &models.MaintenanceWindow{
+		ID:              uuid.NewString(),    // Generate a new unique ID for the window.
+		Description:     dto.Description,     // Map the description from DTO.
+		FromAt:          dto.FromAt.UTC(),    // Map the start of the window from DTO.
+		ToAt:            dto.ToAt.UTC(),      // Map the end of the window from DTO.
+		Recurrence:      dto.Recurrence,      // Map the recurrence from DTO.
+		Timezone:        dto.Timezone,        // Map the time zone from DTO.
+		RuleIDs:         dto.RuleIDs,         // Map the rules from DTO.
+		Labels:          dto.Labels,          // Map the labels from DTO.
+		TroubleServices: dto.TroubleServices, // Map the trouble services from DTO.
+		Creator:         dto.Creator,         // Map the creator from DTO.
+		CreatedAt:       currentTime,         // Set the creation time.
+		UpdatedAt:       currentTime,         // Set the update time.
+	}
+
+	// Apply the defaults of the optional fields.
+	if window.Recurrence == "" {
+		window.Recurrence = "none"
+	}
+	if window.Timezone == "" {
+		window.Timezone = "UTC"
+	}
+	if window.RuleIDs == nil {
+		window.RuleIDs = []string{}
+	}
+	if window.Labels == nil {
+		window.Labels = []string{}
+	}
+	if window.TroubleServices == nil {
+		window.TroubleServices = []string{}
+	}
+
+	// Validate the newly created window model.
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+// MapUpdateMaintenanceWindowDTOToModel updates an existing maintenance window model with data from an UpdateMaintenanceWindowDTO.
+func MapUpdateMaintenanceWindowDTOToModel(dto *dtos.UpdateMaintenanceWindowDTO, window *models.MaintenanceWindow) error {
+	anyFieldUpdated := false // Track if any field has been updated.
+
+	updateField(dto.Description, &window.Description, &anyFieldUpdated)
+	updateTimeField(dto.FromAt, &window.FromAt, &anyFieldUpdated)
+	updateTimeField(dto.ToAt, &window.ToAt, &anyFieldUpdated)
+	updateField(dto.Recurrence, &window.Recurrence, &anyFieldUpdated)
+	updateField(dto.Timezone, &window.Timezone, &anyFieldUpdated)
+	updateField(dto.RuleIDs, &window.RuleIDs, &anyFieldUpdated)
+	updateField(dto.Labels, &window.Labels, &anyFieldUpdated)
+	updateField(dto.TroubleServices, &window.TroubleServices, &anyFieldUpdated)
+
+	// If no fields were updated, return an error indicating no update was made.
+	if !anyFieldUpdated {
+		return errors.New("no fields provided for update")
+	} else {
+		// Validate the updated window and update the timestamp.
+		if err := window.Validate(); err != nil {
+			return err
+		}
+		window.UpdatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+// MapCreateIncidentLinkDTOToModel converts a CreateIncidentLinkDTO into an IncidentLink model from the given incident.
+func MapCreateIncidentLinkDTOToModel(incidentID string, dto *dtos.CreateIncidentLinkDTO) (*models.IncidentLink, error) {
+	link := &models.IncidentLink{
+		ID:               uuid.NewString(),     // Generate a new unique ID for the link.
+		IncidentID:       incidentID,           // Link from the incident of the request.
+		LinkedIncidentID: dto.LinkedIncidentID, // Map the linked incident from DTO.
+		Type:             dto.Type,             // Map the link type from DTO.
+		Creator:          dto.Creator,          // Map the creator from DTO.
+		CreatedAt:        time.Now().UTC(),     // Set the creation time.
+	}
+
+	// Validate the newly created link model.
+	if err := link.Validate(); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// MapCreateSLODTOToModel converts a CreateSLODTO into an SLO model and validates it.
+func MapCreateSLODTOToModel(dto *dtos.CreateSLODTO) (*models.SLO, error) {
+	currentTime := time.Now().UTC() // Capture the current time in UTC for timestamps.
+
+	slo := &models.SLO{
+		ID:          uuid.NewString(), // Generate a new unique ID for the objective.
+		Description: dto.Description,  // Map the description from DTO.
+		TargetType:  dto.TargetType,   // Map the type of the target from DTO.
+		Target:      dto.Target,       // Map the target from DTO.
+		Objective:   dto.Objective,    // Map the required availability from DTO.
+		Period:      dto.Period,       // Map the period from DTO.
+		CreatedAt:   currentTime,      // Set the creation time.
+		UpdatedAt:   currentTime,      // Set the update time.
+	}
+
+	// The error budgets are computed per quarter unless another period is given.
+	if slo.Period == "" {
+		slo.Period = models.QuarterSLOPeriod
+	}
+
+	// Validate the newly created objective model.
+	if err := slo.Validate(); err != nil {
+		return nil, err
+	}
+
+	return slo, nil
+}
+
+// MapUpdateSLODTOToModel updates an existing SLO model with data from an UpdateSLODTO.
+func MapUpdateSLODTOToModel(dto *dtos.UpdateSLODTO, slo *models.SLO) error {
+	anyFieldUpdated := false // Track if any field has been updated.
+
+	updateField(dto.Description, &slo.Description, &anyFieldUpdated)
+	updateField(dto.TargetType, &slo.TargetType, &anyFieldUpdated)
+	updateField(dto.Target, &slo.Target, &anyFieldUpdated)
+	updateField(dto.Objective, &slo.Objective, &anyFieldUpdated)
+	updateField(dto.Period, &slo.Period, &anyFieldUpdated)
+
+	// If no fields were updated, return an error indicating no update was made.
+	if !anyFieldUpdated {
+		return errors.New("no fields provided for update")
+	} else {
+		// Validate the updated objective and update the timestamp.
+		if err := slo.Validate(); err != nil {
+			return err
+		}
+		slo.UpdatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+// MapBulkIncidentsDTOToAction converts a BulkIncidentsDTO into a bulk action on incidents and validates it.
+func MapBulkIncidentsDTOToAction(dto *dtos.BulkIncidentsDTO) (*models.BulkAction, error) {
+	action := &models.BulkAction{
+		Name:   dto.Action,       // Map the action from DTO.
+		Labels: dto.Labels,       // Map the labels to set from DTO.
+		At:     time.Now().UTC(), // Apply the action at the current time.
+	}
+
+	// Validate the action against the ones supported by incidents.
+	if err := action.Validate(models.IncidentBulkActions); err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
+
+// MapBulkRulesDTOToAction converts a BulkRulesDTO into a bulk action on rules and validates it.
+func MapBulkRulesDTOToAction(dto *dtos.BulkRulesDTO) (*models.BulkAction, error) {
+	currentTime := time.Now().UTC() // Capture the current time in UTC for the action.
+
+	action := &models.BulkAction{
+		Name:       dto.Action,     // Map the action from DTO.
+		Labels:     dto.Labels,     // Map the incident labels to set from DTO.
+		MuteReason: dto.MuteReason, // Map the mute reason from DTO.
+		MutedBy:    dto.MutedBy,    // Map the muting user from DTO.
+		At:         currentTime,    // Apply the action at the current time.
+	}
+
+	// Map the time the rules are automatically unmuted at, which must be in the future.
+	if dto.MuteUntil != nil {
+		if !dto.MuteUntil.After(currentTime) {
+			return nil, errors.New("mute_until must be in the future")
+		}
+		muteUntil := dto.MuteUntil.UTC()
+		action.MuteUntil = &muteUntil
+	}
+
+	// Validate the action against the ones supported by rules.
+	if err := action.Validate(models.RuleBulkActions); err != nil {
+		return nil, err
+	}
+
+	return action, nil
+}
@@ -0,0 +1,20 @@
+package dtos // dnywonnt.me/alerts2incidents/internal/api/v1/dtos
+
+import "time"
+
+// BulkIncidentsDTO is used to receive data from API requests to apply an action to several incidents at once.
+type BulkIncidentsDTO struct {
+	IDs    []string `json:"ids"`    // Incidents to apply the action to; the incidents matching the filter of the query if empty.
+	Action string   `json:"action"` // Action to apply: close, confirm, set_labels or delete.
+	Labels []string `json:"labels"` // Labels to set by the set_labels action.
+}
+
+// BulkRulesDTO is used to receive data from API requests to apply an action to several rules at once.
+type BulkRulesDTO struct {
+	IDs        []string   `json:"ids"`         // Rules to apply the action to; the rules matching the filter of the query if empty.
+	Action     string     `json:"action"`      // Action to apply: mute, unmute, set_labels or delete.
+	Labels     []string   `json:"labels"`      // Incident labels to set by the set_labels action.
+	MuteUntil  *time.Time `json:"mute_until"`  // Time the rules are automatically unmuted at, for the mute action.
+	MuteReason string     `json:"mute_reason"` // Reason the rules are muted for, for the mute action.
+	MutedBy    string     `json:"muted_by"`    // Identifier of the user muting the rules, for the mute action.
+}
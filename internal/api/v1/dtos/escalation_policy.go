@@ -0,0 +1,19 @@
+package dtos // dnywonnt.me/alerts2incidents/internal/api/v1/dtos
+
+import "dnywonnt.me/alerts2incidents/internal/models"
+
+// CreateEscalationPolicyDTO is used to capture incoming data from API requests to create a new escalation policy.
+type CreateEscalationPolicyDTO struct {
+	Description string                  `json:"description"` // Description of the policy.
+	RuleID      *string                 `json:"rule_id"`     // Rule the policy is attached to; mutually exclusive with the departament.
+	Departament *string                 `json:"departament"` // Departament the policy is attached to; mutually exclusive with the rule.
+	Steps       []models.EscalationStep `json:"steps"`       // Escalation steps in the order of execution.
+}
+
+// UpdateEscalationPolicyDTO is used to capture incoming data from API requests to update an existing escalation policy.
+type UpdateEscalationPolicyDTO struct {
+	Description *string                  `json:"description,omitempty"` // Optional update to the description of the policy.
+	RuleID      *string                  `json:"rule_id,omitempty"`     // Optional update to the rule the policy is attached to; clears the departament.
+	Departament *string                  `json:"departament,omitempty"` // Optional update to the departament the policy is attached to; clears the rule.
+	Steps       *[]models.EscalationStep `json:"steps,omitempty"`       // Optional update to the escalation steps.
+}
@@ -21,6 +21,7 @@ type CreateIncidentDTO struct {
 	Labels          []string  `json:"labels"`           // Labels associated with the incident for categorization.
 	IsDowntime      bool      `json:"is_downtime"`      // Indicates if the incident causes downtime.
 	PostmortemLink  string    `json:"postmortem_link"`  // Link to the postmortem report if available.
+	Severity        string    `json:"severity"`         // Severity of the incident, "SEV4" if not set.
 	Creator         string    `json:"creator"`          // Identifier of the user creating the incident.
 }
 
@@ -45,4 +46,5 @@ type UpdateIncidentDTO struct {
 	Labels           *[]string  `json:"labels,omitempty"`            // Optional updated list of labels.
 	IsDowntime       *bool      `json:"is_downtime,omitempty"`       // Optional updated downtime status.
 	PostmortemLink   *string    `json:"postmortem_link,omitempty"`   // Optional updated link to the postmortem report.
+	Severity         *string    `json:"severity,omitempty"`          // Optional updated severity of the incident.
 }
@@ -0,0 +1,21 @@
+package dtos // dnywonnt.me/alerts2incidents/internal/api/v1/dtos
+
+// CreateIncidentLinkDTO is used to receive data from API requests to link an incident to another one.
+type CreateIncidentLinkDTO struct {
+	LinkedIncidentID string `json:"linked_incident_id"` // Incident the link points to.
+	Type             string `json:"type"`               // Type of the link: child_of, duplicate_of, related_to or split_from.
+	Creator          string `json:"creator"`            // Identifier of the user creating the link.
+}
+
+// MergeIncidentsDTO is used to receive data from API requests to merge duplicate incidents into a primary one.
+type MergeIncidentsDTO struct {
+	IncidentIDs []string `json:"incident_ids"` // Duplicate incidents to merge into the primary one.
+	Creator     string   `json:"creator"`      // Identifier of the user merging the incidents.
+}
+
+// SplitIncidentDTO is used to receive data from API requests to split alerts of an incident into a new incident.
+type SplitIncidentDTO struct {
+	Fingerprints []string `json:"fingerprints"` // Fingerprints of the alerts to move into the new incident.
+	Summary      string   `json:"summary"`      // Summary of the new incident; the summary of the original one if empty.
+	Creator      string   `json:"creator"`      // Identifier of the user splitting the incident.
+}
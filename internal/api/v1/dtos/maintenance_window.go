@@ -0,0 +1,28 @@
+package dtos // dnywonnt.me/alerts2incidents/internal/api/v1/dtos
+
+import "time"
+
+// CreateMaintenanceWindowDTO is used to capture incoming data from API requests to create a new maintenance window.
+type CreateMaintenanceWindowDTO struct {
+	Description     string    `json:"description"`      // Description of the planned work.
+	FromAt          time.Time `json:"from_at"`          // Start of the (first occurrence of the) window.
+	ToAt            time.Time `json:"to_at"`            // End of the (first occurrence of the) window.
+	Recurrence      string    `json:"recurrence"`       // Recurrence of the window: none, daily or weekly; none if empty.
+	Timezone        string    `json:"timezone"`         // IANA time zone the window recurs in; UTC if empty.
+	RuleIDs         []string  `json:"rule_ids"`         // Rules the window applies to.
+	Labels          []string  `json:"labels"`           // Incident labels of the rules the window applies to.
+	TroubleServices []string  `json:"trouble_services"` // Trouble services of the rules the window applies to.
+	Creator         string    `json:"creator"`          // Identifier of the user creating the window.
+}
+
+// UpdateMaintenanceWindowDTO is used to capture incoming data from API requests to update an existing maintenance window.
+type UpdateMaintenanceWindowDTO struct {
+	Description     *string    `json:"description,omitempty"`      // Optional update to the description.
+	FromAt          *time.Time `json:"from_at,omitempty"`          // Optional update to the start of the window.
+	ToAt            *time.Time `json:"to_at,omitempty"`            // Optional update to the end of the window.
+	Recurrence      *string    `json:"recurrence,omitempty"`       // Optional update to the recurrence.
+	Timezone        *string    `json:"timezone,omitempty"`         // Optional update to the time zone.
+	RuleIDs         *[]string  `json:"rule_ids,omitempty"`         // Optional update to the rules the window applies to.
+	Labels          *[]string  `json:"labels,omitempty"`           // Optional update to the labels the window applies to.
+	TroubleServices *[]string  `json:"trouble_services,omitempty"` // Optional update to the trouble services the window applies to.
+}
@@ -0,0 +1,46 @@
+package dtos // dnywonnt.me/alerts2incidents/internal/api/v1/dtos
+
+import "time"
+
+// CreateOnCallUserDTO is used to capture incoming data from API requests to create a new on-call user.
+type CreateOnCallUserDTO struct {
+	Name       string `json:"name"`        // Display name of the user.
+	TelegramID int64  `json:"telegram_id"` // Telegram user ID used to message the user directly.
+	Email      string `json:"email"`       // Email address of the user.
+	LDAPLogin  string `json:"ldap_login"`  // LDAP login of the user.
+}
+
+// UpdateOnCallUserDTO is used to capture incoming data from API requests to update an existing on-call user.
+type UpdateOnCallUserDTO struct {
+	Name       *string `json:"name,omitempty"`        // Optional update to the display name of the user.
+	TelegramID *int64  `json:"telegram_id,omitempty"` // Optional update to the Telegram user ID.
+	Email      *string `json:"email,omitempty"`       // Optional update to the email address.
+	LDAPLogin  *string `json:"ldap_login,omitempty"`  // Optional update to the LDAP login.
+}
+
+// CreateOnCallScheduleDTO is used to capture incoming data from API requests to create a new on-call schedule.
+type CreateOnCallScheduleDTO struct {
+	Name          string    `json:"name"`           // Unique name of the schedule.
+	Description   string    `json:"description"`    // Description of the schedule.
+	Timezone      string    `json:"timezone"`       // IANA time zone the shifts are handed off in.
+	Rotation      string    `json:"rotation"`       // Length of a shift, daily or weekly.
+	RotationStart time.Time `json:"rotation_start"` // Start of the shift of the first user of the rotation.
+	UserIDs       []string  `json:"user_ids"`       // Users of the rotation in the order of their shifts.
+}
+
+// UpdateOnCallScheduleDTO is used to capture incoming data from API requests to update an existing on-call schedule.
+type UpdateOnCallScheduleDTO struct {
+	Name          *string    `json:"name,omitempty"`           // Optional update to the name of the schedule.
+	Description   *string    `json:"description,omitempty"`    // Optional update to the description.
+	Timezone      *string    `json:"timezone,omitempty"`       // Optional update to the time zone.
+	Rotation      *string    `json:"rotation,omitempty"`       // Optional update to the length of a shift.
+	RotationStart *time.Time `json:"rotation_start,omitempty"` // Optional update to the start of the rotation.
+	UserIDs       *[]string  `json:"user_ids,omitempty"`       // Optional update to the users of the rotation.
+}
+
+// CreateOnCallOverrideDTO is used to capture incoming data from API requests to override the on-call user of a schedule.
+type CreateOnCallOverrideDTO struct {
+	UserID string    `json:"user_id"` // User on call during the override.
+	FromAt time.Time `json:"from_at"` // Start of the override.
+	ToAt   time.Time `json:"to_at"`   // End of the override.
+}
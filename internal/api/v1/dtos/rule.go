@@ -5,6 +5,9 @@ import "time"
 // CreateRuleDTO is used to capture incoming data from API requests to create a new rule.
 type CreateRuleDTO struct {
 	IsMuted                          bool            `json:"is_muted"`                            // Indicates if the rule should be muted.
+	MuteUntil                        *time.Time      `json:"mute_until"`                          // Time the rule should be automatically unmuted at.
+	MuteReason                       string          `json:"mute_reason"`                         // Reason the rule is muted for.
+	MutedBy                          string          `json:"muted_by"`                            // Identifier of the user muting the rule.
 	Description                      string          `json:"description"`                         // Description of the rule.
 	AlertsSummaryConditions          []string        `json:"alerts_summary_conditions"`           // Conditions that summarize alerts.
 	AlertsActivityIntervalConditions []time.Duration `json:"alerts_activity_interval_conditions"` // List of time durations for alert activity intervals.
@@ -20,11 +23,18 @@ type CreateRuleDTO struct {
 	SetIncidentFailureType           string          `json:"set_incident_failure_type"`           // Type of failure associated with the incident.
 	SetIncidentLabels                []string        `json:"set_incident_labels"`                 // Labels associated with the incident.
 	SetIncidentIsDowntime            bool            `json:"set_incident_is_downtime"`            // Indicates if the incident causes downtime.
+	FlappingWindow                   time.Duration   `json:"flapping_window"`                     // Window the state changes of an incident are counted within to detect flapping.
+	FlappingThreshold                int             `json:"flapping_threshold"`                  // Number of state changes within the window that makes an incident flapping.
+	SetIncidentSeverity              string          `json:"set_incident_severity"`               // Severity to be set for an incident, "SEV4" if not set.
+	DeriveIncidentSeverity           bool            `json:"derive_incident_severity"`            // Indicates if the incident takes the highest severity of the matching alerts.
 }
 
 // UpdateRuleDTO is used to capture incoming data from API requests to update an existing rule.
 type UpdateRuleDTO struct {
-	IsMuted                          *bool            `json:"is_muted,omitempty"`                            // Optional update to the mute status.
+	IsMuted                          *bool            `json:"is_muted,omitempty"`                            // Optional update to the mute status; a change of it resets the mute details.
+	MuteUntil                        *time.Time       `json:"mute_until,omitempty"`                          // Optional update to the time the rule is automatically unmuted at.
+	MuteReason                       *string          `json:"mute_reason,omitempty"`                         // Optional update to the reason the rule is muted for.
+	MutedBy                          *string          `json:"muted_by,omitempty"`                            // Optional update to the user who muted the rule.
 	Description                      *string          `json:"description,omitempty"`                         // Optional update to the rule's description.
 	AlertsSummaryConditions          *[]string        `json:"alerts_summary_conditions,omitempty"`           // Optional update to the conditions that summarize alerts.
 	AlertsActivityIntervalConditions *[]time.Duration `json:"alerts_activity_interval_conditions,omitempty"` // Optional update to the list of time durations for alert activity intervals.
@@ -40,4 +50,8 @@ type UpdateRuleDTO struct {
 	SetIncidentFailureType           *string          `json:"set_incident_failure_type,omitempty"`           // Optional update to the type of failure associated with the incident.
 	SetIncidentLabels                *[]string        `json:"set_incident_labels,omitempty"`                 // Optional update to the labels associated with the incident.
 	SetIncidentIsDowntime            *bool            `json:"set_incident_is_downtime,omitempty"`            // Optional update to whether the incident causes downtime.
+	FlappingWindow                   *time.Duration   `json:"flapping_window,omitempty"`                     // Optional update to the flapping detection window.
+	FlappingThreshold                *int             `json:"flapping_threshold,omitempty"`                  // Optional update to the flapping detection threshold.
+	SetIncidentSeverity              *string          `json:"set_incident_severity,omitempty"`               // Optional update to the severity set for an incident.
+	DeriveIncidentSeverity           *bool            `json:"derive_incident_severity,omitempty"`            // Optional update to whether the incident takes the highest severity of the matching alerts.
 }
@@ -0,0 +1,19 @@
+package dtos // dnywonnt.me/alerts2incidents/internal/api/v1/dtos
+
+// CreateSLODTO is used to capture incoming data from API requests to create a new service-level objective.
+type CreateSLODTO struct {
+	Description string  `json:"description"` // Description of the objective.
+	TargetType  string  `json:"target_type"` // Type of the target: trouble_service or sale_channel.
+	Target      string  `json:"target"`      // Trouble service or sale channel the objective is defined for.
+	Objective   float64 `json:"objective"`   // Required availability in percent, e.g. 99.9.
+	Period      string  `json:"period"`      // Calendar period of the error budget: month or quarter; quarter by default.
+}
+
+// UpdateSLODTO is used to capture incoming data from API requests to update an existing service-level objective.
+type UpdateSLODTO struct {
+	Description *string  `json:"description,omitempty"` // Optional update to the description.
+	TargetType  *string  `json:"target_type,omitempty"` // Optional update to the type of the target.
+	Target      *string  `json:"target,omitempty"`      // Optional update to the target.
+	Objective   *float64 `json:"objective,omitempty"`   // Optional update to the required availability.
+	Period      *string  `json:"period,omitempty"`      // Optional update to the period of the error budget.
+}
@@ -0,0 +1,171 @@
+package v1 // dnywonnt.me/alerts2incidents/internal/api/v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"dnywonnt.me/alerts2incidents/internal/api/v1/openapi"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorCode is a machine-readable code of an API error; unlike the messages, the codes don't change between the releases.
+type ErrorCode string
+
+// Codes of the API errors.
+const (
+	ValidationErrorCode   ErrorCode = "validation_failed" // The request has a malformed body, field or query parameter
+	UnauthorizedErrorCode ErrorCode = "unauthorized"      // The request isn't authenticated
+	ForbiddenErrorCode    ErrorCode = "forbidden"         // The user isn't allowed to perform the request
+	NotFoundErrorCode     ErrorCode = "not_found"         // The requested record or route doesn't exist
+	ConflictErrorCode     ErrorCode = "conflict"          // The record has been changed concurrently
+	InternalErrorCode     ErrorCode = "internal_error"    // The request has failed on the server side
+)
+
+// ErrorCodes lists all the codes of the API errors.
+var ErrorCodes = []ErrorCode{
+	ValidationErrorCode, UnauthorizedErrorCode, ForbiddenErrorCode, NotFoundErrorCode, ConflictErrorCode, InternalErrorCode,
+}
+
+// internalErrorMessage is the message of the internal errors; their causes, e.g. the database errors, are only logged.
+const internalErrorMessage = "internal server error"
+
+// APIError is an error of a request, attached to the Gin context by the handlers and rendered by ErrorMiddleware.
+type APIError struct {
+	Status  int           // HTTP status of the response
+	Code    ErrorCode     // Machine-readable code of the error
+	Message string        // Human-readable description of the error, safe to show to the client
+	Details []*FieldError // Errors of the individual fields, for the validation errors
+	Err     error         // Cause of the error, if any; it is never shown to the client
+}
+
+// FieldError describes a field of the request that has failed the validation.
+type FieldError struct {
+	Field   string `json:"field" validate:"required"`   // Path of the field, e.g. steps[0].delay
+	Rule    string `json:"rule" validate:"required"`    // Validation rule the field has failed, e.g. required
+	Param   string `json:"param,omitempty"`             // Parameter of the rule, e.g. the allowed values of oneof
+	Message string `json:"message" validate:"required"` // Human-readable description of the failure
+}
+
+// ErrorResponse is the body of the error responses of the API.
+type ErrorResponse struct {
+	Code      ErrorCode     `json:"code" validate:"required"`       // Machine-readable code of the error
+	Message   string        `json:"message" validate:"required"`    // Human-readable description of the error
+	Details   []*FieldError `json:"details,omitempty"`              // Errors of the individual fields, for the validation errors
+	RequestID string        `json:"request_id" validate:"required"` // ID of the request, also returned in the X-Request-ID header
+}
+
+// Error returns the message of the error along with its cause.
+func (e *APIError) Error() string {
+	if e.Err != nil && e.Err.Error() != e.Message {
+		return fmt.Sprintf("%s: %s", e.Message, e.Err.Error())
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause of the error.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// NewValidationError creates an error of an invalid request. The errors of the validator and of the query validation
+// are broken down into the details of the fields, the other errors, e.g. of decoding the JSON body, are described by their message.
+func NewValidationError(err error) *APIError {
+	var parameterErr *openapi.ParameterError
+	if errors.As(err, &parameterErr) {
+		return &APIError{
+			Status:  http.StatusBadRequest,
+			Code:    ValidationErrorCode,
+			Message: "query validation failed",
+			Details: []*FieldError{{Field: parameterErr.Name, Rule: parameterErr.Rule, Message: parameterErr.Error()}},
+			Err:     err,
+		}
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return &APIError{Status: http.StatusBadRequest, Code: ValidationErrorCode, Message: err.Error(), Err: err}
+	}
+
+	details := make([]*FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		details = append(details, newFieldError(fieldErr))
+	}
+	return &APIError{
+		Status:  http.StatusBadRequest,
+		Code:    ValidationErrorCode,
+		Message: "request validation failed",
+		Details: details,
+		Err:     err,
+	}
+}
+
+// NewUnauthorizedError creates an error of an unauthenticated request.
+func NewUnauthorizedError(message string) *APIError {
+	return &APIError{Status: http.StatusUnauthorized, Code: UnauthorizedErrorCode, Message: message}
+}
+
+// NewForbiddenError creates an error of a request the user isn't allowed to perform.
+func NewForbiddenError(message string) *APIError {
+	return &APIError{Status: http.StatusForbidden, Code: ForbiddenErrorCode, Message: message}
+}
+
+// NewNotFoundError creates an error of a missing record, e.g. "incident not found".
+func NewNotFoundError(message string) *APIError {
+	return &APIError{Status: http.StatusNotFound, Code: NotFoundErrorCode, Message: message}
+}
+
+// NewConflictError creates an error of a record changed concurrently.
+func NewConflictError(err error) *APIError {
+	return &APIError{Status: http.StatusConflict, Code: ConflictErrorCode, Message: err.Error(), Err: err}
+}
+
+// NewInternalError creates an error of a request failed on the server side; the message of the cause isn't shown to the client.
+func NewInternalError(err error) *APIError {
+	return &APIError{Status: http.StatusInternalServerError, Code: InternalErrorCode, Message: internalErrorMessage, Err: err}
+}
+
+// newFieldError describes an error of the validator. The path of the field drops the name of the validated struct,
+// e.g. CreateIncidentDTO.summary becomes summary.
+func newFieldError(fieldErr validator.FieldError) *FieldError {
+	field := fieldErr.Namespace()
+	if _, path, ok := strings.Cut(field, "."); ok {
+		field = path
+	}
+
+	message := fmt.Sprintf("%s must satisfy the %s rule", field, fieldErr.Tag())
+	switch fieldErr.Tag() {
+	case "required", "required_if", "required_if_m", "required_with", "required_without":
+		message = fmt.Sprintf("%s is required", field)
+	case "oneof":
+		message = fmt.Sprintf("%s must be one of: %s", field, fieldErr.Param())
+	case "min", "gte":
+		message = fmt.Sprintf("%s must be at least %s", field, boundDescription(fieldErr))
+	case "max", "lte":
+		message = fmt.Sprintf("%s must be at most %s", field, boundDescription(fieldErr))
+	case "gt":
+		message = fmt.Sprintf("%s must be greater than %s", field, fieldErr.Param())
+	case "lt":
+		message = fmt.Sprintf("%s must be less than %s", field, fieldErr.Param())
+	}
+
+	return &FieldError{
+		Field:   field,
+		Rule:    fieldErr.Tag(),
+		Param:   fieldErr.Param(),
+		Message: message,
+	}
+}
+
+// boundDescription describes the bound of a min or max rule, which limits the length of the strings and the collections.
+func boundDescription(fieldErr validator.FieldError) string {
+	switch fieldErr.Kind() {
+	case reflect.String:
+		return fieldErr.Param() + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fieldErr.Param() + " items in size"
+	}
+	return fieldErr.Param()
+}
@@ -33,7 +33,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to unmarshal request JSON data")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -45,7 +45,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"baseDN": apiCfg.LDAP.BaseDN,
 				"error":  err.Error(),
 			}).Error("Failed to extract domain from baseDN string")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -60,7 +60,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"bindDN":  bindDN,
 				"error":   err.Error(),
 			}).Error("Failed to connect to LDAP server")
-			c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			c.Error(v1.NewUnauthorizedError("invalid login or password"))
 			return
 		}
 		defer ldapConn.Close()
@@ -73,7 +73,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"login":  authRequest.Login,
 				"error":  err.Error(),
 			}).Error("Failed to search LDAP user")
-			c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			c.Error(v1.NewUnauthorizedError("LDAP user not found"))
 			return
 		}
 
@@ -83,7 +83,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"login":         authRequest.Login,
 				"allowedGroups": apiCfg.LDAP.AllowedGroups,
 			}).Error("LDAP user is not in allowed group")
-			c.JSON(http.StatusForbidden, gin.H{"message": "permission denied"})
+			c.Error(v1.NewForbiddenError("permission denied"))
 			return
 		}
 
@@ -94,7 +94,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"login": authRequest.Login,
 				"error": err.Error(),
 			}).Error("Failed to generate JWT token")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -105,7 +105,7 @@ func authenticateLDAPUser(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"login": authRequest.Login,
 				"error": err.Error(),
 			}).Error("Failed to retrieve LDAP user name")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -0,0 +1,161 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// bulkIncidents returns a handler for applying an action to the incidents with the IDs of the request
+// or, if no IDs are given, to the incidents matching the filter of the query.
+func bulkIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.BulkIncidentsDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		action, err := v1.MapBulkIncidentsDTOToAction(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to bulk action")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		filterBy, err := buildFilterForIncidents(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"filterBy": filterBy,
+				"error":    err.Error(),
+			}).Error("Failed to build filter for incidents")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		startTime, endTime, err := parseBulkSelection(c, dto.IDs, filterBy)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to select incidents for bulk action")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		results, err := repo.ApplyBulkActionToIncidents(c, dto.IDs, filterBy, startTime.UTC(), endTime.UTC(), action)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"action": action.Name,
+				"error":  err.Error(),
+			}).Error("Failed to apply bulk action to incidents")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"action":  action.Name,
+			"counts":  countBulkResults(results),
+			"results": results,
+		})
+	}
+}
+
+// bulkRules returns a handler for applying an action to the rules with the IDs of the request
+// or, if no IDs are given, to the rules matching the filter of the query.
+func bulkRules(repo *repositories.RulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.BulkRulesDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		action, err := v1.MapBulkRulesDTOToAction(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to bulk action")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		filterBy, err := buildFilterForRules(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"filterBy": filterBy,
+				"error":    err.Error(),
+			}).Error("Failed to build filter for rules")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		startTime, endTime, err := parseBulkSelection(c, dto.IDs, filterBy)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to select rules for bulk action")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		results, err := repo.ApplyBulkActionToRules(c, dto.IDs, filterBy, startTime.UTC(), endTime.UTC(), action)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"action": action.Name,
+				"error":  err.Error(),
+			}).Error("Failed to apply bulk action to rules")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"action":  action.Name,
+			"counts":  countBulkResults(results),
+			"results": results,
+		})
+	}
+}
+
+// parseBulkSelection parses the time range of the query and checks that a bulk operation selects the records
+// either by IDs or by a filter, so an empty request doesn't apply the action to all records.
+func parseBulkSelection(c *gin.Context, ids []string, filterBy map[string]interface{}) (time.Time, time.Time, error) {
+	startTime, endTime, err := parseTimeRange(c)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	hasFilter := len(filterBy) > 0 || (!startTime.IsZero() && !endTime.IsZero())
+	switch {
+	case len(ids) > 0 && hasFilter:
+		return time.Time{}, time.Time{}, errors.New("either ids or a filter must be provided, not both")
+	case len(ids) == 0 && !hasFilter:
+		return time.Time{}, time.Time{}, errors.New("either ids or a filter must be provided")
+	}
+
+	return startTime, endTime, nil
+}
+
+// countBulkResults counts the results of a bulk operation by their statuses.
+func countBulkResults(results []*models.BulkItemResult) map[string]int {
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	return counts
+}
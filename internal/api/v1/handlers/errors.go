@@ -0,0 +1,25 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"errors"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+)
+
+// repositoryError converts an error of a repository to the API error: a missing record is reported as not found
+// with the message, e.g. "incident not found", a concurrent update as a conflict, an invalid merge or split of incidents
+// as a validation error, and any other error, e.g. of the database, as an internal error not exposing its details.
+func repositoryError(err error, notFoundMessage string) *v1.APIError {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return v1.NewNotFoundError(notFoundMessage)
+	case errors.Is(err, repositories.ErrVersionConflict):
+		return v1.NewConflictError(repositories.ErrVersionConflict)
+	case errors.Is(err, repositories.ErrAlreadyExists):
+		return v1.NewConflictError(err)
+	case errors.Is(err, repositories.ErrInvalidIncidentOperation):
+		return v1.NewValidationError(err)
+	}
+	return v1.NewInternalError(err)
+}
@@ -0,0 +1,153 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterEscalationPoliciesRoutes sets up the routing for escalation policy API endpoints.
+func RegisterEscalationPoliciesRoutes(router *gin.Engine, repo *repositories.EscalationPoliciesRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/escalation-policies")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:id", getEscalationPolicy(repo))
+	routerGroup.GET("/", getEscalationPolicies(repo))
+	routerGroup.POST("/", createEscalationPolicy(repo))
+	routerGroup.PUT("/:id", updateEscalationPolicy(repo))
+	routerGroup.DELETE("/:id", deleteEscalationPolicy(repo))
+}
+
+// getEscalationPolicy returns a handler for retrieving a single escalation policy by its ID.
+func getEscalationPolicy(repo *repositories.EscalationPoliciesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, err := repo.GetEscalationPolicy(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve escalation policy")
+			c.Error(repositoryError(err, "escalation policy not found"))
+			return
+		}
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// getEscalationPolicies returns a handler for retrieving all escalation policies.
+// There are only a few policies, at most one per rule and departament, so they aren't paginated.
+func getEscalationPolicies(repo *repositories.EscalationPoliciesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies, err := repo.GetEscalationPolicies(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to retrieve escalation policies")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"escalation_policies": policies,
+		})
+	}
+}
+
+// createEscalationPolicy returns a handler for creating a new escalation policy based on provided data.
+func createEscalationPolicy(repo *repositories.EscalationPoliciesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateEscalationPolicyDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		policy, err := v1.MapCreateEscalationPolicyDTOToModel(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to escalation policy model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateEscalationPolicy(c, policy); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to create escalation policy")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// updateEscalationPolicy returns a handler for updating an existing escalation policy.
+func updateEscalationPolicy(repo *repositories.EscalationPoliciesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.UpdateEscalationPolicyDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		policy, err := repo.GetEscalationPolicy(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve escalation policy")
+			c.Error(repositoryError(err, "escalation policy not found"))
+			return
+		}
+
+		if err := v1.MapUpdateEscalationPolicyDTOToModel(dto, policy); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to escalation policy model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.UpdateEscalationPolicy(c, policy); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to update escalation policy")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// deleteEscalationPolicy returns a handler for deleting an escalation policy by its ID.
+func deleteEscalationPolicy(repo *repositories.EscalationPoliciesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteEscalationPolicy(c, c.Param("id")); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to delete escalation policy")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
@@ -0,0 +1,35 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag returns the entity tag of the version of a record.
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// setVersionETag sets the ETag header of the response to the version of the record.
+func setVersionETag(c *gin.Context, version int) {
+	c.Header("ETag", versionETag(version))
+}
+
+// matchesIfMatch reports whether the If-Match header of the request allows updating the record of the version.
+// A request without the header is allowed, in which case the update is still checked against the version read by the request.
+func matchesIfMatch(c *gin.Context, version int) bool {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return true
+	}
+
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == versionETag(version) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Kinds of the values of the query filters of the lists.
+const (
+	stringQueryFilter = iota // The value is matched as is
+	boolQueryFilter          // The value is parsed as a boolean
+	intQueryFilter           // The value is parsed as an integer
+	arrayQueryFilter         // The parameter is repeated for the values of an array field
+)
+
+// queryFilter describes a query parameter filtering a list by the field of the same name.
+// The filters are shared by the handlers and the OpenAPI document, so the documented filters are the accepted ones.
+type queryFilter struct {
+	Name string // Name of the query parameter and of the filtered field
+	Kind int    // Kind of the value
+}
+
+// incidentQueryFilters are the filters of the incidents lists, exports, reports and bulk operations.
+var incidentQueryFilters = []queryFilter{
+	{"is_confirmed", boolQueryFilter},
+	{"quarter", intQueryFilter},
+	{"is_deploy", boolQueryFilter},
+	{"is_downtime", boolQueryFilter},
+	{"sale_channels", arrayQueryFilter},
+	{"trouble_services", arrayQueryFilter},
+	{"labels", arrayQueryFilter},
+	{"type", stringQueryFilter},
+	{"creator", stringQueryFilter},
+	{"status", stringQueryFilter},
+	{"departament", stringQueryFilter},
+	{"rule_id", stringQueryFilter},
+	{"failure_type", stringQueryFilter},
+	{"is_manageable", stringQueryFilter},
+	{"severity", stringQueryFilter},
+}
+
+// ruleQueryFilters are the filters of the rules lists and bulk operations, besides mute_expires_within.
+var ruleQueryFilters = []queryFilter{
+	{"is_muted", boolQueryFilter},
+	{"set_incident_is_downtime", boolQueryFilter},
+	{"set_incident_sale_channels", arrayQueryFilter},
+	{"set_incident_trouble_services", arrayQueryFilter},
+	{"set_incident_labels", arrayQueryFilter},
+	{"set_incident_departament", stringQueryFilter},
+	{"set_incident_is_manageable", stringQueryFilter},
+	{"set_incident_failure_type", stringQueryFilter},
+	{"set_incident_severity", stringQueryFilter},
+}
+
+// buildFilter constructs a filter map of the values of the query filters present in the request.
+func buildFilter(c *gin.Context, filters []queryFilter) (map[string]interface{}, error) {
+	filter := make(map[string]interface{})
+
+	for _, queryFilter := range filters {
+		if queryFilter.Kind == arrayQueryFilter {
+			if values := c.QueryArray(queryFilter.Name); len(values) > 0 {
+				filter[queryFilter.Name] = values
+			}
+			continue
+		}
+
+		valueStr := c.Query(queryFilter.Name)
+		if valueStr == "" {
+			continue
+		}
+
+		var value interface{} = valueStr
+		var err error
+		switch queryFilter.Kind {
+		case boolQueryFilter:
+			value, err = strconv.ParseBool(valueStr)
+		case intQueryFilter:
+			value, err = strconv.Atoi(valueStr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		filter[queryFilter.Name] = value
+	}
+
+	return filter, nil
+}
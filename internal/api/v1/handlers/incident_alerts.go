@@ -0,0 +1,40 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterIncidentAlertsRoutes sets up the routing of the endpoints of the alerts contributing to incidents.
+func RegisterIncidentAlertsRoutes(router *gin.Engine, repo *repositories.IncidentAlertsRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/incidents")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:id/alerts", getIncidentAlerts(repo))
+}
+
+// getIncidentAlerts returns a handler for retrieving the alerts that have contributed to an incident.
+func getIncidentAlerts(repo *repositories.IncidentAlertsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		alerts, err := repo.GetIncidentAlerts(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve incident alerts")
+			c.Error(repositoryError(err, "incident not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"alerts": alerts,
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterIncidentEventsRoutes sets up the routing of incident history endpoints.
+func RegisterIncidentEventsRoutes(router *gin.Engine, repo *repositories.IncidentEventsRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/incidents")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:id/events", getIncidentEvents(repo))
+}
+
+// getIncidentEvents returns a handler for retrieving the events of an incident in chronological order.
+func getIncidentEvents(repo *repositories.IncidentEventsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, err := repo.GetIncidentEvents(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve incident events")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"events": events,
+		})
+	}
+}
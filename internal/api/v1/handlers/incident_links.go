@@ -0,0 +1,160 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"errors"
+	"net/http"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterIncidentLinksRoutes sets up the routing of the endpoints for linking, merging and splitting incidents.
+func RegisterIncidentLinksRoutes(router *gin.Engine, incidentsRepo *repositories.IncidentsRepository,
+	linksRepo *repositories.IncidentLinksRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/incidents")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:id/links", getIncidentLinks(linksRepo))
+	routerGroup.POST("/:id/links", createIncidentLink(linksRepo))
+	routerGroup.DELETE("/:id/links/:linkId", deleteIncidentLink(linksRepo))
+	routerGroup.POST("/:id/merge", mergeIncidents(incidentsRepo))
+	routerGroup.POST("/:id/split", splitIncident(incidentsRepo))
+}
+
+// getIncidentLinks returns a handler for retrieving the links from and to an incident.
+func getIncidentLinks(repo *repositories.IncidentLinksRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		links, err := repo.GetIncidentLinks(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve incident links")
+			c.Error(repositoryError(err, "incident not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"links": links,
+		})
+	}
+}
+
+// createIncidentLink returns a handler for linking an incident to another one.
+func createIncidentLink(repo *repositories.IncidentLinksRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateIncidentLinkDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		link, err := v1.MapCreateIncidentLinkDTOToModel(c.Param("id"), dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to incident link model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateIncidentLink(c, link); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to create incident link")
+			c.Error(repositoryError(err, "incident not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, link)
+	}
+}
+
+// deleteIncidentLink returns a handler for deleting a link from or to an incident.
+func deleteIncidentLink(repo *repositories.IncidentLinksRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteIncidentLink(c, c.Param("id"), c.Param("linkId")); err != nil {
+			log.WithFields(log.Fields{
+				"id":     c.Param("id"),
+				"linkId": c.Param("linkId"),
+				"error":  err.Error(),
+			}).Error("Failed to delete incident link")
+			c.Error(repositoryError(err, "incident link not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
+
+// mergeIncidents returns a handler for merging duplicate incidents into the incident of the request.
+func mergeIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.MergeIncidentsDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+		if dto.Creator == "" {
+			c.Error(v1.NewValidationError(errors.New("creator is required")))
+			return
+		}
+
+		incident, err := repo.MergeIncidents(c, c.Param("id"), dto.IncidentIDs, dto.Creator)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":          c.Param("id"),
+				"incidentIDs": dto.IncidentIDs,
+				"error":       err.Error(),
+			}).Error("Failed to merge incidents")
+			c.Error(repositoryError(err, "incident not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, incident)
+	}
+}
+
+// splitIncident returns a handler for splitting alerts of the incident of the request into a new incident.
+func splitIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.SplitIncidentDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+		if dto.Creator == "" {
+			c.Error(v1.NewValidationError(errors.New("creator is required")))
+			return
+		}
+
+		incident, err := repo.SplitIncident(c, c.Param("id"), dto.Fingerprints, dto.Summary, dto.Creator)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":           c.Param("id"),
+				"fingerprints": dto.Fingerprints,
+				"error":        err.Error(),
+			}).Error("Failed to split incident")
+			c.Error(repositoryError(err, "incident not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, incident)
+	}
+}
@@ -22,9 +22,11 @@ func RegisterIncidentsRoutes(router *gin.Engine, repo *repositories.IncidentsRep
 	routerGroup.Use(v1.JWTMiddleware(apiCfg))
 
 	// Define route handlers for each operation.
+	routerGroup.GET("/export", exportIncidents(repo))
 	routerGroup.GET("/:id", getIncident(repo))
 	routerGroup.GET("/", getIncidents(repo))
 	routerGroup.POST("/", createIncident(repo))
+	routerGroup.POST("/bulk", bulkIncidents(repo))
 	routerGroup.PUT("/:id", updateIncident(repo))
 	routerGroup.DELETE("/:id", deleteIncident(repo))
 }
@@ -39,11 +41,12 @@ func getIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to retrieve incident")
-			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			c.Error(repositoryError(err, "incident not found"))
 			return
 		}
 
-		// Respond with the found incident.
+		// Respond with the found incident and its version as the entity tag.
+		setVersionETag(c, incident.Version)
 		c.JSON(http.StatusOK, incident)
 	}
 }
@@ -61,7 +64,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 				"page":  pageStr,
 				"error": err.Error(),
 			}).Error("Failed to parse pagination parameter")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -71,7 +74,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 				"pageSize": pageSizeStr,
 				"error":    err.Error(),
 			}).Error("Failed to parse pagination parameter")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -85,7 +88,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 					"startTime": startTimeStr,
 					"error":     err.Error(),
 				}).Error("Failed to parse start time")
-				c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+				c.Error(v1.NewValidationError(err))
 				return
 			}
 		}
@@ -99,7 +102,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 					"endTime": endTimeStr,
 					"error":   err.Error(),
 				}).Error("Failed to parse end time")
-				c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+				c.Error(v1.NewValidationError(err))
 				return
 			}
 		}
@@ -111,7 +114,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 				"filterBy": filterBy,
 				"error":    err.Error(),
 			}).Error("Failed to build filter for incidents")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -125,7 +128,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to retrieve incidents")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -135,7 +138,7 @@ func getIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to get total incidents count")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 		totalPages := utils.CalculatePages(totalIncidents, pageSize)
@@ -158,7 +161,7 @@ func createIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to unmarshal request JSON data")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -168,7 +171,7 @@ func createIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to map DTO to incident model")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -177,7 +180,7 @@ func createIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to create incident")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -194,7 +197,7 @@ func updateIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to unmarshal request JSON data")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -205,7 +208,18 @@ func updateIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to retrieve incident")
-			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			c.Error(repositoryError(err, "incident not found"))
+			return
+		}
+
+		// Reject the update if it's based on another version of the incident.
+		if !matchesIfMatch(c, incident.Version) {
+			log.WithFields(log.Fields{
+				"id":      c.Param("id"),
+				"ifMatch": c.GetHeader("If-Match"),
+				"version": incident.Version,
+			}).Error("Incident version doesn't match")
+			c.Error(v1.NewConflictError(repositories.ErrVersionConflict))
 			return
 		}
 
@@ -214,21 +228,22 @@ func updateIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to map DTO to incident model")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
-		// Save the updated incident in the repository.
+		// Save the updated incident in the repository, unless it has been modified concurrently.
 		if err := repo.UpdateIncident(c, incident); err != nil {
 			log.WithFields(log.Fields{
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to update incident")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(repositoryError(err, "incident not found"))
 			return
 		}
 
-		// Respond with the updated incident.
+		// Respond with the updated incident and its new version.
+		setVersionETag(c, incident.Version)
 		c.JSON(http.StatusOK, incident)
 	}
 }
@@ -242,7 +257,7 @@ func deleteIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to delete incident")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -253,70 +268,5 @@ func deleteIncident(repo *repositories.IncidentsRepository) gin.HandlerFunc {
 
 // buildFilterForIncidents constructs a filter map based on the query parameters.
 func buildFilterForIncidents(c *gin.Context) (map[string]interface{}, error) {
-	filter := make(map[string]interface{})
-
-	// Helper functions to add filters if they are not empty.
-	addToFilterIfNotEmpty := func(key, value string) {
-		if value != "" {
-			filter[key] = value
-		}
-	}
-
-	addToFilterIfArrayNotEmpty := func(key string, values []string) {
-		if len(values) > 0 {
-			filter[key] = values
-		}
-	}
-
-	// Helper function to parse and add values to the filter.
-	parseAndAddToFilter := func(key string, parseFunc func(string) (interface{}, error)) error {
-		valueStr := c.Query(key)
-		if valueStr != "" {
-			value, err := parseFunc(valueStr)
-			if err != nil {
-				return err
-			}
-			filter[key] = value
-		}
-		return nil
-	}
-
-	// Parsing specific query parameters.
-	parseBool := func(str string) (interface{}, error) {
-		return strconv.ParseBool(str)
-	}
-
-	parseInt := func(str string) (interface{}, error) {
-		return strconv.Atoi(str)
-	}
-
-	// Add specific filters based on query parameters.
-	if err := parseAndAddToFilter("is_confirmed", parseBool); err != nil {
-		return nil, err
-	}
-	if err := parseAndAddToFilter("quarter", parseInt); err != nil {
-		return nil, err
-	}
-	if err := parseAndAddToFilter("is_deploy", parseBool); err != nil {
-		return nil, err
-	}
-	if err := parseAndAddToFilter("is_downtime", parseBool); err != nil {
-		return nil, err
-	}
-
-	// Add array and simple filters.
-	addToFilterIfArrayNotEmpty("sale_channels", c.QueryArray("sale_channels"))
-	addToFilterIfArrayNotEmpty("trouble_services", c.QueryArray("trouble_services"))
-	addToFilterIfArrayNotEmpty("labels", c.QueryArray("labels"))
-
-	// Add remaining simple filters.
-	addToFilterIfNotEmpty("type", c.Query("type"))
-	addToFilterIfNotEmpty("creator", c.Query("creator"))
-	addToFilterIfNotEmpty("status", c.Query("status"))
-	addToFilterIfNotEmpty("departament", c.Query("departament"))
-	addToFilterIfNotEmpty("rule_id", c.Query("rule_id"))
-	addToFilterIfNotEmpty("failure_type", c.Query("failure_type"))
-	addToFilterIfNotEmpty("is_manageable", c.Query("is_manageable"))
-
-	return filter, nil
+	return buildFilter(c, incidentQueryFilters)
 }
@@ -0,0 +1,212 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/export"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"dnywonnt.me/alerts2incidents/internal/utils"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// exportFlushInterval is the number of rows after which the exported table is flushed to the client.
+const exportFlushInterval = 100
+
+// exportColumn describes a column of the exported incidents table.
+type exportColumn struct {
+	Name  string
+	Value func(*models.Incident) interface{}
+}
+
+// exportColumns lists the columns available for export in their default order; array fields are flattened into comma-separated strings.
+var exportColumns = []exportColumn{
+	{"id", func(i *models.Incident) interface{} { return i.ID }},
+	{"type", func(i *models.Incident) interface{} { return i.Type }},
+	{"status", func(i *models.Incident) interface{} { return i.Status }},
+	{"severity", func(i *models.Incident) interface{} { return i.Severity }},
+	{"summary", func(i *models.Incident) interface{} { return i.Summary }},
+	{"description", func(i *models.Incident) interface{} { return i.Description }},
+	{"from_at", func(i *models.Incident) interface{} { return formatExportTime(i.FromAt) }},
+	{"to_at", func(i *models.Incident) interface{} { return formatExportTime(i.ToAt) }},
+	{"is_confirmed", func(i *models.Incident) interface{} { return i.IsConfirmed }},
+	{"confirmation_time", func(i *models.Incident) interface{} { return formatExportTime(i.ConfirmationTime) }},
+	{"quarter", func(i *models.Incident) interface{} { return i.Quarter }},
+	{"departament", func(i *models.Incident) interface{} { return i.Departament }},
+	{"client_affect", func(i *models.Incident) interface{} { return i.ClientAffect }},
+	{"is_manageable", func(i *models.Incident) interface{} { return i.IsManageable }},
+	{"sale_channels", func(i *models.Incident) interface{} { return utils.JoinWithCommas(i.SaleChannels) }},
+	{"trouble_services", func(i *models.Incident) interface{} { return utils.JoinWithCommas(i.TroubleServices) }},
+	{"fin_losses", func(i *models.Incident) interface{} { return i.FinLosses }},
+	{"failure_type", func(i *models.Incident) interface{} { return i.FailureType }},
+	{"is_deploy", func(i *models.Incident) interface{} { return i.IsDeploy }},
+	{"deploy_link", func(i *models.Incident) interface{} { return i.DeployLink }},
+	{"labels", func(i *models.Incident) interface{} { return utils.JoinWithCommas(i.Labels) }},
+	{"is_downtime", func(i *models.Incident) interface{} { return i.IsDowntime }},
+	{"postmortem_link", func(i *models.Incident) interface{} { return i.PostmortemLink }},
+	{"creator", func(i *models.Incident) interface{} { return i.Creator }},
+	{"rule_id", func(i *models.Incident) interface{} { return utils.DerefStr(i.RuleID) }},
+	{"matching_count", func(i *models.Incident) interface{} { return i.MatchingCount }},
+	{"last_matching_time", func(i *models.Incident) interface{} { return formatExportTime(i.LastMatchingTime) }},
+	{"is_flapping", func(i *models.Incident) interface{} { return i.IsFlapping }},
+	{"flap_count", func(i *models.Incident) interface{} { return i.FlapCount }},
+	{"merged_into_id", func(i *models.Incident) interface{} { return utils.DerefStr(i.MergedIntoID) }},
+	{"created_at", func(i *models.Incident) interface{} { return formatExportTime(i.CreatedAt) }},
+	{"updated_at", func(i *models.Incident) interface{} { return formatExportTime(i.UpdatedAt) }},
+	{"closed_at", func(i *models.Incident) interface{} {
+		if i.ClosedAt == nil {
+			return ""
+		}
+		return formatExportTime(*i.ClosedAt)
+	}},
+}
+
+// exportIncidents returns a handler for exporting the incidents as a CSV or XLSX table.
+// It accepts the same filters, time range and sorting as the incidents list; the columns
+// query parameter selects the columns and their order. Rows are streamed as they are read from the database.
+func exportIncidents(repo *repositories.IncidentsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", export.CSVFormat)
+		if format != export.CSVFormat && format != export.XLSXFormat {
+			c.Error(v1.NewValidationError(fmt.Errorf("unknown export format: %s", format)))
+			return
+		}
+
+		columns, err := selectExportColumns(c.Query("columns"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"columns": c.Query("columns"),
+				"error":   err.Error(),
+			}).Error("Failed to select export columns")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		startTime, endTime, err := parseTimeRange(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to parse time range")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		// Build filters from query parameters.
+		filterBy, err := buildFilterForIncidents(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"filterBy": filterBy,
+				"error":    err.Error(),
+			}).Error("Failed to build filter for incidents")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		sortBy := c.DefaultQuery("sortBy", "created_at")
+		sortOrder := c.DefaultQuery("sortOrder", "desc")
+
+		// From here on the response is streamed, so errors can only be logged.
+		c.Header("Content-Type", export.ContentType(format))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="incidents_%s.%s"`, time.Now().UTC().Format("20060102T150405Z"), format))
+		c.Status(http.StatusOK)
+
+		writer, err := export.NewTableWriter(format, c.Writer)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"format": format,
+				"error":  err.Error(),
+			}).Error("Failed to create export table writer")
+			return
+		}
+
+		header := make([]interface{}, len(columns))
+		for i, column := range columns {
+			header[i] = column.Name
+		}
+		if err := writer.WriteRow(header); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to write export header")
+			return
+		}
+
+		rowsCount := 0
+		err = repo.StreamIncidents(c, filterBy, sortBy, sortOrder, startTime.UTC(), endTime.UTC(), func(incident *models.Incident) error {
+			row := make([]interface{}, len(columns))
+			for i, column := range columns {
+				row[i] = column.Value(incident)
+			}
+			if err := writer.WriteRow(row); err != nil {
+				return fmt.Errorf("error writing the row: %w", err)
+			}
+
+			// Periodically push the written rows to the client.
+			rowsCount++
+			if rowsCount%exportFlushInterval == 0 {
+				if err := writer.Flush(); err != nil {
+					return fmt.Errorf("error flushing the rows: %w", err)
+				}
+				c.Writer.Flush()
+			}
+			return nil
+		})
+		if err != nil {
+			log.WithFields(log.Fields{
+				"format":    format,
+				"rowsCount": rowsCount,
+				"error":     err.Error(),
+			}).Error("Failed to export incidents")
+			return
+		}
+
+		if err := writer.Close(); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to complete export table")
+			return
+		}
+
+		log.WithFields(log.Fields{
+			"format":    format,
+			"rowsCount": rowsCount,
+		}).Info("Incidents exported")
+	}
+}
+
+// selectExportColumns returns the export columns named in the comma-separated list, or all columns if the list is empty.
+func selectExportColumns(columnsStr string) ([]exportColumn, error) {
+	if strings.TrimSpace(columnsStr) == "" {
+		return exportColumns, nil
+	}
+
+	columns := []exportColumn{}
+	for _, name := range strings.Split(columnsStr, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, column := range exportColumns {
+			if column.Name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown export column: %s", name)
+		}
+	}
+	return columns, nil
+}
+
+// formatExportTime formats the time in RFC3339, leaving unset times empty.
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
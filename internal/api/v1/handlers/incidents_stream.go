@@ -0,0 +1,82 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/stream"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// streamHeartbeatInterval is the interval of the comments keeping idle streams open through proxies.
+	streamHeartbeatInterval = 15 * time.Second
+	// streamRetryMillis is the reconnection delay suggested to the clients.
+	streamRetryMillis = 5000
+)
+
+// RegisterIncidentsStreamRoutes sets up the routing of the incidents stream endpoint.
+func RegisterIncidentsStreamRoutes(router *gin.Engine, hub *stream.Hub, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/incidents")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/stream", streamIncidents(hub))
+}
+
+// streamIncidents returns a handler streaming the incident changes as Server-Sent Events.
+// A reconnecting client sending the Last-Event-ID header first receives the events it missed, or a reset event
+// if they aren't kept anymore; comments are sent as heartbeats while nothing changes.
+func streamIncidents(hub *stream.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, missed, unsubscribe := hub.Subscribe(c.GetHeader("Last-Event-ID"))
+		defer unsubscribe()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+
+		fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetryMillis)
+		for _, event := range missed {
+			writeStreamEvent(c.Writer, event)
+		}
+		c.Writer.Flush()
+
+		log.WithFields(log.Fields{
+			"clientIP":     c.ClientIP(),
+			"missedEvents": len(missed),
+		}).Debug("Incidents stream client connected")
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, ok := <-events:
+				// The channel is closed on shutdown or when the client lags behind; the client reconnects and resumes
+				if !ok {
+					return
+				}
+				writeStreamEvent(c.Writer, event)
+			case <-heartbeat.C:
+				io.WriteString(c.Writer, ": heartbeat\n\n")
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeStreamEvent writes the event in the Server-Sent Events format; the data is single-line JSON.
+func writeStreamEvent(w io.Writer, event *stream.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
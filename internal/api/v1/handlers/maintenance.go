@@ -0,0 +1,182 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterMaintenanceWindowsRoutes sets up the routing for maintenance window API endpoints.
+func RegisterMaintenanceWindowsRoutes(router *gin.Engine, repo *repositories.MaintenanceWindowsRepository,
+	suppressionsRepo *repositories.MaintenanceSuppressionsRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/maintenance-windows")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:id", getMaintenanceWindow(repo))
+	routerGroup.GET("/", getMaintenanceWindows(repo))
+	routerGroup.POST("/", createMaintenanceWindow(repo))
+	routerGroup.PUT("/:id", updateMaintenanceWindow(repo))
+	routerGroup.DELETE("/:id", deleteMaintenanceWindow(repo))
+	routerGroup.GET("/:id/suppressions", getMaintenanceSuppressions(suppressionsRepo))
+}
+
+// getMaintenanceWindow returns a handler for retrieving a single maintenance window by its ID.
+func getMaintenanceWindow(repo *repositories.MaintenanceWindowsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		window, err := repo.GetMaintenanceWindow(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve maintenance window")
+			c.Error(repositoryError(err, "maintenance window not found"))
+			return
+		}
+		c.JSON(http.StatusOK, window)
+	}
+}
+
+// getMaintenanceWindows returns a handler for retrieving all maintenance windows.
+// The one-time windows that have already ended are left out if the "not_ended" query parameter is true.
+func getMaintenanceWindows(repo *repositories.MaintenanceWindowsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var notEndedAt *time.Time
+		if c.Query("not_ended") == "true" {
+			currentTime := time.Now().UTC()
+			notEndedAt = &currentTime
+		}
+
+		windows, err := repo.GetMaintenanceWindows(c, notEndedAt)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to retrieve maintenance windows")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"maintenance_windows": windows,
+		})
+	}
+}
+
+// createMaintenanceWindow returns a handler for creating a new maintenance window based on provided data.
+func createMaintenanceWindow(repo *repositories.MaintenanceWindowsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateMaintenanceWindowDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		window, err := v1.MapCreateMaintenanceWindowDTOToModel(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to maintenance window model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateMaintenanceWindow(c, window); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to create maintenance window")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, window)
+	}
+}
+
+// updateMaintenanceWindow returns a handler for updating an existing maintenance window.
+func updateMaintenanceWindow(repo *repositories.MaintenanceWindowsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.UpdateMaintenanceWindowDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		window, err := repo.GetMaintenanceWindow(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve maintenance window")
+			c.Error(repositoryError(err, "maintenance window not found"))
+			return
+		}
+
+		if err := v1.MapUpdateMaintenanceWindowDTOToModel(dto, window); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to maintenance window model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.UpdateMaintenanceWindow(c, window); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to update maintenance window")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, window)
+	}
+}
+
+// deleteMaintenanceWindow returns a handler for deleting an maintenance window by its ID.
+func deleteMaintenanceWindow(repo *repositories.MaintenanceWindowsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteMaintenanceWindow(c, c.Param("id")); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to delete maintenance window")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
+
+// getMaintenanceSuppressions returns a handler for retrieving the matches suppressed by a maintenance window.
+// The suppressions are kept after the window is deleted, so they can be audited afterwards.
+func getMaintenanceSuppressions(repo *repositories.MaintenanceSuppressionsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		suppressions, err := repo.GetMaintenanceSuppressions(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"windowID": c.Param("id"),
+				"error":    err.Error(),
+			}).Error("Failed to retrieve maintenance suppressions")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"suppressions": suppressions,
+		})
+	}
+}
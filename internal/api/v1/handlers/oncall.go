@@ -0,0 +1,378 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/oncall"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterOnCallRoutes sets up the routing for on-call users, schedules and overrides API endpoints.
+func RegisterOnCallRoutes(router *gin.Engine, usersRepo *repositories.OnCallUsersRepository, schedulesRepo *repositories.OnCallSchedulesRepository,
+	overridesRepo *repositories.OnCallOverridesRepository, resolver *oncall.Resolver, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/oncall")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/users/:id", getOnCallUser(usersRepo))
+	routerGroup.GET("/users", getOnCallUsers(usersRepo))
+	routerGroup.POST("/users", createOnCallUser(usersRepo))
+	routerGroup.PUT("/users/:id", updateOnCallUser(usersRepo))
+	routerGroup.DELETE("/users/:id", deleteOnCallUser(usersRepo))
+
+	routerGroup.GET("/schedules/:id", getOnCallSchedule(schedulesRepo))
+	routerGroup.GET("/schedules", getOnCallSchedules(schedulesRepo))
+	routerGroup.POST("/schedules", createOnCallSchedule(schedulesRepo))
+	routerGroup.PUT("/schedules/:id", updateOnCallSchedule(schedulesRepo))
+	routerGroup.DELETE("/schedules/:id", deleteOnCallSchedule(schedulesRepo))
+
+	routerGroup.GET("/schedules/:id/overrides", getOnCallOverrides(overridesRepo))
+	routerGroup.POST("/schedules/:id/overrides", createOnCallOverride(overridesRepo))
+	routerGroup.DELETE("/overrides/:id", deleteOnCallOverride(overridesRepo))
+
+	routerGroup.GET("/:schedule/now", getCurrentOnCallShift(resolver))
+}
+
+// getOnCallUser returns a handler for retrieving a single on-call user by its ID.
+func getOnCallUser(repo *repositories.OnCallUsersRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := repo.GetOnCallUser(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve on-call user")
+			c.Error(repositoryError(err, "on-call user not found"))
+			return
+		}
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// getOnCallUsers returns a handler for retrieving all on-call users.
+func getOnCallUsers(repo *repositories.OnCallUsersRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := repo.GetOnCallUsers(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to retrieve on-call users")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"users": users,
+		})
+	}
+}
+
+// createOnCallUser returns a handler for creating a new on-call user based on provided data.
+func createOnCallUser(repo *repositories.OnCallUsersRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateOnCallUserDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		user, err := v1.MapCreateOnCallUserDTOToModel(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to on-call user model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateOnCallUser(c, user); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to create on-call user")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// updateOnCallUser returns a handler for updating an existing on-call user.
+func updateOnCallUser(repo *repositories.OnCallUsersRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.UpdateOnCallUserDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		user, err := repo.GetOnCallUser(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve on-call user")
+			c.Error(repositoryError(err, "on-call user not found"))
+			return
+		}
+
+		if err := v1.MapUpdateOnCallUserDTOToModel(dto, user); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to on-call user model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.UpdateOnCallUser(c, user); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to update on-call user")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	}
+}
+
+// deleteOnCallUser returns a handler for deleting an on-call user by its ID.
+func deleteOnCallUser(repo *repositories.OnCallUsersRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteOnCallUser(c, c.Param("id")); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to delete on-call user")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
+
+// getOnCallSchedule returns a handler for retrieving a single on-call schedule by its ID.
+func getOnCallSchedule(repo *repositories.OnCallSchedulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedule, err := repo.GetOnCallSchedule(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve on-call schedule")
+			c.Error(repositoryError(err, "on-call schedule not found"))
+			return
+		}
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// getOnCallSchedules returns a handler for retrieving all on-call schedules.
+func getOnCallSchedules(repo *repositories.OnCallSchedulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schedules, err := repo.GetOnCallSchedules(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to retrieve on-call schedules")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"schedules": schedules,
+		})
+	}
+}
+
+// createOnCallSchedule returns a handler for creating a new on-call schedule based on provided data.
+func createOnCallSchedule(repo *repositories.OnCallSchedulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateOnCallScheduleDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		schedule, err := v1.MapCreateOnCallScheduleDTOToModel(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to on-call schedule model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateOnCallSchedule(c, schedule); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to create on-call schedule")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// updateOnCallSchedule returns a handler for updating an existing on-call schedule.
+func updateOnCallSchedule(repo *repositories.OnCallSchedulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.UpdateOnCallScheduleDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		schedule, err := repo.GetOnCallSchedule(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve on-call schedule")
+			c.Error(repositoryError(err, "on-call schedule not found"))
+			return
+		}
+
+		if err := v1.MapUpdateOnCallScheduleDTOToModel(dto, schedule); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to on-call schedule model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.UpdateOnCallSchedule(c, schedule); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to update on-call schedule")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// deleteOnCallSchedule returns a handler for deleting an on-call schedule by its ID together with its overrides.
+func deleteOnCallSchedule(repo *repositories.OnCallSchedulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteOnCallSchedule(c, c.Param("id")); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to delete on-call schedule")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
+
+// getOnCallOverrides returns a handler for retrieving the current and upcoming overrides of an on-call schedule.
+func getOnCallOverrides(repo *repositories.OnCallOverridesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		overrides, err := repo.GetOnCallOverrides(c, c.Param("id"), time.Now().UTC())
+		if err != nil {
+			log.WithFields(log.Fields{
+				"schedule_id": c.Param("id"),
+				"error":       err.Error(),
+			}).Error("Failed to retrieve on-call overrides")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"overrides": overrides,
+		})
+	}
+}
+
+// createOnCallOverride returns a handler for overriding the on-call user of a schedule for a period of time.
+func createOnCallOverride(repo *repositories.OnCallOverridesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateOnCallOverrideDTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		override, err := v1.MapCreateOnCallOverrideDTOToModel(dto, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to on-call override model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateOnCallOverride(c, override); err != nil {
+			log.WithFields(log.Fields{
+				"schedule_id": c.Param("id"),
+				"error":       err.Error(),
+			}).Error("Failed to create on-call override")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, override)
+	}
+}
+
+// deleteOnCallOverride returns a handler for deleting an on-call override by its ID.
+func deleteOnCallOverride(repo *repositories.OnCallOverridesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteOnCallOverride(c, c.Param("id")); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to delete on-call override")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
+
+// getCurrentOnCallShift returns a handler for retrieving who is on call for a schedule, given by its name, right now.
+func getCurrentOnCallShift(resolver *oncall.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		shift, err := resolver.ShiftAt(c, c.Param("schedule"), time.Now().UTC())
+		if err != nil {
+			log.WithFields(log.Fields{
+				"schedule": c.Param("schedule"),
+				"error":    err.Error(),
+			}).Error("Failed to resolve current on-call shift")
+			c.Error(repositoryError(err, "on-call shift not found"))
+			return
+		}
+		c.JSON(http.StatusOK, shift)
+	}
+}
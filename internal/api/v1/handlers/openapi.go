@@ -0,0 +1,739 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/openapi"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/export"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Tags grouping the operations of the OpenAPI document.
+const (
+	authTag               = "auth"
+	incidentsTag          = "incidents"
+	reportsTag            = "reports"
+	rulesTag              = "rules"
+	slosTag               = "slo"
+	escalationPoliciesTag = "escalation-policies"
+	onCallTag             = "oncall"
+	maintenanceWindowsTag = "maintenance-windows"
+	openAPITag            = "openapi"
+)
+
+// errorDescriptions are the descriptions of the error responses of the operations by the status code.
+var errorDescriptions = map[int]string{
+	http.StatusBadRequest:          "The request is invalid, e.g. a field or a query parameter has a wrong value",
+	http.StatusUnauthorized:        "The JWT token is missing, malformed or expired",
+	http.StatusForbidden:           "The user isn't allowed to use the API",
+	http.StatusNotFound:            "The requested record doesn't exist",
+	http.StatusConflict:            "The record has been changed by another client, see If-Match, or duplicates an existing one",
+	http.StatusInternalServerError: "The request has failed, e.g. because of the database",
+}
+
+// RegisterOpenAPIRoutes registers the route of the OpenAPI document of the API. The document is public,
+// so the clients can be generated without a token.
+func RegisterOpenAPIRoutes(router *gin.Engine, document *openapi.Document) {
+	routerGroup := router.Group("/api/v1")
+
+	routerGroup.GET("/openapi.json", getOpenAPIDocument(document))
+}
+
+// getOpenAPIDocument returns a handler serving the OpenAPI document, marshaled once as it doesn't change.
+func getOpenAPIDocument(document *openapi.Document) gin.HandlerFunc {
+	data, err := json.Marshal(document)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Fatal("Failed to marshal the OpenAPI document")
+	}
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}
+}
+
+// OpenAPIDocument builds the OpenAPI document of the v1 API. Every route registered by the Register functions
+// must be described here under the name of its handler; the tests of the server verify it with openapi.CheckRoutes.
+func OpenAPIDocument() *openapi.Document {
+	d := openapi.NewDocument("Alerts2Incidents API",
+		"REST API of the incidents created from the alerts of the monitoring systems and of the rules creating them.", "1")
+	// The error responses, see withErrors, refer to the ErrorResponse component; its codes are listed as the allowed values.
+	d.SchemaOf(v1.ErrorResponse{})
+	codeSchema := d.Components.Schemas["ErrorResponse"].Properties["code"]
+	for _, code := range v1.ErrorCodes {
+		codeSchema.Enum = append(codeSchema.Enum, string(code))
+	}
+	d.Components.Schemas["Message"] = openapi.ObjectOf(map[string]*openapi.Schema{
+		"message": {Type: "string", Enum: []string{"ok"}},
+	})
+
+	documentAuthRoutes(d)
+	documentIncidentsRoutes(d)
+	documentIncidentsStreamRoutes(d)
+	documentIncidentEventsRoutes(d)
+	documentIncidentAlertsRoutes(d)
+	documentIncidentLinksRoutes(d)
+	documentReportsRoutes(d)
+	documentRulesRoutes(d)
+	documentSLOsRoutes(d)
+	documentEscalationPoliciesRoutes(d)
+	documentOnCallRoutes(d)
+	documentMaintenanceWindowsRoutes(d)
+
+	d.AddOperation(http.MethodGet, "/api/v1/openapi.json", &openapi.Operation{
+		OperationID: "getOpenAPIDocument",
+		Summary:     "Get this OpenAPI document",
+		Tags:        []string{openAPITag},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("OpenAPI 3 document of the API", &openapi.Schema{Type: "object"}),
+		},
+	})
+
+	return d
+}
+
+// documentAuthRoutes describes the routes of RegisterAuthRoutes.
+func documentAuthRoutes(d *openapi.Document) {
+	d.AddOperation(http.MethodPost, "/api/v1/auth/ldap", withErrors(&openapi.Operation{
+		OperationID: "authenticateLDAPUser",
+		Summary:     "Log in with an LDAP account",
+		Description: "Issues a JWT token for the user of an allowed LDAP group; the token is sent as Authorization: Bearer <token>.",
+		Tags:        []string{authTag},
+		RequestBody: jsonRequestBody(openapi.ObjectOf(map[string]*openapi.Schema{
+			"login":    {Type: "string"},
+			"password": {Type: "string", Format: "password"},
+		})),
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The token of the user", openapi.ObjectOf(map[string]*openapi.Schema{
+				"user_name":       {Type: "string", Description: "Name of the LDAP user"},
+				"token":           {Type: "string", Description: "JWT token"},
+				"token_life_time": {Type: "integer", Format: "int64", Description: "Life time of the token in nanoseconds"},
+			})),
+		},
+	}, http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusInternalServerError))
+}
+
+// documentIncidentsRoutes describes the routes of RegisterIncidentsRoutes.
+func documentIncidentsRoutes(d *openapi.Document) {
+	incident := d.SchemaOf(models.Incident{})
+	listParameters := slices.Concat(paginationParameters(), timeRangeParameters(), sortParameters(d, models.Incident{}),
+		filterParameters(d, models.Incident{}, incidentQueryFilters))
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/export", &openapi.Operation{
+		OperationID: "exportIncidents",
+		Summary:     "Export the incidents as a table",
+		Description: "Streams the incidents matching the filters as a CSV or XLSX table; array fields are joined with commas.",
+		Tags:        []string{incidentsTag},
+		Parameters: slices.Concat([]*openapi.Parameter{
+			openapi.QueryParameter("format", "Format of the table", &openapi.Schema{
+				Type: "string", Enum: []string{export.CSVFormat, export.XLSXFormat}, Default: export.CSVFormat,
+			}),
+			openapi.QueryParameter("columns", "Comma-separated columns of the table in their order; all columns by default",
+				&openapi.Schema{Type: "string"}),
+		}, timeRangeParameters(), sortParameters(d, models.Incident{}), filterParameters(d, models.Incident{}, incidentQueryFilters)),
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The table of the incidents",
+				Headers:     attachmentHeaders(),
+				Content: map[string]*openapi.MediaType{
+					export.ContentType(export.CSVFormat):  {Schema: &openapi.Schema{Type: "string"}},
+					export.ContentType(export.XLSXFormat): {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+		},
+	}, http.StatusBadRequest)
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/:id", &openapi.Operation{
+		OperationID: "getIncident",
+		Summary:     "Get an incident",
+		Tags:        []string{incidentsTag},
+		Responses:   map[string]*openapi.Response{"200": versionedResponse("The incident", incident)},
+	}, http.StatusNotFound)
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/", &openapi.Operation{
+		OperationID: "getIncidents",
+		Summary:     "List the incidents",
+		Tags:        []string{incidentsTag},
+		Parameters:  listParameters,
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("A page of the incidents", pageSchema("incidents", d.ArrayOf(models.Incident{}))),
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/incidents/", &openapi.Operation{
+		OperationID: "createIncident",
+		Summary:     "Create a manual incident",
+		Tags:        []string{incidentsTag},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.CreateIncidentDTO{})),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The created incident", incident)},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/incidents/bulk", &openapi.Operation{
+		OperationID: "bulkIncidents",
+		Summary:     "Apply an action to several incidents",
+		Description: "The incidents are selected either by the ids of the body or by the filters of the query, not both.",
+		Tags:        []string{incidentsTag},
+		Parameters:  slices.Concat(timeRangeParameters(), filterParameters(d, models.Incident{}, incidentQueryFilters)),
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.BulkIncidentsDTO{})),
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The results of the action", bulkResultSchema(d, models.IncidentBulkActions)),
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPut, "/api/v1/incidents/:id", &openapi.Operation{
+		OperationID: "updateIncident",
+		Summary:     "Update the fields of an incident",
+		Description: "Only the fields present in the body are updated.",
+		Tags:        []string{incidentsTag},
+		Parameters:  []*openapi.Parameter{ifMatchParameter()},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.UpdateIncidentDTO{})),
+		Responses:   map[string]*openapi.Response{"200": versionedResponse("The updated incident", incident)},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodDelete, "/api/v1/incidents/:id", &openapi.Operation{
+		OperationID: "deleteIncident",
+		Summary:     "Delete an incident",
+		Tags:        []string{incidentsTag},
+		Responses:   okResponses(),
+	}, http.StatusInternalServerError)
+}
+
+// documentIncidentsStreamRoutes describes the routes of RegisterIncidentsStreamRoutes.
+func documentIncidentsStreamRoutes(d *openapi.Document) {
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/stream", &openapi.Operation{
+		OperationID: "streamIncidents",
+		Summary:     "Stream the changes of the incidents",
+		Description: "Server-Sent Events named after the change, e.g. insert, update or delete, with the incident or its ID as the data; " +
+			"a reset event tells that the missed events can't be replayed.",
+		Tags: []string{incidentsTag},
+		Parameters: []*openapi.Parameter{
+			openapi.HeaderParameter("Last-Event-ID", "ID of the last event received, to resume the stream after a reconnection"),
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The stream of the events",
+				Content:     map[string]*openapi.MediaType{"text/event-stream": {Schema: &openapi.Schema{Type: "string"}}},
+			},
+		},
+	})
+}
+
+// documentIncidentEventsRoutes describes the routes of RegisterIncidentEventsRoutes.
+func documentIncidentEventsRoutes(d *openapi.Document) {
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/:id/events", &openapi.Operation{
+		OperationID: "getIncidentEvents",
+		Summary:     "Get the history of an incident",
+		Tags:        []string{incidentsTag},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The events of the incident", listSchema("events", d.ArrayOf(models.IncidentEvent{}))),
+		},
+	}, http.StatusInternalServerError)
+}
+
+// documentIncidentAlertsRoutes describes the routes of RegisterIncidentAlertsRoutes.
+func documentIncidentAlertsRoutes(d *openapi.Document) {
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/:id/alerts", &openapi.Operation{
+		OperationID: "getIncidentAlerts",
+		Summary:     "Get the alerts contributing to an incident",
+		Tags:        []string{incidentsTag},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The alerts of the incident", listSchema("alerts", d.ArrayOf(models.IncidentAlert{}))),
+		},
+	}, http.StatusNotFound, http.StatusInternalServerError)
+}
+
+// documentIncidentLinksRoutes describes the routes of RegisterIncidentLinksRoutes.
+func documentIncidentLinksRoutes(d *openapi.Document) {
+	addSecuredOperation(d, http.MethodGet, "/api/v1/incidents/:id/links", &openapi.Operation{
+		OperationID: "getIncidentLinks",
+		Summary:     "Get the links of an incident",
+		Tags:        []string{incidentsTag},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The links of the incident", listSchema("links", d.ArrayOf(models.IncidentLink{}))),
+		},
+	}, http.StatusNotFound, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/incidents/:id/links", &openapi.Operation{
+		OperationID: "createIncidentLink",
+		Summary:     "Link an incident to another one",
+		Tags:        []string{incidentsTag},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.CreateIncidentLinkDTO{})),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The created link", d.SchemaOf(models.IncidentLink{}))},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodDelete, "/api/v1/incidents/:id/links/:linkId", &openapi.Operation{
+		OperationID: "deleteIncidentLink",
+		Summary:     "Delete a link of an incident",
+		Tags:        []string{incidentsTag},
+		Responses:   okResponses(),
+	}, http.StatusNotFound, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/incidents/:id/merge", &openapi.Operation{
+		OperationID: "mergeIncidents",
+		Summary:     "Merge duplicate incidents into an incident",
+		Tags:        []string{incidentsTag},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.MergeIncidentsDTO{})),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The primary incident", d.SchemaOf(models.Incident{}))},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/incidents/:id/split", &openapi.Operation{
+		OperationID: "splitIncident",
+		Summary:     "Split the alerts of an incident into a new incident",
+		Tags:        []string{incidentsTag},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.SplitIncidentDTO{})),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The new incident", d.SchemaOf(models.Incident{}))},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)
+}
+
+// documentReportsRoutes describes the routes of RegisterReportsRoutes.
+func documentReportsRoutes(d *openapi.Document) {
+	dimension := openapi.PathParameter("dimension", "Dimension the incidents are grouped by")
+	dimension.Schema.Enum = repositories.ReportDimensions()
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/reports/:dimension", &openapi.Operation{
+		OperationID: "getIncidentsReport",
+		Summary:     "Aggregate the incidents by a dimension",
+		Tags:        []string{reportsTag},
+		Parameters: slices.Concat([]*openapi.Parameter{dimension}, timeRangeParameters(),
+			filterParameters(d, models.Incident{}, incidentQueryFilters)),
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The statistics of the groups of the incidents", openapi.ObjectOf(map[string]*openapi.Schema{
+				"dimension": {Type: "string"},
+				"rows":      d.ArrayOf(models.IncidentsReportRow{}),
+			})),
+		},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)
+}
+
+// documentRulesRoutes describes the routes of RegisterRulesRoutes.
+func documentRulesRoutes(d *openapi.Document) {
+	rule := d.SchemaOf(models.Rule{})
+	rulesDocument := d.SchemaOf(models.RulesDocument{})
+	documentFormat := openapi.QueryParameter("format", "Format of the rules document", &openapi.Schema{
+		Type: "string", Enum: []string{yamlRulesFormat, jsonRulesFormat},
+	})
+	listParameters := slices.Concat(paginationParameters(), timeRangeParameters(), sortParameters(d, models.Rule{}),
+		filterParameters(d, models.Rule{}, ruleQueryFilters), []*openapi.Parameter{muteExpiresWithinParameter()})
+
+	exportFormat := *documentFormat
+	exportFormat.Schema = &openapi.Schema{Type: "string", Enum: documentFormat.Schema.Enum, Default: yamlRulesFormat}
+	addSecuredOperation(d, http.MethodGet, "/api/v1/rules/export", &openapi.Operation{
+		OperationID: "exportRules",
+		Summary:     "Export all rules as a rules document",
+		Tags:        []string{rulesTag},
+		Parameters:  []*openapi.Parameter{&exportFormat},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The rules document ordered by ID",
+				Headers:     attachmentHeaders(),
+				Content: map[string]*openapi.MediaType{
+					"application/yaml": {Schema: rulesDocument},
+					"application/json": {Schema: rulesDocument},
+				},
+			},
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/rules/import", &openapi.Operation{
+		OperationID: "importRules",
+		Summary:     "Import the rules of a rules document",
+		Description: "Creates and updates the rules of the document by ID in a single transaction; " +
+			"the format is taken from the format parameter or the Content-Type, YAML by default. " +
+			"The durations are strings in YAML, e.g. 5m, and nanoseconds in JSON.",
+		Tags: []string{rulesTag},
+		Parameters: []*openapi.Parameter{
+			documentFormat,
+			openapi.QueryParameter("dry_run", "Only compute the changes without saving them", &openapi.Schema{Type: "boolean", Default: false}),
+			openapi.QueryParameter("prune", "Delete the rules absent from the document", &openapi.Schema{Type: "boolean", Default: false}),
+		},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{
+				"application/yaml": {Schema: rulesDocument},
+				"application/json": {Schema: rulesDocument},
+			},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The changes made or planned", d.SchemaOf(models.RulesImportResult{})),
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/rules/:id", &openapi.Operation{
+		OperationID: "getRule",
+		Summary:     "Get a rule",
+		Tags:        []string{rulesTag},
+		Responses:   map[string]*openapi.Response{"200": versionedResponse("The rule", rule)},
+	}, http.StatusNotFound)
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/rules/", &openapi.Operation{
+		OperationID: "getRules",
+		Summary:     "List the rules",
+		Tags:        []string{rulesTag},
+		Parameters:  listParameters,
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("A page of the rules", pageSchema("rules", d.ArrayOf(models.Rule{}))),
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/rules/", &openapi.Operation{
+		OperationID: "createRule",
+		Summary:     "Create a rule",
+		Tags:        []string{rulesTag},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.CreateRuleDTO{})),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The created rule", rule)},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/rules/bulk", &openapi.Operation{
+		OperationID: "bulkRules",
+		Summary:     "Apply an action to several rules",
+		Description: "The rules are selected either by the ids of the body or by the filters of the query, not both.",
+		Tags:        []string{rulesTag},
+		Parameters: slices.Concat(timeRangeParameters(), filterParameters(d, models.Rule{}, ruleQueryFilters),
+			[]*openapi.Parameter{muteExpiresWithinParameter()}),
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.BulkRulesDTO{})),
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The results of the action", bulkResultSchema(d, models.RuleBulkActions)),
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPut, "/api/v1/rules/:id", &openapi.Operation{
+		OperationID: "updateRule",
+		Summary:     "Update the fields of a rule",
+		Description: "Only the fields present in the body are updated.",
+		Tags:        []string{rulesTag},
+		Parameters:  []*openapi.Parameter{ifMatchParameter()},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.UpdateRuleDTO{})),
+		Responses:   map[string]*openapi.Response{"200": versionedResponse("The updated rule", rule)},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodDelete, "/api/v1/rules/:id", &openapi.Operation{
+		OperationID: "deleteRule",
+		Summary:     "Delete a rule",
+		Tags:        []string{rulesTag},
+		Responses:   okResponses(),
+	}, http.StatusInternalServerError)
+}
+
+// documentSLOsRoutes describes the routes of RegisterSLOsRoutes.
+func documentSLOsRoutes(d *openapi.Document) {
+	at := openapi.QueryParameter("at", "Time selecting the period of the objectives, now by default",
+		&openapi.Schema{Type: "string", Format: "date-time"})
+
+	// The objectives are read with the consumption of their error budgets, so their get and list routes differ from the other resources
+	addSecuredOperation(d, http.MethodGet, "/api/v1/slo/:id", &openapi.Operation{
+		OperationID: "getSLO",
+		Summary:     "Get the error budget of an objective",
+		Tags:        []string{slosTag},
+		Parameters:  []*openapi.Parameter{at},
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The status of the objective", d.SchemaOf(models.SLOStatus{}))},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/slo/", &openapi.Operation{
+		OperationID: "getSLOs",
+		Summary:     "List the error budgets of all objectives",
+		Tags:        []string{slosTag},
+		Parameters:  []*openapi.Parameter{at},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The statuses of the objectives", listSchema("slos", d.ArrayOf(models.SLOStatus{}))),
+		},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	documentCRUDRoutes(d, &crudResource{
+		Path: "/api/v1/slo/", Tag: slosTag, Name: "SLO", Title: "service-level objective",
+		Item: models.SLO{}, CreateDTO: dtos.CreateSLODTO{}, UpdateDTO: dtos.UpdateSLODTO{},
+	})
+}
+
+// documentEscalationPoliciesRoutes describes the routes of RegisterEscalationPoliciesRoutes.
+func documentEscalationPoliciesRoutes(d *openapi.Document) {
+	documentCRUDRoutes(d, &crudResource{
+		Path: "/api/v1/escalation-policies/", Tag: escalationPoliciesTag,
+		Name: "EscalationPolicy", Names: "EscalationPolicies", Title: "escalation policy", Titles: "escalation policies",
+		Item: models.EscalationPolicy{}, ListKey: "escalation_policies",
+		CreateDTO: dtos.CreateEscalationPolicyDTO{}, UpdateDTO: dtos.UpdateEscalationPolicyDTO{},
+	})
+}
+
+// documentOnCallRoutes describes the routes of RegisterOnCallRoutes.
+func documentOnCallRoutes(d *openapi.Document) {
+	documentCRUDRoutes(d, &crudResource{
+		Path: "/api/v1/oncall/users", Tag: onCallTag,
+		Name: "OnCallUser", Names: "OnCallUsers", Title: "on-call user", Titles: "on-call users",
+		Item: models.OnCallUser{}, ListKey: "users",
+		CreateDTO: dtos.CreateOnCallUserDTO{}, UpdateDTO: dtos.UpdateOnCallUserDTO{},
+	})
+	documentCRUDRoutes(d, &crudResource{
+		Path: "/api/v1/oncall/schedules", Tag: onCallTag,
+		Name: "OnCallSchedule", Names: "OnCallSchedules", Title: "on-call schedule", Titles: "on-call schedules",
+		Item: models.OnCallSchedule{}, ListKey: "schedules",
+		CreateDTO: dtos.CreateOnCallScheduleDTO{}, UpdateDTO: dtos.UpdateOnCallScheduleDTO{},
+	})
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/oncall/schedules/:id/overrides", &openapi.Operation{
+		OperationID: "getOnCallOverrides",
+		Summary:     "List the current and upcoming overrides of an on-call schedule",
+		Tags:        []string{onCallTag},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The overrides of the schedule", listSchema("overrides", d.ArrayOf(models.OnCallOverride{}))),
+		},
+	}, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPost, "/api/v1/oncall/schedules/:id/overrides", &openapi.Operation{
+		OperationID: "createOnCallOverride",
+		Summary:     "Override the on-call user of a schedule for a period of time",
+		Tags:        []string{onCallTag},
+		RequestBody: jsonRequestBody(d.SchemaOf(dtos.CreateOnCallOverrideDTO{})),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The created override", d.SchemaOf(models.OnCallOverride{}))},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodDelete, "/api/v1/oncall/overrides/:id", &openapi.Operation{
+		OperationID: "deleteOnCallOverride",
+		Summary:     "Delete an on-call override",
+		Tags:        []string{onCallTag},
+		Responses:   okResponses(),
+	}, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/oncall/:schedule/now", &openapi.Operation{
+		OperationID: "getCurrentOnCallShift",
+		Summary:     "Get who is on call for a schedule right now",
+		Tags:        []string{onCallTag},
+		Parameters:  []*openapi.Parameter{openapi.PathParameter("schedule", "Name of the schedule")},
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The current shift", d.SchemaOf(models.OnCallShift{}))},
+	}, http.StatusNotFound)
+}
+
+// documentMaintenanceWindowsRoutes describes the routes of RegisterMaintenanceWindowsRoutes.
+func documentMaintenanceWindowsRoutes(d *openapi.Document) {
+	documentCRUDRoutes(d, &crudResource{
+		Path: "/api/v1/maintenance-windows/", Tag: maintenanceWindowsTag,
+		Name: "MaintenanceWindow", Names: "MaintenanceWindows", Title: "maintenance window", Titles: "maintenance windows",
+		Item: models.MaintenanceWindow{}, ListKey: "maintenance_windows",
+		ListParameters: []*openapi.Parameter{
+			openapi.QueryParameter("not_ended", "Leave out the one-time windows that have already ended", &openapi.Schema{Type: "boolean"}),
+		},
+		CreateDTO: dtos.CreateMaintenanceWindowDTO{}, UpdateDTO: dtos.UpdateMaintenanceWindowDTO{},
+	})
+
+	addSecuredOperation(d, http.MethodGet, "/api/v1/maintenance-windows/:id/suppressions", &openapi.Operation{
+		OperationID: "getMaintenanceSuppressions",
+		Summary:     "List the matches suppressed by a maintenance window",
+		Tags:        []string{maintenanceWindowsTag},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The suppressions of the window", listSchema("suppressions", d.ArrayOf(models.MaintenanceSuppression{}))),
+		},
+	}, http.StatusInternalServerError)
+}
+
+// crudResource describes a resource of the API managed by the get, list, create, update and delete routes
+// handled by get<Name>, get<Names>, create<Name>, update<Name> and delete<Name>.
+type crudResource struct {
+	Path           string               // Path of the list of the resource, e.g. /api/v1/oncall/users; the item paths add /:id
+	Tag            string               // Tag of the operations
+	Name           string               // Name of the resource in the names of the handlers
+	Names          string               // Plural name of the resource in the name of the list handler
+	Title          string               // Title of the resource in the summaries
+	Titles         string               // Plural title of the resource in the summaries
+	Item           interface{}          // Model of the resource
+	ListKey        string               // Key of the list response; the get and list routes aren't documented if it's empty
+	ListParameters []*openapi.Parameter // Query parameters of the list
+	CreateDTO      interface{}          // DTO of the creation
+	UpdateDTO      interface{}          // DTO of the update
+}
+
+// documentCRUDRoutes describes the routes of the resource.
+func documentCRUDRoutes(d *openapi.Document, resource *crudResource) {
+	item := d.SchemaOf(resource.Item)
+	itemPath := strings.TrimSuffix(resource.Path, "/") + "/:id"
+
+	if resource.ListKey != "" {
+		addSecuredOperation(d, http.MethodGet, itemPath, &openapi.Operation{
+			OperationID: "get" + resource.Name,
+			Summary:     "Get " + withArticle(resource.Title),
+			Tags:        []string{resource.Tag},
+			Responses:   map[string]*openapi.Response{"200": jsonResponse("The "+resource.Title, item)},
+		}, http.StatusNotFound)
+
+		addSecuredOperation(d, http.MethodGet, resource.Path, &openapi.Operation{
+			OperationID: "get" + resource.Names,
+			Summary:     "List the " + resource.Titles,
+			Tags:        []string{resource.Tag},
+			Parameters:  resource.ListParameters,
+			Responses: map[string]*openapi.Response{
+				"200": jsonResponse("The "+resource.Titles, listSchema(resource.ListKey, d.ArrayOf(resource.Item))),
+			},
+		}, http.StatusInternalServerError)
+	}
+
+	addSecuredOperation(d, http.MethodPost, resource.Path, &openapi.Operation{
+		OperationID: "create" + resource.Name,
+		Summary:     "Create " + withArticle(resource.Title),
+		Tags:        []string{resource.Tag},
+		RequestBody: jsonRequestBody(d.SchemaOf(resource.CreateDTO)),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The created "+resource.Title, item)},
+	}, http.StatusBadRequest, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodPut, itemPath, &openapi.Operation{
+		OperationID: "update" + resource.Name,
+		Summary:     "Update the fields of " + withArticle(resource.Title),
+		Description: "Only the fields present in the body are updated.",
+		Tags:        []string{resource.Tag},
+		RequestBody: jsonRequestBody(d.SchemaOf(resource.UpdateDTO)),
+		Responses:   map[string]*openapi.Response{"200": jsonResponse("The updated "+resource.Title, item)},
+	}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError)
+
+	addSecuredOperation(d, http.MethodDelete, itemPath, &openapi.Operation{
+		OperationID: "delete" + resource.Name,
+		Summary:     "Delete " + withArticle(resource.Title),
+		Tags:        []string{resource.Tag},
+		Responses:   okResponses(),
+	}, http.StatusInternalServerError)
+}
+
+// withArticle returns the title with its indefinite article.
+func withArticle(title string) string {
+	if strings.ContainsRune("aeiou", rune(title[0])) {
+		return "an " + title
+	}
+	return "a " + title
+}
+
+// addSecuredOperation adds an operation requiring the JWT token, with the error responses of the statuses and of the token.
+func addSecuredOperation(d *openapi.Document, method, path string, operation *openapi.Operation, errorStatuses ...int) {
+	operation.Security = []map[string][]string{{openapi.BearerAuth: {}}}
+	d.AddOperation(method, path, withErrors(operation, append(errorStatuses, http.StatusUnauthorized)...))
+}
+
+// withErrors adds the error responses of the statuses to the operation.
+func withErrors(operation *openapi.Operation, statuses ...int) *openapi.Operation {
+	for _, status := range statuses {
+		operation.Responses[strconv.Itoa(status)] = &openapi.Response{
+			Description: errorDescriptions[status],
+			Headers: map[string]*openapi.Header{
+				v1.RequestIDHeader: {Description: "ID of the request, also given in the body", Schema: &openapi.Schema{Type: "string"}},
+			},
+			Content: openapi.JSONContent(&openapi.Schema{Ref: "#/components/schemas/ErrorResponse"}),
+		}
+	}
+	return operation
+}
+
+// jsonRequestBody returns a required JSON request body of the schema.
+func jsonRequestBody(schema *openapi.Schema) *openapi.RequestBody {
+	return &openapi.RequestBody{Required: true, Content: openapi.JSONContent(schema)}
+}
+
+// jsonResponse returns a JSON response of the schema.
+func jsonResponse(description string, schema *openapi.Schema) *openapi.Response {
+	return &openapi.Response{Description: description, Content: openapi.JSONContent(schema)}
+}
+
+// versionedResponse returns a JSON response of a record with its version in the ETag header.
+func versionedResponse(description string, schema *openapi.Schema) *openapi.Response {
+	response := jsonResponse(description, schema)
+	response.Headers = map[string]*openapi.Header{
+		"ETag": {Description: "Version of the record, e.g. \"3\", to be sent in If-Match", Schema: &openapi.Schema{Type: "string"}},
+	}
+	return response
+}
+
+// okResponses returns the responses of an operation answering {"message": "ok"}, e.g. of a deletion.
+func okResponses() map[string]*openapi.Response {
+	return map[string]*openapi.Response{"200": jsonResponse("The operation has succeeded", &openapi.Schema{Ref: "#/components/schemas/Message"})}
+}
+
+// attachmentHeaders returns the headers of a response downloaded as a file.
+func attachmentHeaders() map[string]*openapi.Header {
+	return map[string]*openapi.Header{
+		"Content-Disposition": {Description: "Name of the downloaded file", Schema: &openapi.Schema{Type: "string"}},
+	}
+}
+
+// listSchema returns the schema of an object holding a list under the key.
+func listSchema(key string, items *openapi.Schema) *openapi.Schema {
+	return openapi.ObjectOf(map[string]*openapi.Schema{key: items})
+}
+
+// pageSchema returns the schema of a page of a paginated list under the key.
+func pageSchema(key string, items *openapi.Schema) *openapi.Schema {
+	return openapi.ObjectOf(map[string]*openapi.Schema{
+		key:            items,
+		"current_page": {Type: "integer", Format: "int32"},
+		"page_size":    {Type: "integer", Format: "int32"},
+		"total_pages":  {Type: "integer", Format: "int32"},
+	})
+}
+
+// bulkResultSchema returns the schema of the results of a bulk action.
+func bulkResultSchema(d *openapi.Document, actions []string) *openapi.Schema {
+	return openapi.ObjectOf(map[string]*openapi.Schema{
+		"action":  {Type: "string", Enum: actions},
+		"counts":  {Type: "object", Description: "Number of the records by status", AdditionalProperties: &openapi.Schema{Type: "integer", Format: "int32"}},
+		"results": d.ArrayOf(models.BulkItemResult{}),
+	})
+}
+
+// paginationParameters returns the query parameters of the page of a list.
+func paginationParameters() []*openapi.Parameter {
+	return []*openapi.Parameter{
+		openapi.QueryParameter("page", "Number of the page", &openapi.Schema{Type: "integer", Format: "int32", Default: 1}),
+		openapi.QueryParameter("pageSize", "Number of the records per page", &openapi.Schema{Type: "integer", Format: "int32", Default: 10}),
+	}
+}
+
+// timeRangeParameters returns the query parameters of the range of the creation time; both must be set to filter by it.
+func timeRangeParameters() []*openapi.Parameter {
+	return []*openapi.Parameter{
+		openapi.QueryParameter("startTime", "Start of the range of the creation time", &openapi.Schema{Type: "string", Format: "date-time"}),
+		openapi.QueryParameter("endTime", "End of the range of the creation time", &openapi.Schema{Type: "string", Format: "date-time"}),
+	}
+}
+
+// sortParameters returns the query parameters sorting a list by a field of the model.
+func sortParameters(d *openapi.Document, model interface{}) []*openapi.Parameter {
+	properties := d.PropertiesOf(model)
+	fields := make([]string, 0, len(properties))
+	for field := range properties {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	return []*openapi.Parameter{
+		openapi.QueryParameter("sortBy", "Field to sort by", &openapi.Schema{Type: "string", Enum: fields, Default: "created_at"}),
+		openapi.QueryParameter("sortOrder", "Order of the sorting", &openapi.Schema{Type: "string", Enum: []string{"asc", "desc"}, Default: "desc"}),
+	}
+}
+
+// filterParameters returns the query parameters of the filters of a list of the model, with the schemas of the filtered fields.
+func filterParameters(d *openapi.Document, model interface{}, filters []queryFilter) []*openapi.Parameter {
+	parameters := make([]*openapi.Parameter, len(filters))
+	for i, filter := range filters {
+		description := "Filter by " + filter.Name
+		if filter.Kind == arrayQueryFilter {
+			description += "; repeat the parameter for several values"
+		}
+		parameters[i] = openapi.QueryParameter(filter.Name, description, d.PropertyOf(model, filter.Name))
+	}
+	return parameters
+}
+
+// muteExpiresWithinParameter returns the query parameter filtering the muted rules by the expiration of their mutes.
+func muteExpiresWithinParameter() *openapi.Parameter {
+	return openapi.QueryParameter("mute_expires_within", "Only the muted rules whose mutes expire within the duration, e.g. 24h",
+		&openapi.Schema{Type: "string", Format: "duration"})
+}
+
+// ifMatchParameter returns the If-Match header of an update, making it fail with 409 if the record has another version.
+func ifMatchParameter() *openapi.Parameter {
+	return openapi.HeaderParameter("If-Match", "Version the record must have, from the ETag of its last read, e.g. \"3\"")
+}
@@ -0,0 +1,213 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/openapi"
+	"dnywonnt.me/alerts2incidents/internal/models"
+)
+
+// jsonSchemaType returns the type of the schema of the JSON encoding of a Go type, or the name of its component for the named structs.
+func jsonSchemaType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return "string"
+	case t == reflect.TypeOf(time.Duration(0)):
+		return "integer"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct:
+		return "#/components/schemas/" + t.Name()
+	}
+	return "object"
+}
+
+// schemaType returns the type of the schema, or its reference.
+func schemaType(schema *openapi.Schema) string {
+	if schema.Ref != "" {
+		return schema.Ref
+	}
+	return schema.Type
+}
+
+func TestOpenAPIRequestBodiesMatchDTOs(t *testing.T) {
+	// The DTOs the handlers bind the bodies of the requests to; the rules import decodes a rules document
+	bodies := map[string]interface{}{
+		"POST /api/v1/incidents/":                     dtos.CreateIncidentDTO{},
+		"PUT /api/v1/incidents/:id":                   dtos.UpdateIncidentDTO{},
+		"POST /api/v1/incidents/bulk":                 dtos.BulkIncidentsDTO{},
+		"POST /api/v1/incidents/:id/links":            dtos.CreateIncidentLinkDTO{},
+		"POST /api/v1/incidents/:id/merge":            dtos.MergeIncidentsDTO{},
+		"POST /api/v1/incidents/:id/split":            dtos.SplitIncidentDTO{},
+		"POST /api/v1/rules/":                         dtos.CreateRuleDTO{},
+		"PUT /api/v1/rules/:id":                       dtos.UpdateRuleDTO{},
+		"POST /api/v1/rules/bulk":                     dtos.BulkRulesDTO{},
+		"POST /api/v1/rules/import":                   models.RulesDocument{},
+		"POST /api/v1/slo/":                           dtos.CreateSLODTO{},
+		"PUT /api/v1/slo/:id":                         dtos.UpdateSLODTO{},
+		"POST /api/v1/escalation-policies/":           dtos.CreateEscalationPolicyDTO{},
+		"PUT /api/v1/escalation-policies/:id":         dtos.UpdateEscalationPolicyDTO{},
+		"POST /api/v1/oncall/users":                   dtos.CreateOnCallUserDTO{},
+		"PUT /api/v1/oncall/users/:id":                dtos.UpdateOnCallUserDTO{},
+		"POST /api/v1/oncall/schedules":               dtos.CreateOnCallScheduleDTO{},
+		"PUT /api/v1/oncall/schedules/:id":            dtos.UpdateOnCallScheduleDTO{},
+		"POST /api/v1/oncall/schedules/:id/overrides": dtos.CreateOnCallOverrideDTO{},
+		"POST /api/v1/maintenance-windows/":           dtos.CreateMaintenanceWindowDTO{},
+		"PUT /api/v1/maintenance-windows/:id":         dtos.UpdateMaintenanceWindowDTO{},
+	}
+
+	document := OpenAPIDocument()
+	documented := 0
+	for _, pathItem := range document.Paths {
+		for _, operation := range pathItem {
+			if operation.RequestBody == nil {
+				continue
+			}
+			if content, ok := operation.RequestBody.Content["application/json"]; ok && content.Schema.Ref != "" {
+				documented++
+			}
+		}
+	}
+	if documented != len(bodies) {
+		t.Errorf("the document has %d operations with a DTO body, the test knows %d; add the new ones to the test", documented, len(bodies))
+	}
+
+	for route, dto := range bodies {
+		t.Run(route, func(t *testing.T) {
+			method, path, _ := strings.Cut(route, " ")
+			operation := document.Operation(method, path)
+			if operation == nil || operation.RequestBody == nil {
+				t.Fatalf("%s has no documented request body", route)
+			}
+
+			content, ok := operation.RequestBody.Content["application/json"]
+			if !ok {
+				t.Fatalf("%s has no JSON request body", route)
+			}
+
+			dtoType := reflect.TypeOf(dto)
+			schema := content.Schema
+			if want := jsonSchemaType(dtoType); schema.Ref != want {
+				t.Fatalf("the body is documented as %q, want %q", schemaType(schema), want)
+			}
+			properties := document.Components.Schemas[dtoType.Name()].Properties
+
+			fields := map[string]bool{}
+			for i := 0; i < dtoType.NumField(); i++ {
+				field := dtoType.Field(i)
+				name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if !field.IsExported() || name == "-" {
+					continue
+				}
+				fields[name] = true
+
+				property, ok := properties[name]
+				if !ok {
+					t.Errorf("the field %s isn't documented", name)
+					continue
+				}
+				if got, want := schemaType(property), jsonSchemaType(field.Type); got != want {
+					t.Errorf("the field %s is documented as %q, want %q", name, got, want)
+				}
+			}
+			for name := range properties {
+				if !fields[name] {
+					t.Errorf("the documented property %s isn't a field of %s", name, dtoType.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIListsDocumentQueryFilters(t *testing.T) {
+	// Schema types of the kinds of the query filters
+	filterTypes := map[int]string{
+		stringQueryFilter: "string",
+		boolQueryFilter:   "boolean",
+		intQueryFilter:    "integer",
+		arrayQueryFilter:  "array",
+	}
+
+	tests := []struct {
+		method  string
+		path    string
+		filters []queryFilter
+	}{
+		{http.MethodGet, "/api/v1/incidents/", incidentQueryFilters},
+		{http.MethodGet, "/api/v1/incidents/export", incidentQueryFilters},
+		{http.MethodPost, "/api/v1/incidents/bulk", incidentQueryFilters},
+		{http.MethodGet, "/api/v1/reports/:dimension", incidentQueryFilters},
+		{http.MethodGet, "/api/v1/rules/", ruleQueryFilters},
+		{http.MethodPost, "/api/v1/rules/bulk", ruleQueryFilters},
+	}
+
+	document := OpenAPIDocument()
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			operation := document.Operation(tt.method, tt.path)
+			if operation == nil {
+				t.Fatalf("%s %s isn't documented", tt.method, tt.path)
+			}
+
+			parameters := map[string]*openapi.Parameter{}
+			for _, parameter := range operation.Parameters {
+				if parameter.In == "query" {
+					parameters[parameter.Name] = parameter
+				}
+			}
+
+			for _, filter := range tt.filters {
+				parameter, ok := parameters[filter.Name]
+				if !ok {
+					t.Errorf("the filter %s isn't documented", filter.Name)
+					continue
+				}
+				if got, want := parameter.Schema.Type, filterTypes[filter.Kind]; got != want {
+					t.Errorf("the filter %s is documented as %q, want %q", filter.Name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIQueryFiltersAreModelFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   interface{}
+		filters []queryFilter
+	}{
+		{"incidents", models.Incident{}, incidentQueryFilters},
+		{"rules", models.Rule{}, ruleQueryFilters},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := OpenAPIDocument().PropertiesOf(tt.model)
+			for _, filter := range tt.filters {
+				if _, ok := properties[filter.Name]; !ok {
+					t.Errorf("the filter %s isn't a field of the model", filter.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterReportsRoutes sets up the routing of the incidents reports endpoints.
+func RegisterReportsRoutes(router *gin.Engine, repo *repositories.IncidentsRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/reports")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:dimension", getIncidentsReport(repo))
+}
+
+// getIncidentsReport returns a handler for aggregating the incidents by a dimension: quarter, departament,
+// failure_type, trouble_services or sale_channels. It accepts the same filters and time range as the incidents list.
+func getIncidentsReport(repo *repositories.IncidentsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dimension := c.Param("dimension")
+		if !repositories.IsReportDimension(dimension) {
+			c.Error(v1.NewNotFoundError(fmt.Sprintf("unknown report dimension: %s", dimension)))
+			return
+		}
+
+		startTime, endTime, err := parseTimeRange(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to parse time range")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		filterBy, err := buildFilterForIncidents(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"filterBy": filterBy,
+				"error":    err.Error(),
+			}).Error("Failed to build filter for incidents")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		rows, err := repo.GetIncidentsReport(c, dimension, filterBy, startTime.UTC(), endTime.UTC())
+		if err != nil {
+			log.WithFields(log.Fields{
+				"dimension": dimension,
+				"error":     err.Error(),
+			}).Error("Failed to aggregate incidents for a report")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"dimension": dimension,
+			"rows":      rows,
+		})
+	}
+}
+
+// parseTimeRange parses the optional "startTime" and "endTime" query parameters in the RFC 3339 format.
+func parseTimeRange(c *gin.Context) (time.Time, time.Time, error) {
+	startTime, endTime := time.Time{}, time.Time{}
+	var err error
+
+	if startTimeStr := c.Query("startTime"); startTimeStr != "" {
+		if startTime, err = time.Parse(time.RFC3339, startTimeStr); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("error parsing start time: %w", err)
+		}
+	}
+	if endTimeStr := c.Query("endTime"); endTimeStr != "" {
+		if endTime, err = time.Parse(time.RFC3339, endTimeStr); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("error parsing end time: %w", err)
+		}
+	}
+
+	return startTime, endTime, nil
+}
@@ -21,9 +21,12 @@ func RegisterRulesRoutes(router *gin.Engine, repo *repositories.RulesRepository,
 
 	routerGroup.Use(v1.JWTMiddleware(apiCfg))
 
+	routerGroup.GET("/export", exportRules(repo))
+	routerGroup.POST("/import", importRules(repo))
 	routerGroup.GET("/:id", getRule(repo))
 	routerGroup.GET("/", getRules(repo))
 	routerGroup.POST("/", createRule(repo))
+	routerGroup.POST("/bulk", bulkRules(repo))
 	routerGroup.PUT("/:id", updateRule(repo))
 	routerGroup.DELETE("/:id", deleteRule(repo))
 }
@@ -37,9 +40,10 @@ func getRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to retrieve rule")
-			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			c.Error(repositoryError(err, "rule not found"))
 			return
 		}
+		setVersionETag(c, rule.Version)
 		c.JSON(http.StatusOK, rule)
 	}
 }
@@ -55,7 +59,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"page":  pageStr,
 				"error": err.Error(),
 			}).Error("Failed to parse pagination parameter")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -65,7 +69,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"pageSize": pageSizeStr,
 				"error":    err.Error(),
 			}).Error("Failed to parse pagination parameter")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -78,7 +82,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 					"startTime": startTimeStr,
 					"error":     err.Error(),
 				}).Error("Failed to parse start time")
-				c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+				c.Error(v1.NewValidationError(err))
 				return
 			}
 		}
@@ -92,7 +96,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 					"endTime": endTimeStr,
 					"error":   err.Error(),
 				}).Error("Failed to parse end time")
-				c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+				c.Error(v1.NewValidationError(err))
 				return
 			}
 		}
@@ -103,7 +107,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"filterBy": filterBy,
 				"error":    err.Error(),
 			}).Error("Failed to build filter for rules")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -115,7 +119,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to retrieve rules")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -124,7 +128,7 @@ func getRules(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to get total rules count")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 		totalPages := utils.CalculatePages(totalRules, pageSize)
@@ -146,7 +150,7 @@ func createRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to unmarshal request JSON data")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -155,7 +159,7 @@ func createRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to map DTO to rule model")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -163,7 +167,7 @@ func createRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to create rule")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -179,7 +183,7 @@ func updateRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to unmarshal request JSON data")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -189,7 +193,17 @@ func updateRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to retrieve rule")
-			c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+			c.Error(repositoryError(err, "rule not found"))
+			return
+		}
+
+		if !matchesIfMatch(c, rule.Version) {
+			log.WithFields(log.Fields{
+				"id":      c.Param("id"),
+				"ifMatch": c.GetHeader("If-Match"),
+				"version": rule.Version,
+			}).Error("Rule version doesn't match")
+			c.Error(v1.NewConflictError(repositories.ErrVersionConflict))
 			return
 		}
 
@@ -197,7 +211,7 @@ func updateRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"error": err.Error(),
 			}).Error("Failed to map DTO to rule model")
-			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			c.Error(v1.NewValidationError(err))
 			return
 		}
 
@@ -206,10 +220,11 @@ func updateRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to update rule")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(repositoryError(err, "rule not found"))
 			return
 		}
 
+		setVersionETag(c, rule.Version)
 		c.JSON(http.StatusOK, rule)
 	}
 }
@@ -222,7 +237,7 @@ func deleteRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 				"id":    c.Param("id"),
 				"error": err.Error(),
 			}).Error("Failed to delete rule")
-			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			c.Error(v1.NewInternalError(err))
 			return
 		}
 
@@ -232,50 +247,19 @@ func deleteRule(repo *repositories.RulesRepository) gin.HandlerFunc {
 
 // buildFilterForRules constructs a map of filters for querying rules based on request query parameters.
 func buildFilterForRules(c *gin.Context) (map[string]interface{}, error) {
-	filter := make(map[string]interface{})
-
-	addToFilterIfNotEmpty := func(key, value string) {
-		if value != "" {
-			filter[key] = value
-		}
-	}
-
-	addToFilterIfArrayNotEmpty := func(key string, values []string) {
-		if len(values) > 0 {
-			filter[key] = values
-		}
+	filter, err := buildFilter(c, ruleQueryFilters)
+	if err != nil {
+		return nil, err
 	}
 
-	parseAndAddToFilter := func(key string, parseFunc func(string) (interface{}, error)) error {
-		valueStr := c.Query(key)
-		if valueStr != "" {
-			value, err := parseFunc(valueStr)
-			if err != nil {
-				return err
-			}
-			filter[key] = value
+	// Muted rules expiring soon: the ones whose mutes expire within the given duration, e.g. "24h"
+	if muteExpiresWithinStr := c.Query("mute_expires_within"); muteExpiresWithinStr != "" {
+		muteExpiresWithin, err := time.ParseDuration(muteExpiresWithinStr)
+		if err != nil {
+			return nil, err
 		}
-		return nil
-	}
-
-	parseBool := func(str string) (interface{}, error) {
-		return strconv.ParseBool(str)
+		filter["mute_expires_before"] = time.Now().UTC().Add(muteExpiresWithin)
 	}
 
-	if err := parseAndAddToFilter("is_muted", parseBool); err != nil {
-		return nil, err
-	}
-	if err := parseAndAddToFilter("set_incident_is_downtime", parseBool); err != nil {
-		return nil, err
-	}
-
-	addToFilterIfArrayNotEmpty("set_incident_sale_channels", c.QueryArray("set_incident_sale_channels"))
-	addToFilterIfArrayNotEmpty("set_incident_trouble_services", c.QueryArray("set_incident_trouble_services"))
-	addToFilterIfArrayNotEmpty("set_incident_labels", c.QueryArray("set_incident_labels"))
-
-	addToFilterIfNotEmpty("set_incident_departament", c.Query("set_incident_departament"))
-	addToFilterIfNotEmpty("set_incident_is_manageable", c.Query("set_incident_is_manageable"))
-	addToFilterIfNotEmpty("set_incident_failure_type", c.Query("set_incident_failure_type"))
-
 	return filter, nil
 }
@@ -0,0 +1,152 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Formats of the rules documents.
+const (
+	yamlRulesFormat = "yaml"
+	jsonRulesFormat = "json"
+)
+
+// exportRules returns a handler for exporting all rules as a YAML or JSON rules document,
+// ordered by ID so the exports of the same rules are identical and diff well in git.
+func exportRules(repo *repositories.RulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", yamlRulesFormat)
+		if format != yamlRulesFormat && format != jsonRulesFormat {
+			c.Error(v1.NewValidationError(fmt.Errorf("unknown rules document format: %s", format)))
+			return
+		}
+
+		rules, err := repo.GetAllRules(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to retrieve rules")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		document := &models.RulesDocument{Rules: make([]*models.RuleSpec, len(rules))}
+		for i, rule := range rules {
+			document.Rules[i] = models.NewRuleSpec(rule)
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="rules.%s"`, format))
+		if format == jsonRulesFormat {
+			c.IndentedJSON(http.StatusOK, document)
+			return
+		}
+
+		data := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(data)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to marshal rules document")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", data.Bytes())
+	}
+}
+
+// importRules returns a handler for importing a YAML or JSON rules document. The format is taken
+// from the format query parameter or the content type of the request, YAML by default. The dry_run
+// query parameter only plans the changes, and the prune one deletes the rules absent from the document.
+func importRules(repo *repositories.RulesRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+		if err != nil {
+			c.Error(v1.NewValidationError(fmt.Errorf("invalid dry_run: %w", err)))
+			return
+		}
+		prune, err := strconv.ParseBool(c.DefaultQuery("prune", "false"))
+		if err != nil {
+			c.Error(v1.NewValidationError(fmt.Errorf("invalid prune: %w", err)))
+			return
+		}
+
+		format := c.Query("format")
+		if format == "" {
+			format = yamlRulesFormat
+			if strings.Contains(c.ContentType(), jsonRulesFormat) {
+				format = jsonRulesFormat
+			}
+		}
+
+		document, err := decodeRulesDocument(c.Request.Body, format)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"format": format,
+				"error":  err.Error(),
+			}).Error("Failed to decode rules document")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+		if err := document.Validate(); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to validate rules document")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		result, err := repo.ImportRules(c, document, prune, dryRun)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"prune":  prune,
+				"dryRun": dryRun,
+				"error":  err.Error(),
+			}).Error("Failed to import rules")
+			c.Error(repositoryError(err, "rule not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// decodeRulesDocument decodes a rules document of the format, rejecting the unknown fields.
+func decodeRulesDocument(r io.Reader, format string) (*models.RulesDocument, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the rules document: %w", err)
+	}
+
+	document := &models.RulesDocument{}
+	switch format {
+	case jsonRulesFormat:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(document)
+	case yamlRulesFormat:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(document)
+	default:
+		return nil, fmt.Errorf("unknown rules document format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding the rules document: %w", err)
+	}
+
+	return document, nil
+}
@@ -0,0 +1,195 @@
+package handlers // dnywonnt.me/alerts2incidents/internal/api/v1/handlers
+
+import (
+	"net/http"
+	"time"
+
+	v1 "dnywonnt.me/alerts2incidents/internal/api/v1"
+	"dnywonnt.me/alerts2incidents/internal/api/v1/dtos"
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"github.com/gin-gonic/gin"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RegisterSLOsRoutes sets up the routing for service-level objective API endpoints.
+func RegisterSLOsRoutes(router *gin.Engine, repo *repositories.SLOsRepository, apiCfg *config.ApiConfig) {
+	routerGroup := router.Group("/api/v1/slo")
+
+	routerGroup.Use(v1.JWTMiddleware(apiCfg))
+
+	routerGroup.GET("/:id", getSLO(repo))
+	routerGroup.GET("/", getSLOs(repo))
+	routerGroup.POST("/", createSLO(repo))
+	routerGroup.PUT("/:id", updateSLO(repo))
+	routerGroup.DELETE("/:id", deleteSLO(repo))
+}
+
+// getSLO returns a handler for retrieving the status of a single objective by its ID.
+// The optional "at" query parameter (RFC3339) selects the period; the current one is used by default.
+func getSLO(repo *repositories.SLOsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at, err := parseSLOTime(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"at":    c.Query("at"),
+				"error": err.Error(),
+			}).Error("Failed to parse SLO time")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		slo, err := repo.GetSLO(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve SLO")
+			c.Error(repositoryError(err, "SLO not found"))
+			return
+		}
+
+		status, err := repo.GetSLOStatus(c, slo, at)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to compute SLO status")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// getSLOs returns a handler for retrieving the statuses of all objectives with their error budgets.
+// There are only a few objectives, at most one per target and period, so they aren't paginated.
+func getSLOs(repo *repositories.SLOsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		at, err := parseSLOTime(c)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"at":    c.Query("at"),
+				"error": err.Error(),
+			}).Error("Failed to parse SLO time")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		statuses, err := repo.GetSLOStatuses(c, at)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to compute SLO statuses")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"slos": statuses,
+		})
+	}
+}
+
+// createSLO returns a handler for creating a new objective based on provided data.
+func createSLO(repo *repositories.SLOsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.CreateSLODTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		slo, err := v1.MapCreateSLODTOToModel(dto)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to SLO model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.CreateSLO(c, slo); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to create SLO")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, slo)
+	}
+}
+
+// updateSLO returns a handler for updating an existing objective.
+func updateSLO(repo *repositories.SLOsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dto := &dtos.UpdateSLODTO{}
+		if err := c.ShouldBindJSON(dto); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to unmarshal request JSON data")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		slo, err := repo.GetSLO(c, c.Param("id"))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to retrieve SLO")
+			c.Error(repositoryError(err, "SLO not found"))
+			return
+		}
+
+		if err := v1.MapUpdateSLODTOToModel(dto, slo); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to map DTO to SLO model")
+			c.Error(v1.NewValidationError(err))
+			return
+		}
+
+		if err := repo.UpdateSLO(c, slo); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to update SLO")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, slo)
+	}
+}
+
+// deleteSLO returns a handler for deleting an objective by its ID.
+func deleteSLO(repo *repositories.SLOsRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := repo.DeleteSLO(c, c.Param("id")); err != nil {
+			log.WithFields(log.Fields{
+				"id":    c.Param("id"),
+				"error": err.Error(),
+			}).Error("Failed to delete SLO")
+			c.Error(v1.NewInternalError(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "ok"})
+	}
+}
+
+// parseSLOTime parses the optional "at" query parameter selecting the period of the objectives, defaulting to now.
+func parseSLOTime(c *gin.Context) (time.Time, error) {
+	atStr := c.Query("at")
+	if atStr == "" {
+		return time.Now().UTC(), nil
+	}
+	return time.Parse(time.RFC3339, atStr)
+}
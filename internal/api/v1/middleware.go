@@ -1,16 +1,82 @@
 package v1 // dnywonnt.me/alerts2incidents/internal/api/v1
 
 import (
-	"net/http"
+	"errors"
+	"regexp"
 	"strings"
 	"time"
 
+	"dnywonnt.me/alerts2incidents/internal/api/v1/openapi"
 	"dnywonnt.me/alerts2incidents/internal/config"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 
 	log "github.com/sirupsen/logrus"
 )
 
+// RequestIDHeader is the header carrying the ID of a request, either passed by the client or generated by RequestIDMiddleware.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the key of the request ID in the Gin context.
+const requestIDKey = "requestID"
+
+// requestIDRegexp matches the request IDs accepted from the clients, so the logs and the responses can't be polluted with arbitrary text.
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware returns a Gin middleware function that assigns an ID to the request: the one passed by the client
+// in the X-Request-ID header, if it's valid, or a new UUID. The ID is returned in the same header of the response.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDRegexp.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// RequestID returns the ID assigned to the request by RequestIDMiddleware.
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// ErrorMiddleware returns a Gin middleware function that renders the error attached to the context by a handler
+// or a middleware with c.Error as the JSON ErrorResponse. The errors other than APIError are rendered as internal errors,
+// and nothing is rendered if the handler has already written the response, e.g. a stream interrupted by the error.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		ginErr := c.Errors.Last()
+		if ginErr == nil || c.Writer.Written() {
+			return
+		}
+
+		apiErr := &APIError{}
+		if !errors.As(ginErr.Err, &apiErr) {
+			apiErr = NewInternalError(ginErr.Err)
+		}
+
+		c.JSON(apiErr.Status, &ErrorResponse{
+			Code:      apiErr.Code,
+			Message:   apiErr.Message,
+			Details:   apiErr.Details,
+			RequestID: RequestID(c),
+		})
+	}
+}
+
+// NoRouteHandler returns a Gin handler reporting the requests to the unknown routes as the not found errors.
+func NoRouteHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Error(NewNotFoundError("route not found"))
+	}
+}
+
 // LoggerMiddleware returns a Gin middleware function that logs information about incoming requests.
 func LoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -23,11 +89,12 @@ func LoggerMiddleware() gin.HandlerFunc {
 
 		// Log information about the received request.
 		log.WithFields(log.Fields{
-			"method":  c.Request.Method,   // HTTP method of the request.
-			"path":    c.Request.URL.Path, // Request path.
-			"status":  c.Writer.Status(),  // HTTP status code of the response.
-			"ip":      c.ClientIP(),       // Client IP address.
-			"latency": latency.String(),   // Duration of the request processing as a string.
+			"requestID": RequestID(c),       // ID of the request, see RequestIDMiddleware.
+			"method":    c.Request.Method,   // HTTP method of the request.
+			"path":      c.Request.URL.Path, // Request path.
+			"status":    c.Writer.Status(),  // HTTP status code of the response.
+			"ip":        c.ClientIP(),       // Client IP address.
+			"latency":   latency.String(),   // Duration of the request processing as a string.
 		}).Info("Received a new request")
 	}
 }
@@ -39,7 +106,7 @@ func JWTMiddleware(apiCfg *config.ApiConfig) gin.HandlerFunc {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			log.Error("Authorization header is missing")
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "authorization header is missing"})
+			c.Error(NewUnauthorizedError("authorization header is missing"))
 			c.Abort()
 			return
 		}
@@ -50,7 +117,7 @@ func JWTMiddleware(apiCfg *config.ApiConfig) gin.HandlerFunc {
 			log.WithFields(log.Fields{
 				"authHeader": authHeader,
 			}).Error("Invalid token format")
-			c.JSON(http.StatusUnauthorized, gin.H{"message": "invalid token format"})
+			c.Error(NewUnauthorizedError("invalid token format"))
 			c.Abort()
 			return
 		}
@@ -63,7 +130,32 @@ func JWTMiddleware(apiCfg *config.ApiConfig) gin.HandlerFunc {
 				"token": tokenString,
 				"error": err,
 			}).Error("Failed to validate token")
-			c.JSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+			c.Error(NewUnauthorizedError("invalid or expired token"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// QueryValidationMiddleware rejects the requests whose query parameters don't match the ones documented for their routes
+// in the OpenAPI document, e.g. an unknown sort field or a malformed time, before they reach the handlers.
+func QueryValidationMiddleware(document *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation := document.Operation(c.Request.Method, c.FullPath())
+		if operation == nil {
+			c.Next()
+			return
+		}
+
+		if err := operation.ValidateQuery(c.Request.URL.Query()); err != nil {
+			log.WithFields(log.Fields{
+				"method": c.Request.Method,
+				"path":   c.FullPath(),
+				"error":  err.Error(),
+			}).Error("Invalid query parameters")
+			c.Error(NewValidationError(err))
 			c.Abort()
 			return
 		}
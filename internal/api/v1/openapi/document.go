@@ -0,0 +1,183 @@
+package openapi // dnywonnt.me/alerts2incidents/internal/api/v1/openapi
+
+import "strings"
+
+// Version is the version of the OpenAPI specification the documents follow.
+const Version = "3.0.3"
+
+// BearerAuth is the name of the security scheme of the JWT tokens issued by the LDAP authentication.
+const BearerAuth = "bearerAuth"
+
+// Document is the root of an OpenAPI document; only the parts used by the API are modeled.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`    // Version of the OpenAPI specification
+	Info       *Info               `json:"info"`       // Metadata of the API
+	Paths      map[string]PathItem `json:"paths"`      // Operations by path, with the path parameters in braces
+	Components *Components         `json:"components"` // Reusable schemas and security schemes
+}
+
+// Info holds the metadata of the API.
+type Info struct {
+	Title       string `json:"title"`                 // Title of the API
+	Description string `json:"description,omitempty"` // Description of the API
+	Version     string `json:"version"`               // Version of the API
+}
+
+// PathItem holds the operations of a path by the lower-cased HTTP method.
+type PathItem map[string]*Operation
+
+// Operation describes an endpoint of the API.
+type Operation struct {
+	OperationID string                `json:"operationId"`           // Name of the handler of the endpoint
+	Summary     string                `json:"summary"`               // Short description of the endpoint
+	Description string                `json:"description,omitempty"` // Details of the endpoint
+	Tags        []string              `json:"tags,omitempty"`        // Groups of the endpoint, e.g. incidents
+	Parameters  []*Parameter          `json:"parameters,omitempty"`  // Path, query and header parameters
+	RequestBody *RequestBody          `json:"requestBody,omitempty"` // Body of the request, if any
+	Responses   map[string]*Response  `json:"responses"`             // Responses by HTTP status code
+	Security    []map[string][]string `json:"security,omitempty"`    // Security requirements; empty for the public endpoints
+}
+
+// Parameter describes a path, query or header parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`                  // Name of the parameter
+	In          string  `json:"in"`                    // Location of the parameter: path, query or header
+	Description string  `json:"description,omitempty"` // Description of the parameter
+	Required    bool    `json:"required,omitempty"`    // Flag indicating if the parameter is required; path parameters always are
+	Schema      *Schema `json:"schema"`                // Schema of the value of the parameter
+	Explode     *bool   `json:"explode,omitempty"`     // Flag indicating if an array is passed as repeated parameters
+}
+
+// RequestBody describes the body of a request by the content type.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"` // Description of the body
+	Required    bool                  `json:"required,omitempty"`    // Flag indicating if the body is required
+	Content     map[string]*MediaType `json:"content"`               // Schemas of the body by the content type
+}
+
+// Response describes a response of an operation.
+type Response struct {
+	Description string                `json:"description"`       // Description of the response
+	Headers     map[string]*Header    `json:"headers,omitempty"` // Headers of the response by name
+	Content     map[string]*MediaType `json:"content,omitempty"` // Schemas of the body by the content type
+}
+
+// Header describes a header of a response.
+type Header struct {
+	Description string  `json:"description,omitempty"` // Description of the header
+	Schema      *Schema `json:"schema"`                // Schema of the value of the header
+}
+
+// MediaType holds the schema of a body of a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"` // Schema of the body
+}
+
+// Components holds the reusable parts of the document.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`         // Schemas by the name of their Go types
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"` // Security schemes by name
+}
+
+// SecurityScheme describes an authentication method of the API.
+type SecurityScheme struct {
+	Type         string `json:"type"`                   // Type of the scheme, e.g. http
+	Scheme       string `json:"scheme,omitempty"`       // HTTP authentication scheme, e.g. bearer
+	BearerFormat string `json:"bearerFormat,omitempty"` // Format of the bearer tokens, e.g. JWT
+}
+
+// Schema is a JSON schema of a value, either inline or a reference to a component.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`                 // Reference to a component schema
+	Type                 string             `json:"type,omitempty"`                 // Type of the value
+	Format               string             `json:"format,omitempty"`               // Format of the value, e.g. date-time
+	Description          string             `json:"description,omitempty"`          // Description of the value
+	Nullable             bool               `json:"nullable,omitempty"`             // Flag indicating if the value may be null
+	Enum                 []string           `json:"enum,omitempty"`                 // Allowed values
+	Default              interface{}        `json:"default,omitempty"`              // Default value
+	Minimum              *float64           `json:"minimum,omitempty"`              // Minimum of a number
+	Maximum              *float64           `json:"maximum,omitempty"`              // Maximum of a number
+	MinItems             *int               `json:"minItems,omitempty"`             // Minimum number of the items of an array
+	Items                *Schema            `json:"items,omitempty"`                // Schema of the items of an array
+	Properties           map[string]*Schema `json:"properties,omitempty"`           // Schemas of the properties of an object
+	Required             []string           `json:"required,omitempty"`             // Required properties of an object
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"` // Schema of the values of a map
+}
+
+// NewDocument creates an empty document of the API with the bearer authentication scheme.
+func NewDocument(title, description, version string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: &Info{
+			Title:       title,
+			Description: description,
+			Version:     version,
+		},
+		Paths: map[string]PathItem{},
+		Components: &Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+}
+
+// AddOperation adds the operation of the method to the path, given in the Gin syntax, e.g. /api/v1/incidents/:id;
+// the path parameters are converted to the OpenAPI syntax and added to the operation, unless it already has them.
+func (d *Document) AddOperation(method, path string, operation *Operation) {
+	openAPIPath, pathParameters := convertPath(path)
+	missingParameters := []*Parameter{}
+	for _, name := range pathParameters {
+		if !operation.hasParameter(name, "path") {
+			missingParameters = append(missingParameters, PathParameter(name, ""))
+		}
+	}
+	operation.Parameters = append(missingParameters, operation.Parameters...)
+
+	if _, ok := d.Paths[openAPIPath]; !ok {
+		d.Paths[openAPIPath] = PathItem{}
+	}
+	d.Paths[openAPIPath][strings.ToLower(method)] = operation
+}
+
+// Operation returns the operation of the method and the path, given in the Gin syntax, or nil if it isn't documented.
+func (d *Document) Operation(method, path string) *Operation {
+	openAPIPath, _ := convertPath(path)
+	return d.Paths[openAPIPath][strings.ToLower(method)]
+}
+
+// hasParameter reports whether the operation has the parameter in the location.
+func (o *Operation) hasParameter(name, in string) bool {
+	for _, parameter := range o.Parameters {
+		if parameter.Name == name && parameter.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// PathParameter creates a string path parameter.
+func PathParameter(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+// QueryParameter creates an optional query parameter of the schema.
+func QueryParameter(name, description string, schema *Schema) *Parameter {
+	parameter := &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+	if schema.Type == "array" {
+		explode := true
+		parameter.Explode = &explode
+	}
+	return parameter
+}
+
+// HeaderParameter creates an optional string header parameter.
+func HeaderParameter(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// JSONContent returns the content of a JSON body of the schema.
+func JSONContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
@@ -0,0 +1,79 @@
+package openapi // dnywonnt.me/alerts2incidents/internal/api/v1/openapi
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// closureSuffixRegexp matches the suffix of the names of the closures, e.g. ".func1", returned by the handler constructors.
+var closureSuffixRegexp = regexp.MustCompile(`(\.func\d+)+$`)
+
+// CheckRoutes checks that the document describes exactly the routes of the router: every route must be documented
+// with the name of its handler as the operation ID, and every documented operation must be routed.
+// It returns an error listing all the differences.
+func CheckRoutes(document *Document, routes gin.RoutesInfo) error {
+	problems := []string{}
+
+	routed := map[string]bool{}
+	for _, route := range routes {
+		path, _ := convertPath(route.Path)
+		method := strings.ToLower(route.Method)
+		routed[method+" "+path] = true
+
+		operation := document.Paths[path][method]
+		if operation == nil {
+			problems = append(problems, fmt.Sprintf("%s %s isn't documented", route.Method, route.Path))
+			continue
+		}
+		if handlerName := HandlerName(route.Handler); operation.OperationID != handlerName {
+			problems = append(problems, fmt.Sprintf("%s %s is handled by %s, but documented as %s",
+				route.Method, route.Path, handlerName, operation.OperationID))
+		}
+	}
+
+	for path, pathItem := range document.Paths {
+		for method := range pathItem {
+			if !routed[method+" "+path] {
+				problems = append(problems, fmt.Sprintf("%s %s is documented, but not routed", strings.ToUpper(method), path))
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	slices.Sort(problems)
+	return errors.New("the OpenAPI document doesn't match the routes: " + strings.Join(problems, "; "))
+}
+
+// HandlerName returns the name of the function constructing the handler from the full name of the handler
+// reported by Gin, e.g. getIncident for dnywonnt.me/alerts2incidents/internal/api/v1/handlers.getIncident.func1.
+func HandlerName(fullName string) string {
+	name := closureSuffixRegexp.ReplaceAllString(fullName, "")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// convertPath converts a path in the Gin syntax, e.g. /api/v1/incidents/:id, to the OpenAPI syntax, e.g. /api/v1/incidents/{id},
+// and returns the names of its parameters.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	parameters := []string{}
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			parameters = append(parameters, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), parameters
+}
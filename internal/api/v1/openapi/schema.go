@@ -0,0 +1,183 @@
+package openapi // dnywonnt.me/alerts2incidents/internal/api/v1/openapi
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// componentsPrefix is the prefix of the references to the component schemas.
+const componentsPrefix = "#/components/schemas/"
+
+// Types with the schemas different from the ones of their kinds.
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// SchemaOf returns the schema of the JSON encoding of the value's type. The named structs are added
+// to the components once and referenced; their properties follow the json tags, and the validate tags
+// of the models give the required properties, the allowed values and the bounds.
+func (d *Document) SchemaOf(value interface{}) *Schema {
+	return d.schemaOfType(reflect.TypeOf(value))
+}
+
+// ArrayOf returns the schema of an array of the items of the value's type.
+func (d *Document) ArrayOf(value interface{}) *Schema {
+	return &Schema{Type: "array", Items: d.SchemaOf(value)}
+}
+
+// ObjectOf returns the schema of an object with the properties, all of them required.
+func ObjectOf(properties map[string]*Schema) *Schema {
+	schema := &Schema{Type: "object", Properties: properties}
+	for name := range properties {
+		schema.Required = append(schema.Required, name)
+	}
+	slices.Sort(schema.Required)
+	return schema
+}
+
+// PropertiesOf returns the schemas of the properties of the value's struct type.
+func (d *Document) PropertiesOf(value interface{}) map[string]*Schema {
+	schema := d.SchemaOf(value)
+	if schema.Ref != "" {
+		schema = d.Components.Schemas[strings.TrimPrefix(schema.Ref, componentsPrefix)]
+	}
+	return schema.Properties
+}
+
+// PropertyOf returns a copy of the schema of the property of the value's struct type, e.g. to describe a query parameter
+// filtering by the property; it returns a schema of any value if the struct has no such property.
+func (d *Document) PropertyOf(value interface{}, name string) *Schema {
+	property, ok := d.PropertiesOf(value)[name]
+	if !ok {
+		return &Schema{}
+	}
+	propertyCopy := *property
+	propertyCopy.Nullable = false
+	return &propertyCopy
+}
+
+// schemaOfType returns the schema of the JSON encoding of the type.
+func (d *Document) schemaOfType(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "Duration in nanoseconds"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := d.schemaOfType(t.Elem())
+		// The references can't be nullable in OpenAPI 3.0, so only the inline schemas are marked.
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaOfType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		if _, ok := d.Components.Schemas[t.Name()]; !ok {
+			// A placeholder stops the recursion of the self-referencing types.
+			d.Components.Schemas[t.Name()] = &Schema{}
+			*d.Components.Schemas[t.Name()] = *d.structSchema(t)
+		}
+		return &Schema{Ref: componentsPrefix + t.Name()}
+	}
+
+	// Interfaces and the other kinds may hold any value.
+	return &Schema{}
+}
+
+// structSchema returns the object schema of the exported fields of the struct.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		// The request structs bound by Gin declare their rules in the binding tags.
+		validateTag, ok := field.Tag.Lookup("validate")
+		if !ok {
+			validateTag = field.Tag.Get("binding")
+		}
+
+		property := d.schemaOfType(field.Type)
+		if applyValidateTag(property, validateTag) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// applyValidateTag applies the rules of a validate tag to the schema of a field and reports whether the field is required.
+// Only the unconditional rules are applied: the alternatives of a rule separated by "|" and the conditional
+// requirements, e.g. required_if, depend on the other fields and aren't expressed by the schema.
+func applyValidateTag(schema *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			// The rules after dive apply to the items.
+			break
+		}
+		if strings.Contains(rule, "|") {
+			continue
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "min", "gte":
+			if bound, err := strconv.ParseFloat(param, 64); err == nil {
+				if schema.Type == "array" {
+					minItems := int(bound)
+					schema.MinItems = &minItems
+				} else if isNumber(schema) {
+					schema.Minimum = &bound
+				}
+			}
+		case "max", "lte":
+			if bound, err := strconv.ParseFloat(param, 64); err == nil && isNumber(schema) {
+				schema.Maximum = &bound
+			}
+		}
+	}
+	return required
+}
+
+// isNumber reports whether the schema is of a number, so the bounds of the validate tags are its minimum and maximum.
+func isNumber(schema *Schema) bool {
+	return schema.Type == "integer" || schema.Type == "number"
+}
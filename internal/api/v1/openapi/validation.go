@@ -0,0 +1,95 @@
+package openapi // dnywonnt.me/alerts2incidents/internal/api/v1/openapi
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ParameterError is an error of a query parameter that doesn't match its documentation.
+type ParameterError struct {
+	Name    string // Name of the parameter
+	Rule    string // Rule of the schema the parameter has failed: required, enum, type, minimum, maximum or format
+	Message string // Description of the failure
+}
+
+// Error returns the description of the failure along with the name of the parameter.
+func (e *ParameterError) Error() string {
+	if e.Rule == "required" {
+		return fmt.Sprintf("query parameter %s is required", e.Name)
+	}
+	return fmt.Sprintf("invalid query parameter %s: %s", e.Name, e.Message)
+}
+
+// ValidateQuery checks the query parameters of a request against the documented ones of the operation:
+// the required parameters must be present, and the values must be of the types, formats, allowed values and bounds
+// of their schemas. The undocumented parameters and the empty values, treated by the handlers as absent, are ignored.
+// The first failed parameter is reported as a ParameterError.
+func (o *Operation) ValidateQuery(query url.Values) error {
+	for _, parameter := range o.Parameters {
+		if parameter.In != "query" {
+			continue
+		}
+
+		values, ok := query[parameter.Name]
+		if !ok {
+			if parameter.Required {
+				return &ParameterError{Name: parameter.Name, Rule: "required", Message: "the parameter is required"}
+			}
+			continue
+		}
+
+		schema := parameter.Schema
+		if schema.Type == "array" && schema.Items != nil {
+			schema = schema.Items
+		}
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if rule, err := validateValue(schema, value); err != nil {
+				return &ParameterError{Name: parameter.Name, Rule: rule, Message: err.Error()}
+			}
+		}
+	}
+	return nil
+}
+
+// validateValue checks a value of a parameter against its schema and returns the failed rule along with the error.
+func validateValue(schema *Schema, value string) (string, error) {
+	if len(schema.Enum) > 0 && !slices.Contains(schema.Enum, value) {
+		return "enum", fmt.Errorf("%q is not one of %v", value, schema.Enum)
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || (schema.Type == "integer" && number != float64(int64(number))) {
+			return "type", fmt.Errorf("%q is not a valid %s", value, schema.Type)
+		}
+		if schema.Minimum != nil && number < *schema.Minimum {
+			return "minimum", fmt.Errorf("%s is less than %v", value, *schema.Minimum)
+		}
+		if schema.Maximum != nil && number > *schema.Maximum {
+			return "maximum", fmt.Errorf("%s is greater than %v", value, *schema.Maximum)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "type", fmt.Errorf("%q is not a valid boolean", value)
+		}
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return "format", fmt.Errorf("%q is not an RFC 3339 date-time", value)
+			}
+		case "duration":
+			if _, err := time.ParseDuration(value); err != nil {
+				return "format", fmt.Errorf("%q is not a valid duration, e.g. 24h", value)
+			}
+		}
+	}
+	return "", nil
+}
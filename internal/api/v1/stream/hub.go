@@ -0,0 +1,149 @@
+package stream // dnywonnt.me/alerts2incidents/internal/api/v1/stream
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ResetEventType is the type of the event telling a client that the missed events can't be replayed,
+	// so it has to reload the current state before applying the next events.
+	ResetEventType = "reset"
+
+	// subscriberBufferSize is the number of events buffered for a subscriber; a subscriber lagging further behind is dropped.
+	subscriberBufferSize = 64
+)
+
+// Event represents an event published to the subscribers of a hub.
+type Event struct {
+	ID   uint64 // Sequential identifier of the event, sent to clients as the SSE event ID
+	Type string // Type of the event, sent to clients as the SSE event name
+	Data []byte // Payload of the event
+}
+
+// Hub fans out the published events to its subscribers and keeps the recent events,
+// so a reconnecting client can resume from the last event it received.
+type Hub struct {
+	mu          sync.Mutex
+	lastID      uint64                   // Identifier of the last published event
+	history     []*Event                 // Recent events in the order of publishing
+	historySize int                      // Max number of the recent events kept
+	subscribers map[chan *Event]struct{} // Channels of the current subscribers
+	closed      bool                     // Whether the hub is closed
+}
+
+// NewHub creates a new hub keeping up to historySize recent events.
+// The identifiers of the events start from the current time in nanoseconds, so they keep growing across restarts
+// and the identifiers from a previous run are recognized as unknown instead of being mixed up with the new ones.
+func NewHub(historySize int) *Hub {
+	return &Hub{
+		lastID:      uint64(time.Now().UnixNano()),
+		historySize: historySize,
+		subscribers: make(map[chan *Event]struct{}),
+	}
+}
+
+// Publish assigns an identifier to the event of the type and sends it to all subscribers.
+// A subscriber whose buffer is full is dropped; its client reconnects and resumes from its last event.
+func (h *Hub) Publish(eventType string, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.lastID++
+	event := &Event{ID: h.lastID, Type: eventType, Data: data}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			log.WithFields(log.Fields{
+				"eventID": event.ID,
+			}).Warn("Stream subscriber is lagging behind; dropping it")
+			delete(h.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber and returns its channel, the missed events to send before the ones from the channel
+// and a function to unsubscribe. The channel is closed when the hub is closed or the subscriber is dropped.
+// If the last event ID of the client isn't empty and the events after it aren't kept anymore (or it's unknown),
+// a single reset event is returned as the missed events.
+func (h *Hub) Subscribe(lastEventID string) (<-chan *Event, []*Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscriber := make(chan *Event, subscriberBufferSize)
+	if h.closed {
+		close(subscriber)
+		return subscriber, nil, func() {}
+	}
+	h.subscribers[subscriber] = struct{}{}
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subscribers[subscriber]; ok {
+			delete(h.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+
+	return subscriber, h.missedEvents(lastEventID), unsubscribe
+}
+
+// missedEvents returns the kept events published after the event with the identifier, or a reset event if they can't be replayed.
+// It must be called with the mutex locked.
+func (h *Hub) missedEvents(lastEventID string) []*Event {
+	if lastEventID == "" {
+		return nil
+	}
+
+	reset := []*Event{{ID: h.lastID, Type: ResetEventType, Data: []byte("{}")}}
+
+	lastID, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || lastID > h.lastID {
+		return reset
+	}
+
+	// The events right after the last one must still be kept; the first kept event may be the next one
+	oldestID := h.lastID + 1
+	if len(h.history) > 0 {
+		oldestID = h.history[0].ID
+	}
+	if lastID+1 < oldestID {
+		return reset
+	}
+
+	missed := []*Event{}
+	for _, event := range h.history {
+		if event.ID > lastID {
+			missed = append(missed, event)
+		}
+	}
+	return missed
+}
+
+// Close closes the channels of all subscribers, e.g. to let the streaming requests end on shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for subscriber := range h.subscribers {
+		delete(h.subscribers, subscriber)
+		close(subscriber)
+	}
+}
@@ -0,0 +1,55 @@
+package stream // dnywonnt.me/alerts2incidents/internal/api/v1/stream
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"dnywonnt.me/alerts2incidents/internal/database"
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RelayIncidents publishes the changes of the incidents received from the incidents notification channel to the hub
+// until the context is cancelled. The inserted and updated incidents are published
+// as their full JSON, the deleted ones as their ID; the event types are the lowercase actions, e.g. "update".
+func RelayIncidents(ctx context.Context, dbPool *pgxpool.Pool, repo *repositories.IncidentsRepository, hub *Hub) {
+	database.ListenToNotifications(ctx, dbPool, database.IncidentsChannel, func(notification *pgconn.Notification) {
+		action, incidentID, err := notifier.ParseEventPayload(notification.Payload)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"payload": notification.Payload,
+				"error":   err.Error(),
+			}).Error("Failed to parse the incidents notification payload")
+			return
+		}
+
+		var data []byte
+		if action == notifier.DeleteAction {
+			data, err = json.Marshal(map[string]string{"id": incidentID})
+		} else {
+			incident, getErr := repo.GetIncident(ctx, incidentID)
+			if getErr != nil {
+				log.WithFields(log.Fields{
+					"incidentID": incidentID,
+					"error":      getErr.Error(),
+				}).Error("Failed to retrieve the changed incident for the stream")
+				return
+			}
+			data, err = json.Marshal(incident)
+		}
+		if err != nil {
+			log.WithFields(log.Fields{
+				"incidentID": incidentID,
+				"error":      err.Error(),
+			}).Error("Failed to marshal the incident stream event")
+			return
+		}
+
+		hub.Publish(strings.ToLower(string(action)), data)
+	})
+}
@@ -2,6 +2,7 @@ package config // dnywonnt.me/alerts2incidents/internal/config
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"dnywonnt.me/alerts2incidents/internal/utils"
@@ -47,6 +48,7 @@ type ServiceConfig struct {
 	AlertsChanMaxSize     int                     `validate:"required,gte=1,lte=100"`  // Max size for alerts channel
 	IncidentsCacheMaxSize int                     `validate:"required,gte=1,lte=100"`  // Max size for incidents cache
 	RulesCacheMaxSize     int                     `validate:"required,gte=-1,lte=100"` // Max size for rules cache
+	RulesUnmuteInterval   time.Duration           `validate:"required,min=10s"`        // Interval of unmuting the rules whose mutes have expired
 	GrafanaCollector      *GrafanaCollectorConfig `validate:"required"`                // Configuration for Grafana collector
 	ZabbixCollector       *ZabbixCollectorConfig  `validate:"required"`                // Configuration for Zabbix collector
 	AlertsParser          *AlertsParserConfig     `validate:"required"`                // Configuration for alerts parser
@@ -80,12 +82,70 @@ type AlertsParserConfig struct {
 
 // TelegramBotConfig represents the configuration for the Telegram bot
 type TelegramBotConfig struct {
-	Token                   string        `validate:"required"`                                // Token for the Telegram bot
-	Chats                   []string      `validate:"required,min=1"`                          // List of chat IDs and optional thread IDs
-	MessageCacheMaxSize     int           `validate:"required,gte=1,lte=100"`                  // Max size for the message cache
-	MessageParseMode        string        `validate:"required,oneof=HTML Markdown MarkdownV2"` // Message parse mode
-	MessageTemplateFilepath string        `validate:"required,filepath"`                       // Filepath for the message template
-	RequestDelay            time.Duration `validate:"required,min=1s,max=30s"`                 // Delay between consecutive requests to avoid rate limits (1 to 30 seconds)
+	Token                   string        `validate:"required"`                                              // Token for the Telegram bot
+	Chats                   []string      `validate:"required,min=1"`                                        // List of chat IDs with optional thread IDs and locales
+	MessageCacheMaxSize     int           `validate:"required,gte=1,lte=100"`                                // Max size for the message cache
+	MessageParseMode        string        `validate:"required,oneof=HTML Markdown MarkdownV2"`               // Message parse mode
+	MessageTemplatesDirpath string        `validate:"required,dir"`                                          // Path to the directory with a subdirectory of message templates per locale
+	MessageDefaultLocale    string        `validate:"required"`                                              // Locale of the chats that don't declare one
+	RequestDelay            time.Duration `validate:"required,min=1s,max=30s"`                               // Delay between consecutive requests to avoid rate limits (1 to 30 seconds)
+	DigestIsActive          bool          `validate:"-"`                                                     // Flag to send the periodic incident digests
+	DigestSchedule          string        `validate:"required_if=DigestIsActive true"`                       // Cron expression of the digest schedule
+	DigestWindow            time.Duration `validate:"required_if=DigestIsActive true,omitempty,min=1m"`      // Time window covered by each digest
+	DigestChats             []string      `validate:"required_if=DigestIsActive true"`                       // List of chat IDs with optional thread IDs and locales for the digests
+	DigestTemplatesDirpath  string        `validate:"required_if=DigestIsActive true,omitempty,dir"`         // Path to the directory with a subdirectory of digest templates per locale
+	EscalationIsActive      bool          `validate:"-"`                                                     // Flag to escalate the unconfirmed auto incidents by the escalation policies
+	EscalationCheckInterval time.Duration `validate:"required_if=EscalationIsActive true,omitempty,min=10s"` // Interval between checks of the unconfirmed auto incidents
+	SLOIsActive             bool          `validate:"-"`                                                     // Flag to alert about the burned error budgets of the service-level objectives
+	SLOCheckInterval        time.Duration `validate:"required_if=SLOIsActive true,omitempty,min=1m"`         // Interval between checks of the error budgets
+	SLOBurnThreshold        float64       `validate:"required_if=SLOIsActive true,omitempty,gt=0,lte=1"`     // Fraction of the error budget burned within a period after which an alert is sent
+	SLOChats                []string      `validate:"required_if=SLOIsActive true"`                          // List of chat IDs with optional thread IDs and locales for the error budget alerts
+	SLOTemplatesDirpath     string        `validate:"required_if=SLOIsActive true,omitempty,dir"`            // Path to the directory with a subdirectory of error budget alert templates per locale
+}
+
+// NotifiersConfig represents the configuration for the additional incident notifiers
+type NotifiersConfig struct {
+	Email      *EmailNotifierConfig      `validate:"required"` // Configuration for the email notifier
+	Mattermost *MattermostNotifierConfig `validate:"required"` // Configuration for the Mattermost notifier
+	Teams      *TeamsNotifierConfig      `validate:"required"` // Configuration for the Microsoft Teams notifier
+}
+
+// EmailNotifierConfig represents the configuration for the email (SMTP) notifier
+type EmailNotifierConfig struct {
+	IsActive             bool          `validate:"-"`                                                   // Whether the email notifier is active
+	SMTPHost             string        `validate:"required_if=IsActive true,omitempty,hostname|ip"`     // Hostname or IP address of the SMTP server
+	SMTPPort             int           `validate:"required_if=IsActive true,omitempty,gte=1,lte=65535"` // Port of the SMTP server
+	SMTPUser             string        `validate:"omitempty"`                                           // User for the SMTP authentication (no authentication if empty)
+	SMTPPassword         string        `validate:"required_with=SMTPUser"`                              // Password for the SMTP authentication
+	From                 string        `validate:"required_if=IsActive true,omitempty,email"`           // Sender email address
+	Recipients           []string      `validate:"required_if=IsActive true,omitempty,min=1"`           // Recipients in the "departament:email" format ("*" matches any departament)
+	TextTemplateFilepath string        `validate:"required_if=IsActive true,omitempty,filepath"`        // Filepath for the plain-text message template
+	HTMLTemplateFilepath string        `validate:"required_if=IsActive true,omitempty,filepath"`        // Filepath for the HTML message template
+	TranslationsFilepath string        `validate:"omitempty,filepath"`                                  // Optional filepath for the translations of the enum values of the templates
+	StatusCacheMaxSize   int           `validate:"required_if=IsActive true,omitempty,gte=1,lte=100"`   // Max size for the cache of the last sent incident statuses
+	Timeout              time.Duration `validate:"required_if=IsActive true,omitempty,min=1s,max=1m"`   // Timeout for sending an email, from connecting to the SMTP server to QUIT
+}
+
+// MattermostNotifierConfig represents the configuration for the Mattermost incoming-webhook notifier
+type MattermostNotifierConfig struct {
+	IsActive             bool          `validate:"-"`                                                  // Whether the Mattermost notifier is active
+	Webhooks             []string      `validate:"required_if=IsActive true,omitempty,min=1"`          // Incoming webhook URLs in the "departament:url" format ("*" matches any departament)
+	Username             string        `validate:"omitempty"`                                          // Optional username overriding the one of the webhook
+	IconURL              string        `validate:"omitempty,url"`                                      // Optional icon URL overriding the one of the webhook
+	TemplateFilepath     string        `validate:"required_if=IsActive true,omitempty,filepath"`       // Filepath for the message template
+	TranslationsFilepath string        `validate:"omitempty,filepath"`                                 // Optional filepath for the translations of the enum values of the template
+	StatusCacheMaxSize   int           `validate:"required_if=IsActive true,omitempty,gte=1,lte=100"`  // Max size for the cache of the last sent incident statuses
+	RequestTimeout       time.Duration `validate:"required_if=IsActive true,omitempty,min=1s,max=30s"` // Timeout for the webhook requests
+}
+
+// TeamsNotifierConfig represents the configuration for the Microsoft Teams incoming-webhook notifier
+type TeamsNotifierConfig struct {
+	IsActive             bool          `validate:"-"`                                                  // Whether the Teams notifier is active
+	Webhooks             []string      `validate:"required_if=IsActive true,omitempty,min=1"`          // Incoming webhook URLs in the "departament:url" format ("*" matches any departament)
+	TemplateFilepath     string        `validate:"required_if=IsActive true,omitempty,filepath"`       // Filepath for the message template
+	TranslationsFilepath string        `validate:"omitempty,filepath"`                                 // Optional filepath for the translations of the enum values of the template
+	StatusCacheMaxSize   int           `validate:"required_if=IsActive true,omitempty,gte=1,lte=100"`  // Max size for the cache of the last sent incident statuses
+	RequestTimeout       time.Duration `validate:"required_if=IsActive true,omitempty,min=1s,max=30s"` // Timeout for the webhook requests
 }
 
 // LoadApiConfig loads the API configuration from environment variables
@@ -138,11 +198,15 @@ func LoadDatabaseConfig() (*DatabaseConfig, error) {
 func LoadServiceConfig() (*ServiceConfig, error) {
 	viper.SetEnvPrefix("SERVICE")
 
+	// Unmute the expired rules every minute unless configured
+	viper.SetDefault("RULES_UNMUTE_INTERVAL", time.Minute)
+
 	sc := &ServiceConfig{
 		DataChanMaxSize:       viper.GetInt("CHANNEL_DATA_MAX_SIZE"),
 		AlertsChanMaxSize:     viper.GetInt("CHANNEL_ALERTS_MAX_SIZE"),
 		IncidentsCacheMaxSize: viper.GetInt("CACHE_INCIDENTS_MAX_SIZE"),
 		RulesCacheMaxSize:     viper.GetInt("CACHE_RULES_MAX_SIZE"),
+		RulesUnmuteInterval:   viper.GetDuration("RULES_UNMUTE_INTERVAL"),
 		GrafanaCollector: &GrafanaCollectorConfig{
 			IsActive:                viper.GetBool("COLLECTOR_GRAFANA_IS_ACTIVE"),
 			APIUrl:                  viper.GetString("COLLECTOR_GRAFANA_API_URL"),
@@ -182,13 +246,30 @@ func LoadServiceConfig() (*ServiceConfig, error) {
 func LoadTelegramBotConfig() (*TelegramBotConfig, error) {
 	viper.SetEnvPrefix("TELEGRAM")
 
+	// Use the bundled incident templates in English unless configured
+	viper.SetDefault("MESSAGE_TEMPLATES_DIRPATH", "./templates/bk_incident_mdv2")
+	viper.SetDefault("MESSAGE_DEFAULT_LOCALE", "en")
+
 	tbc := &TelegramBotConfig{
 		Token:                   viper.GetString("BOT_TOKEN"),
 		Chats:                   viper.GetStringSlice("CHATS"),
 		MessageCacheMaxSize:     viper.GetInt("MESSAGE_CACHE_MAX_SIZE"),
 		MessageParseMode:        viper.GetString("MESSAGE_PARSE_MODE"),
-		MessageTemplateFilepath: viper.GetString("MESSAGE_TEMPLATE_FILEPATH"),
+		MessageTemplatesDirpath: viper.GetString("MESSAGE_TEMPLATES_DIRPATH"),
+		MessageDefaultLocale:    viper.GetString("MESSAGE_DEFAULT_LOCALE"),
 		RequestDelay:            viper.GetDuration("REQUEST_DELAY"),
+		DigestIsActive:          viper.GetBool("DIGEST_IS_ACTIVE"),
+		DigestSchedule:          viper.GetString("DIGEST_SCHEDULE"),
+		DigestWindow:            viper.GetDuration("DIGEST_WINDOW"),
+		DigestChats:             viper.GetStringSlice("DIGEST_CHATS"),
+		DigestTemplatesDirpath:  viper.GetString("DIGEST_TEMPLATES_DIRPATH"),
+		EscalationIsActive:      viper.GetBool("ESCALATION_IS_ACTIVE"),
+		EscalationCheckInterval: viper.GetDuration("ESCALATION_CHECK_INTERVAL"),
+		SLOIsActive:             viper.GetBool("SLO_IS_ACTIVE"),
+		SLOCheckInterval:        viper.GetDuration("SLO_CHECK_INTERVAL"),
+		SLOBurnThreshold:        viper.GetFloat64("SLO_BURN_THRESHOLD"),
+		SLOChats:                viper.GetStringSlice("SLO_CHATS"),
+		SLOTemplatesDirpath:     viper.GetString("SLO_TEMPLATES_DIRPATH"),
 	}
 
 	// Validate the configuration
@@ -196,5 +277,62 @@ func LoadTelegramBotConfig() (*TelegramBotConfig, error) {
 		return nil, err
 	}
 
+	// Ensure the digest schedule is a valid cron expression
+	if tbc.DigestIsActive {
+		if _, err := utils.ParseCronSchedule(tbc.DigestSchedule); err != nil {
+			return nil, fmt.Errorf("invalid digest schedule: %w", err)
+		}
+	}
+
 	return tbc, nil
 }
+
+// LoadNotifiersConfig loads the additional notifiers configuration from environment variables
+func LoadNotifiersConfig() (*NotifiersConfig, error) {
+	viper.SetEnvPrefix("NOTIFIER")
+
+	// Bound the SMTP sessions by 10 seconds unless configured
+	viper.SetDefault("EMAIL_TIMEOUT", 10*time.Second)
+
+	nc := &NotifiersConfig{
+		Email: &EmailNotifierConfig{
+			IsActive:             viper.GetBool("EMAIL_IS_ACTIVE"),
+			SMTPHost:             viper.GetString("EMAIL_SMTP_HOST"),
+			SMTPPort:             viper.GetInt("EMAIL_SMTP_PORT"),
+			SMTPUser:             viper.GetString("EMAIL_SMTP_USER"),
+			SMTPPassword:         viper.GetString("EMAIL_SMTP_PASSWORD"),
+			From:                 viper.GetString("EMAIL_FROM"),
+			Recipients:           viper.GetStringSlice("EMAIL_RECIPIENTS"),
+			TextTemplateFilepath: viper.GetString("EMAIL_TEXT_TEMPLATE_FILEPATH"),
+			HTMLTemplateFilepath: viper.GetString("EMAIL_HTML_TEMPLATE_FILEPATH"),
+			TranslationsFilepath: viper.GetString("EMAIL_TRANSLATIONS_FILEPATH"),
+			StatusCacheMaxSize:   viper.GetInt("EMAIL_STATUS_CACHE_MAX_SIZE"),
+			Timeout:              viper.GetDuration("EMAIL_TIMEOUT"),
+		},
+		Mattermost: &MattermostNotifierConfig{
+			IsActive:             viper.GetBool("MATTERMOST_IS_ACTIVE"),
+			Webhooks:             viper.GetStringSlice("MATTERMOST_WEBHOOKS"),
+			Username:             viper.GetString("MATTERMOST_USERNAME"),
+			IconURL:              viper.GetString("MATTERMOST_ICON_URL"),
+			TemplateFilepath:     viper.GetString("MATTERMOST_TEMPLATE_FILEPATH"),
+			TranslationsFilepath: viper.GetString("MATTERMOST_TRANSLATIONS_FILEPATH"),
+			StatusCacheMaxSize:   viper.GetInt("MATTERMOST_STATUS_CACHE_MAX_SIZE"),
+			RequestTimeout:       viper.GetDuration("MATTERMOST_REQUEST_TIMEOUT"),
+		},
+		Teams: &TeamsNotifierConfig{
+			IsActive:             viper.GetBool("TEAMS_IS_ACTIVE"),
+			Webhooks:             viper.GetStringSlice("TEAMS_WEBHOOKS"),
+			TemplateFilepath:     viper.GetString("TEAMS_TEMPLATE_FILEPATH"),
+			TranslationsFilepath: viper.GetString("TEAMS_TRANSLATIONS_FILEPATH"),
+			StatusCacheMaxSize:   viper.GetInt("TEAMS_STATUS_CACHE_MAX_SIZE"),
+			RequestTimeout:       viper.GetDuration("TEAMS_REQUEST_TIMEOUT"),
+		},
+	}
+
+	// Validate the configuration
+	if err := utils.ValidateStruct(nc); err != nil {
+		return nil, err
+	}
+
+	return nc, nil
+}
@@ -17,8 +17,9 @@ type ListenChannel string
 
 // Define constants for channel names to listen to for notifications.
 const (
-	IncidentsChannel ListenChannel = "a2i_incidents_channel" // Channel for incident notifications.
-	RulesChannel     ListenChannel = "a2i_rules_channel"     // Channel for rule notifications.
+	IncidentsChannel          ListenChannel = "a2i_incidents_channel"           // Channel for incident notifications.
+	RulesChannel              ListenChannel = "a2i_rules_channel"               // Channel for rule notifications.
+	MaintenanceWindowsChannel ListenChannel = "a2i_maintenance_windows_channel" // Channel for maintenance window notifications.
 )
 
 // ListenToNotifications listens for notifications on a PostgreSQL channel and triggers a handler function when a notification is received.
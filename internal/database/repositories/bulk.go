@@ -0,0 +1,239 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for bulk operations
+const (
+	// Query for selecting and locking rules by IDs for the rest of the transaction
+	selectRulesForUpdateQuery = `
+		SELECT ` + ruleColumns + `
+		FROM a2i_rules
+		WHERE id = ANY($1)
+		FOR UPDATE
+	`
+)
+
+// ApplyBulkActionToIncidents applies the action to the incidents with the given IDs or, if no IDs are given,
+// to the incidents matching the filter and the time range, in a single transaction.
+// The incidents the action can't be applied to are reported as failed without affecting the others,
+// and the results are in the order of the IDs or of the creation of the incidents.
+func (ir *IncidentsRepository) ApplyBulkActionToIncidents(ctx context.Context, ids []string, filterBy map[string]interface{},
+	startTime, endTime time.Time, action *models.BulkAction) ([]*models.BulkItemResult, error) {
+	log.WithFields(log.Fields{
+		"action":   action.Name,
+		"ids":      ids,
+		"filterBy": filterBy,
+	}).Debug("Applying a bulk action to incidents in the database")
+
+	tx, err := ir.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting the transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Select and lock the incidents, keeping the requested order and reporting the unknown IDs.
+	incidents := []*models.Incident{}
+	results := []*models.BulkItemResult{}
+	if len(ids) > 0 {
+		incidentsByID, err := selectIncidentsForUpdate(ctx, tx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range uniqueIDs(ids) {
+			if incident, ok := incidentsByID[id]; ok {
+				incidents = append(incidents, incident)
+			} else {
+				results = append(results, &models.BulkItemResult{ID: id, Status: models.NotFoundBulkItemStatus})
+			}
+		}
+	} else {
+		query, args := buildGetQueryForIncidents(filterBy, "created_at", "asc", 0, 0, startTime, endTime)
+		rows, err := tx.Query(ctx, query+" FOR UPDATE", args...)
+		if err != nil {
+			return nil, fmt.Errorf("error executing the query: %w", err)
+		}
+		incidents, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Incident, error) {
+			incident := &models.Incident{}
+			return incident, row.Scan(incidentScanDest(incident)...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error scanning the rows: %w", err)
+		}
+	}
+
+	for _, incident := range incidents {
+		result := &models.BulkItemResult{ID: incident.ID}
+		results = append(results, result)
+
+		if action.Name == models.DeleteBulkAction {
+			applyBulkChange(ctx, tx, result, models.DeletedBulkItemStatus, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, deleteIncidentQuery, incident.ID)
+				return err
+			})
+			continue
+		}
+
+		changed, err := incident.ApplyBulkAction(action)
+		if err != nil {
+			result.Status, result.Message = models.FailedBulkItemStatus, err.Error()
+			continue
+		}
+		if !changed {
+			result.Status, result.Version = models.SkippedBulkItemStatus, incident.Version
+			continue
+		}
+		applyBulkChange(ctx, tx, result, models.UpdatedBulkItemStatus, func(tx pgx.Tx) error {
+			return tx.QueryRow(ctx, updateIncidentQuery, incidentUpdateArgs(incident)...).Scan(&result.Version, &incident.ClosedAt)
+		})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing the transaction: %w", err)
+	}
+	sortBulkResults(results, ids)
+
+	log.WithFields(log.Fields{
+		"action":         action.Name,
+		"incidentsCount": len(incidents),
+	}).Debug("The bulk action has been applied to the incidents in the database")
+
+	return results, nil
+}
+
+// ApplyBulkActionToRules applies the action to the rules with the given IDs or, if no IDs are given,
+// to the rules matching the filter and the time range, in a single transaction.
+// The rules the action can't be applied to are reported as failed without affecting the others,
+// and the results are in the order of the IDs or of the creation of the rules.
+func (rr *RulesRepository) ApplyBulkActionToRules(ctx context.Context, ids []string, filterBy map[string]interface{},
+	startTime, endTime time.Time, action *models.BulkAction) ([]*models.BulkItemResult, error) {
+	log.WithFields(log.Fields{
+		"action":   action.Name,
+		"ids":      ids,
+		"filterBy": filterBy,
+	}).Debug("Applying a bulk action to rules in the database")
+
+	tx, err := rr.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting the transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query, args := selectRulesForUpdateQuery, []interface{}{ids}
+	if len(ids) == 0 {
+		query, args = buildGetQueryForRules(filterBy, "created_at", "asc", 0, 0, startTime, endTime)
+		query += " FOR UPDATE"
+	}
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	rules, err := collectRules(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the requested order and report the unknown IDs.
+	results := []*models.BulkItemResult{}
+	if len(ids) > 0 {
+		rulesByID := make(map[string]*models.Rule, len(rules))
+		for _, rule := range rules {
+			rulesByID[rule.ID] = rule
+		}
+		rules = rules[:0]
+		for _, id := range uniqueIDs(ids) {
+			if rule, ok := rulesByID[id]; ok {
+				rules = append(rules, rule)
+			} else {
+				results = append(results, &models.BulkItemResult{ID: id, Status: models.NotFoundBulkItemStatus})
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		result := &models.BulkItemResult{ID: rule.ID}
+		results = append(results, result)
+
+		if action.Name == models.DeleteBulkAction {
+			applyBulkChange(ctx, tx, result, models.DeletedBulkItemStatus, func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, deleteRuleQuery, rule.ID)
+				return err
+			})
+			continue
+		}
+
+		changed, err := rule.ApplyBulkAction(action)
+		if err != nil {
+			result.Status, result.Message = models.FailedBulkItemStatus, err.Error()
+			continue
+		}
+		if !changed {
+			result.Status, result.Version = models.SkippedBulkItemStatus, rule.Version
+			continue
+		}
+		applyBulkChange(ctx, tx, result, models.UpdatedBulkItemStatus, func(tx pgx.Tx) error {
+			return tx.QueryRow(ctx, updateRuleQuery, ruleUpdateArgs(rule)...).Scan(&result.Version)
+		})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing the transaction: %w", err)
+	}
+	sortBulkResults(results, ids)
+
+	log.WithFields(log.Fields{
+		"action":     action.Name,
+		"rulesCount": len(rules),
+	}).Debug("The bulk action has been applied to the rules in the database")
+
+	return results, nil
+}
+
+// applyBulkChange applies the change of a single record of a bulk operation within a savepoint of the transaction,
+// so a failed change, e.g. deleting a rule still referenced by incidents, is rolled back alone and reported in the result.
+func applyBulkChange(ctx context.Context, tx pgx.Tx, result *models.BulkItemResult, status string, change func(pgx.Tx) error) {
+	err := pgx.BeginFunc(ctx, tx, change)
+	if err == nil {
+		result.Status = status
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"id":    result.ID,
+		"error": err.Error(),
+	}).Debug("Failed to apply the bulk change to the record")
+	result.Status, result.Message, result.Version = models.FailedBulkItemStatus, err.Error(), 0
+}
+
+// sortBulkResults sorts the results of a bulk operation in the order of the requested IDs, if any.
+func sortBulkResults(results []*models.BulkItemResult, ids []string) {
+	positions := make(map[string]int, len(ids))
+	for i, id := range uniqueIDs(ids) {
+		positions[id] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return positions[results[i].ID] < positions[results[j].ID]
+	})
+}
+
+// uniqueIDs returns the IDs without duplicates, keeping the order of their first occurrences.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,35 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// invalidTextRepresentationCode is the SQLSTATE of a value that can't be parsed as the type of its column, e.g. a malformed UUID.
+const invalidTextRepresentationCode = "22P02"
+
+// foreignKeyViolationCode is the SQLSTATE of a reference to a record that doesn't exist.
+const foreignKeyViolationCode = "23503"
+
+// ErrNotFound is returned when the requested record doesn't exist. It wraps pgx.ErrNoRows,
+// so the callers checking for the missing rows keep working.
+var ErrNotFound = fmt.Errorf("record not found: %w", pgx.ErrNoRows)
+
+// ErrAlreadyExists is returned when the record to create duplicates an existing one.
+var ErrAlreadyExists = errors.New("the record already exists")
+
+// ErrVersionConflict is returned when an incident or a rule has been modified or deleted since the version the update is based on.
+var ErrVersionConflict = errors.New("version conflict: the record has been modified or deleted concurrently")
+
+// selectRecordError converts an error of selecting a single record by its ID or name: no rows, as well as an ID that can't be
+// a valid one, are reported as ErrNotFound, and the other errors, e.g. a lost connection, are wrapped as they are.
+func selectRecordError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == invalidTextRepresentationCode) {
+		return ErrNotFound
+	}
+	return fmt.Errorf("error executing the query: %w", err)
+}
@@ -0,0 +1,169 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for CRUD operations on escalation policies
+const (
+	// Query for inserting a new escalation policy into the database
+	insertEscalationPolicyQuery = `
+		INSERT INTO a2i_escalation_policies (id, description, rule_id, departament, steps, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	// Query for selecting an escalation policy by ID
+	selectEscalationPolicyQuery = `
+		SELECT id, description, rule_id, departament, steps, created_at, updated_at
+		FROM a2i_escalation_policies
+		WHERE id = $1
+	`
+
+	// Query for selecting all escalation policies
+	selectEscalationPoliciesQuery = `
+		SELECT id, description, rule_id, departament, steps, created_at, updated_at
+		FROM a2i_escalation_policies
+		ORDER BY created_at
+	`
+
+	// Query for updating an existing escalation policy
+	updateEscalationPolicyQuery = `
+		UPDATE a2i_escalation_policies
+		SET description = $1, rule_id = $2, departament = $3, steps = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	// Query for deleting an escalation policy by ID
+	deleteEscalationPolicyQuery = `
+		DELETE FROM a2i_escalation_policies
+		WHERE id = $1
+	`
+)
+
+// EscalationPoliciesRepository struct defines the structure for the repository
+type EscalationPoliciesRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for EscalationPoliciesRepository
+func NewEscalationPoliciesRepository(dbPool *pgxpool.Pool) *EscalationPoliciesRepository {
+	log.Debug("Initializing the escalation policies repository")
+	return &EscalationPoliciesRepository{dbPool: dbPool}
+}
+
+// CreateEscalationPolicy inserts a new escalation policy into the database
+func (epr *EscalationPoliciesRepository) CreateEscalationPolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	log.WithFields(log.Fields{
+		"id": policy.ID,
+	}).Debug("Creating a new escalation policy in the database")
+
+	if _, err := epr.dbPool.Exec(
+		ctx,
+		insertEscalationPolicyQuery,
+		policy.ID, policy.Description, policy.RuleID, policy.Departament, policy.Steps, policy.CreatedAt, policy.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": policy.ID,
+	}).Debug("The escalation policy has been created in the database")
+
+	return nil
+}
+
+// GetEscalationPolicy retrieves an escalation policy by ID from the database, or ErrNotFound if there is no such policy
+func (epr *EscalationPoliciesRepository) GetEscalationPolicy(ctx context.Context, id string) (*models.EscalationPolicy, error) {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Retrieving an escalation policy from the database")
+
+	policy := &models.EscalationPolicy{}
+	if err := epr.dbPool.QueryRow(ctx, selectEscalationPolicyQuery, id).Scan(
+		&policy.ID, &policy.Description, &policy.RuleID, &policy.Departament, &policy.Steps, &policy.CreatedAt, &policy.UpdatedAt,
+	); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Escalation policy successfully retrieved from the database")
+
+	return policy, nil
+}
+
+// GetEscalationPolicies retrieves all escalation policies from the database
+func (epr *EscalationPoliciesRepository) GetEscalationPolicies(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	log.Debug("Retrieving escalation policies from the database")
+
+	rows, err := epr.dbPool.Query(ctx, selectEscalationPoliciesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []*models.EscalationPolicy{}
+	for rows.Next() {
+		policy := &models.EscalationPolicy{}
+		if err := rows.Scan(
+			&policy.ID, &policy.Description, &policy.RuleID, &policy.Departament, &policy.Steps, &policy.CreatedAt, &policy.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"policiesCount": len(policies),
+	}).Debug("Escalation policies successfully retrieved from the database")
+
+	return policies, nil
+}
+
+// UpdateEscalationPolicy updates an existing escalation policy in the database
+func (epr *EscalationPoliciesRepository) UpdateEscalationPolicy(ctx context.Context, policy *models.EscalationPolicy) error {
+	log.WithFields(log.Fields{
+		"id": policy.ID,
+	}).Debug("Updating an escalation policy in the database")
+
+	if _, err := epr.dbPool.Exec(
+		ctx,
+		updateEscalationPolicyQuery,
+		policy.Description, policy.RuleID, policy.Departament, policy.Steps, policy.UpdatedAt, policy.ID,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": policy.ID,
+	}).Debug("The escalation policy has been updated in the database")
+
+	return nil
+}
+
+// DeleteEscalationPolicy deletes an escalation policy by ID from the database
+func (epr *EscalationPoliciesRepository) DeleteEscalationPolicy(ctx context.Context, id string) error {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Deleting an escalation policy from the database")
+
+	if _, err := epr.dbPool.Exec(ctx, deleteEscalationPolicyQuery, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The escalation policy has been deleted from the database")
+
+	return nil
+}
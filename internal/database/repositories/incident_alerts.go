@@ -0,0 +1,152 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for operations on incident alerts
+const (
+	// Query for recording an alert of a match of an incident; an alert is counted in one record per incident,
+	// and a matching alert is firing again even if it has been resolved before
+	upsertIncidentAlertQuery = `
+		INSERT INTO a2i_incident_alerts (
+		    incident_id, fingerprint, summary, created_at, matching_count, first_seen_at, last_seen_at
+		) VALUES ($1, $2, $3, $4, 1, $5, $5)
+		ON CONFLICT (incident_id, fingerprint) DO UPDATE
+		SET summary = EXCLUDED.summary,
+		    created_at = EXCLUDED.created_at,
+		    matching_count = a2i_incident_alerts.matching_count + 1,
+		    last_seen_at = EXCLUDED.last_seen_at,
+		    resolved_at = NULL
+	`
+
+	// Query for marking the firing alerts of the incidents with the given fingerprints as resolved
+	updateIncidentAlertsResolvedQuery = `
+		UPDATE a2i_incident_alerts
+		SET resolved_at = $2
+		WHERE fingerprint = ANY($1) AND resolved_at IS NULL
+	`
+
+	// Query for checking whether an incident exists
+	selectIncidentExistsQuery = `
+		SELECT EXISTS (SELECT 1 FROM a2i_incidents WHERE id = $1)
+	`
+
+	// Query for selecting the alerts of an incident in the order they were first seen
+	selectIncidentAlertsQuery = `
+		SELECT incident_id, fingerprint, summary, created_at, matching_count, first_seen_at, last_seen_at, resolved_at
+		FROM a2i_incident_alerts
+		WHERE incident_id = $1
+		ORDER BY first_seen_at, fingerprint
+	`
+)
+
+// IncidentAlertsRepository struct defines the structure for the repository
+type IncidentAlertsRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for IncidentAlertsRepository
+func NewIncidentAlertsRepository(dbPool *pgxpool.Pool) *IncidentAlertsRepository {
+	log.Debug("Initializing the incident alerts repository")
+	return &IncidentAlertsRepository{dbPool: dbPool}
+}
+
+// RecordIncidentAlerts records the alerts of a match of an incident seen at the given time in one batch
+func (iar *IncidentAlertsRepository) RecordIncidentAlerts(ctx context.Context, incidentID string, alerts []models.Alert, seenAt time.Time) error {
+	log.WithFields(log.Fields{
+		"incidentID":  incidentID,
+		"alertsCount": len(alerts),
+	}).Debug("Recording incident alerts in the database")
+
+	// The aggregated alerts may hold the same alert from several polls; it's counted once per match
+	batch := &pgx.Batch{}
+	recorded := make(map[string]struct{})
+	for _, alert := range alerts {
+		if _, ok := recorded[alert.Identity()]; ok {
+			continue
+		}
+		recorded[alert.Identity()] = struct{}{}
+		batch.Queue(upsertIncidentAlertQuery, incidentID, alert.Identity(), alert.Summary, alert.CreatedAt, seenAt)
+	}
+	if err := iar.dbPool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("error executing the batch: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"incidentID": incidentID,
+	}).Debug("Incident alerts have been recorded in the database")
+
+	return nil
+}
+
+// ResolveIncidentAlerts marks the firing alerts with the given fingerprints as resolved at the given time in all incidents.
+// The sources report a resolved alert once, so it's kept until the alert matches again.
+func (iar *IncidentAlertsRepository) ResolveIncidentAlerts(ctx context.Context, fingerprints []string, resolvedAt time.Time) error {
+	log.WithFields(log.Fields{
+		"alertsCount": len(fingerprints),
+	}).Debug("Resolving incident alerts in the database")
+
+	if _, err := iar.dbPool.Exec(ctx, updateIncidentAlertsResolvedQuery, fingerprints, resolvedAt); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"alertsCount": len(fingerprints),
+	}).Debug("Incident alerts have been resolved in the database")
+
+	return nil
+}
+
+// GetIncidentAlerts retrieves the alerts that have contributed to an incident in the order they were first seen;
+// it returns ErrNotFound if there is no such incident.
+func (iar *IncidentAlertsRepository) GetIncidentAlerts(ctx context.Context, incidentID string) ([]*models.IncidentAlert, error) {
+	log.WithFields(log.Fields{
+		"incidentID": incidentID,
+	}).Debug("Retrieving incident alerts from the database")
+
+	exists := false
+	if err := iar.dbPool.QueryRow(ctx, selectIncidentExistsQuery, incidentID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := iar.dbPool.Query(ctx, selectIncidentAlertsQuery, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []*models.IncidentAlert{}
+	for rows.Next() {
+		alert := &models.IncidentAlert{}
+		if err := rows.Scan(
+			&alert.IncidentID, &alert.Fingerprint, &alert.Summary, &alert.CreatedAt,
+			&alert.MatchingCount, &alert.FirstSeenAt, &alert.LastSeenAt, &alert.ResolvedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"incidentID":  incidentID,
+		"alertsCount": len(alerts),
+	}).Debug("Incident alerts successfully retrieved from the database")
+
+	return alerts, nil
+}
@@ -0,0 +1,119 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for operations on incident events
+const (
+	// Query for inserting a new incident event into the database
+	insertIncidentEventQuery = `
+		INSERT INTO a2i_incident_events (id, incident_id, type, data, creator, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	// Query for selecting the events of an incident in chronological order
+	selectIncidentEventsQuery = `
+		SELECT id, incident_id, type, data, creator, created_at
+		FROM a2i_incident_events
+		WHERE incident_id = $1
+		ORDER BY created_at
+	`
+
+	// Query for counting the events of an incident by type
+	countIncidentEventsQuery = `
+		SELECT COUNT(id)
+		FROM a2i_incident_events
+		WHERE incident_id = $1 AND type = $2
+	`
+)
+
+// IncidentEventsRepository struct defines the structure for the repository
+type IncidentEventsRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for IncidentEventsRepository
+func NewIncidentEventsRepository(dbPool *pgxpool.Pool) *IncidentEventsRepository {
+	log.Debug("Initializing the incident events repository")
+	return &IncidentEventsRepository{dbPool: dbPool}
+}
+
+// CreateIncidentEvent inserts a new incident event into the database
+func (ier *IncidentEventsRepository) CreateIncidentEvent(ctx context.Context, event *models.IncidentEvent) error {
+	log.WithFields(log.Fields{
+		"id":         event.ID,
+		"incidentID": event.IncidentID,
+		"type":       event.Type,
+	}).Debug("Creating a new incident event in the database")
+
+	if _, err := ier.dbPool.Exec(
+		ctx,
+		insertIncidentEventQuery,
+		event.ID, event.IncidentID, event.Type, event.Data, event.Creator, event.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": event.ID,
+	}).Debug("The incident event has been created in the database")
+
+	return nil
+}
+
+// GetIncidentEvents retrieves the events of an incident in chronological order
+func (ier *IncidentEventsRepository) GetIncidentEvents(ctx context.Context, incidentID string) ([]*models.IncidentEvent, error) {
+	log.WithFields(log.Fields{
+		"incidentID": incidentID,
+	}).Debug("Retrieving incident events from the database")
+
+	rows, err := ier.dbPool.Query(ctx, selectIncidentEventsQuery, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.IncidentEvent{}
+	for rows.Next() {
+		event := &models.IncidentEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.IncidentID, &event.Type, &event.Data, &event.Creator, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"incidentID":  incidentID,
+		"eventsCount": len(events),
+	}).Debug("Incident events successfully retrieved from the database")
+
+	return events, nil
+}
+
+// CountIncidentEvents counts the events of the given type of an incident
+func (ier *IncidentEventsRepository) CountIncidentEvents(ctx context.Context, incidentID, eventType string) (int, error) {
+	log.WithFields(log.Fields{
+		"incidentID": incidentID,
+		"type":       eventType,
+	}).Debug("Counting incident events in the database")
+
+	count := 0
+	if err := ier.dbPool.QueryRow(ctx, countIncidentEventsQuery, incidentID, eventType).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error executing the query: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,151 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for operations on incident links
+const (
+	// Query for inserting a new incident link; an existing link of the same type between the incidents is kept,
+	// and no row is returned then
+	insertIncidentLinkQuery = `
+		INSERT INTO a2i_incident_links (id, incident_id, linked_incident_id, type, creator, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (incident_id, linked_incident_id, type) DO NOTHING
+		RETURNING id
+	`
+
+	// Query for selecting the links from and to an incident in chronological order
+	selectIncidentLinksQuery = `
+		SELECT id, incident_id, linked_incident_id, type, creator, created_at
+		FROM a2i_incident_links
+		WHERE incident_id = $1 OR linked_incident_id = $1
+		ORDER BY created_at
+	`
+
+	// Query for deleting a link from or to an incident by ID
+	deleteIncidentLinkQuery = `
+		DELETE FROM a2i_incident_links
+		WHERE id = $1 AND (incident_id = $2 OR linked_incident_id = $2)
+	`
+)
+
+// IncidentLinksRepository struct defines the structure for the repository
+type IncidentLinksRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for IncidentLinksRepository
+func NewIncidentLinksRepository(dbPool *pgxpool.Pool) *IncidentLinksRepository {
+	log.Debug("Initializing the incident links repository")
+	return &IncidentLinksRepository{dbPool: dbPool}
+}
+
+// CreateIncidentLink inserts a new incident link into the database. It returns ErrNotFound if any of the incidents doesn't exist
+// and ErrAlreadyExists if the incidents are already linked with the same type.
+func (ilr *IncidentLinksRepository) CreateIncidentLink(ctx context.Context, link *models.IncidentLink) error {
+	log.WithFields(log.Fields{
+		"id":               link.ID,
+		"incidentID":       link.IncidentID,
+		"linkedIncidentID": link.LinkedIncidentID,
+		"type":             link.Type,
+	}).Debug("Creating a new incident link in the database")
+
+	var id string
+	if err := ilr.dbPool.QueryRow(
+		ctx,
+		insertIncidentLinkQuery,
+		link.ID, link.IncidentID, link.LinkedIncidentID, link.Type, link.Creator, link.CreatedAt,
+	).Scan(&id); err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("%w: incident %s is already linked to %s as %s", ErrAlreadyExists, link.IncidentID, link.LinkedIncidentID, link.Type)
+		case errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode:
+			return fmt.Errorf("%w: incident %s or %s", ErrNotFound, link.IncidentID, link.LinkedIncidentID)
+		}
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": link.ID,
+	}).Debug("The incident link has been created in the database")
+
+	return nil
+}
+
+// GetIncidentLinks retrieves the links from and to an incident in chronological order;
+// it returns ErrNotFound if there is no such incident.
+func (ilr *IncidentLinksRepository) GetIncidentLinks(ctx context.Context, incidentID string) ([]*models.IncidentLink, error) {
+	log.WithFields(log.Fields{
+		"incidentID": incidentID,
+	}).Debug("Retrieving incident links from the database")
+
+	exists := false
+	if err := ilr.dbPool.QueryRow(ctx, selectIncidentExistsQuery, incidentID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	rows, err := ilr.dbPool.Query(ctx, selectIncidentLinksQuery, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*models.IncidentLink{}
+	for rows.Next() {
+		link := &models.IncidentLink{}
+		if err := rows.Scan(
+			&link.ID, &link.IncidentID, &link.LinkedIncidentID, &link.Type, &link.Creator, &link.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"incidentID": incidentID,
+		"linksCount": len(links),
+	}).Debug("Incident links successfully retrieved from the database")
+
+	return links, nil
+}
+
+// DeleteIncidentLink deletes a link from or to an incident by ID from the database;
+// it returns ErrNotFound if the incident has no such link.
+func (ilr *IncidentLinksRepository) DeleteIncidentLink(ctx context.Context, incidentID, id string) error {
+	log.WithFields(log.Fields{
+		"id":         id,
+		"incidentID": incidentID,
+	}).Debug("Deleting an incident link from the database")
+
+	result, err := ilr.dbPool.Exec(ctx, deleteIncidentLinkQuery, id, incidentID)
+	if err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The incident link has been deleted from the database")
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"dnywonnt.me/alerts2incidents/internal/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrInvalidIncidentOperation is returned when incidents can't be merged or split as requested.
+var ErrInvalidIncidentOperation = errors.New("invalid incident operation")
+
+// SQL queries as constants for merging and splitting incidents
+const (
+	// Query for selecting and locking incidents by IDs for the rest of the transaction
+	selectIncidentsForUpdateQuery = `
+		SELECT ` + incidentColumns + `
+		FROM a2i_incidents
+		WHERE id = ANY($1)
+		FOR UPDATE
+	`
+
+	// Query for updating the primary incident with the data of the merged duplicates
+	updateMergedPrimaryIncidentQuery = `
+		UPDATE a2i_incidents
+		SET from_at = $1, matching_count = $2, last_matching_time = $3, alerts_data = $4, updated_at = $5,
+		    version = version + 1
+		WHERE id = $6
+	`
+
+	// Query for closing a merged duplicate with the reference to the primary incident
+	updateMergedDuplicateIncidentQuery = `
+		UPDATE a2i_incidents
+		SET status = 'closed', to_at = $1, merged_into_id = $2, updated_at = $3, version = version + 1,
+		    closed_at = CASE WHEN status = 'closed' THEN closed_at ELSE $3 END
+		WHERE id = $4
+	`
+
+	// Query for moving the contributing alerts of the duplicates to the primary incident, combining the records of the same alert
+	mergeIncidentAlertsQuery = `
+		INSERT INTO a2i_incident_alerts (
+		    incident_id, fingerprint, summary, created_at, matching_count, first_seen_at, last_seen_at
+		)
+		SELECT $1, fingerprint, (ARRAY_AGG(summary ORDER BY last_seen_at DESC))[1], MAX(created_at),
+		    SUM(matching_count), MIN(first_seen_at), MAX(last_seen_at)
+		FROM a2i_incident_alerts
+		WHERE incident_id = ANY($2)
+		GROUP BY fingerprint
+		ON CONFLICT (incident_id, fingerprint) DO UPDATE
+		SET summary = CASE WHEN EXCLUDED.last_seen_at > a2i_incident_alerts.last_seen_at
+		        THEN EXCLUDED.summary ELSE a2i_incident_alerts.summary END,
+		    created_at = GREATEST(a2i_incident_alerts.created_at, EXCLUDED.created_at),
+		    matching_count = a2i_incident_alerts.matching_count + EXCLUDED.matching_count,
+		    first_seen_at = LEAST(a2i_incident_alerts.first_seen_at, EXCLUDED.first_seen_at),
+		    last_seen_at = GREATEST(a2i_incident_alerts.last_seen_at, EXCLUDED.last_seen_at)
+	`
+
+	// Query for deleting the contributing alerts of incidents
+	deleteIncidentAlertsQuery = `
+		DELETE FROM a2i_incident_alerts
+		WHERE incident_id = ANY($1)
+	`
+
+	// Query for moving the contributing alerts with the given fingerprints to the incident split from the original one
+	splitIncidentAlertsQuery = `
+		UPDATE a2i_incident_alerts
+		SET incident_id = $1
+		WHERE incident_id = $2 AND fingerprint = ANY($3)
+	`
+
+	// Query for updating the alerts of the incident some alerts have been split from
+	updateSplitIncidentQuery = `
+		UPDATE a2i_incidents
+		SET alerts_data = $1, updated_at = $2, version = version + 1
+		WHERE id = $3
+	`
+)
+
+// MergeIncidents merges the duplicate incidents into the primary one in a single transaction.
+// The alerts and the matching count of the duplicates are moved into the primary incident,
+// and the duplicates are closed with a "duplicate_of" link and a reference to the primary incident.
+// It returns the updated primary incident, or ErrNotFound if any of the incidents doesn't exist.
+func (ir *IncidentsRepository) MergeIncidents(ctx context.Context, primaryID string, duplicateIDs []string, creator string) (*models.Incident, error) {
+	log.WithFields(log.Fields{
+		"primaryID":    primaryID,
+		"duplicateIDs": duplicateIDs,
+	}).Debug("Merging incidents in the database")
+
+	if len(duplicateIDs) == 0 {
+		return nil, fmt.Errorf("%w: no incidents to merge", ErrInvalidIncidentOperation)
+	}
+	ids := []string{primaryID}
+	seen := map[string]struct{}{primaryID: {}}
+	for _, id := range duplicateIDs {
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("%w: incident %s is listed twice or is the primary incident", ErrInvalidIncidentOperation, id)
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	tx, err := ir.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting the transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	incidents, err := selectIncidentsForUpdate(ctx, tx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		incident, ok := incidents[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: incident %s", ErrNotFound, id)
+		}
+		if incident.MergedIntoID != nil {
+			return nil, fmt.Errorf("%w: incident %s has already been merged into %s", ErrInvalidIncidentOperation, id, *incident.MergedIntoID)
+		}
+	}
+
+	currentTimeUTC := time.Now().UTC()
+	primary := incidents[primaryID]
+	alerts := incidentAlerts(primary)
+
+	// Move the data of the duplicates into the primary incident and close them
+	for _, id := range duplicateIDs {
+		duplicate := incidents[id]
+
+		if duplicate.FromAt.Before(primary.FromAt) {
+			primary.FromAt = duplicate.FromAt
+		}
+		if duplicate.LastMatchingTime.After(primary.LastMatchingTime) {
+			primary.LastMatchingTime = duplicate.LastMatchingTime
+		}
+		primary.MatchingCount += duplicate.MatchingCount
+		alerts = append(alerts, incidentAlerts(duplicate)...)
+
+		toAt := duplicate.ToAt
+		if toAt.IsZero() {
+			toAt = currentTimeUTC
+		}
+		if _, err := tx.Exec(ctx, updateMergedDuplicateIncidentQuery, toAt, primaryID, currentTimeUTC, id); err != nil {
+			return nil, fmt.Errorf("error executing the query: %w", err)
+		}
+		if err := insertIncidentLinkAndEvent(ctx, tx, id, primaryID, models.DuplicateOfLinkType, models.MergeEventType,
+			map[string]interface{}{"merged_into_id": primaryID}, creator, currentTimeUTC); err != nil {
+			return nil, err
+		}
+	}
+
+	alertsData, err := json.Marshal(uniqueAlerts(alerts))
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling the alerts: %w", err)
+	}
+	if _, err := tx.Exec(
+		ctx,
+		updateMergedPrimaryIncidentQuery,
+		primary.FromAt, primary.MatchingCount, primary.LastMatchingTime, string(alertsData), currentTimeUTC, primaryID,
+	); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	if _, err := tx.Exec(ctx, mergeIncidentAlertsQuery, primaryID, duplicateIDs); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	if _, err := tx.Exec(ctx, deleteIncidentAlertsQuery, duplicateIDs); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	if err := insertIncidentEvent(ctx, tx, primaryID, models.MergeEventType,
+		map[string]interface{}{"merged_incident_ids": duplicateIDs}, creator, currentTimeUTC); err != nil {
+		return nil, err
+	}
+
+	// Read the primary incident back as it has been stored
+	updatedPrimary := &models.Incident{}
+	if err := tx.QueryRow(ctx, selectIncidentQuery, primaryID).Scan(incidentScanDest(updatedPrimary)...); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing the transaction: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"primaryID":    primaryID,
+		"duplicateIDs": duplicateIDs,
+	}).Debug("The incidents have been merged in the database")
+
+	return updatedPrimary, nil
+}
+
+// SplitIncident moves the alerts with the given fingerprints of an incident into a new manual incident
+// in a single transaction. The new incident copies the details of the original one, takes the given summary
+// (or the original one if it's empty) and is linked to the original incident with a "split_from" link.
+// It returns the new incident, or ErrNotFound if the original incident doesn't exist.
+func (ir *IncidentsRepository) SplitIncident(ctx context.Context, id string, fingerprints []string, summary, creator string) (*models.Incident, error) {
+	log.WithFields(log.Fields{
+		"id":           id,
+		"fingerprints": fingerprints,
+	}).Debug("Splitting an incident in the database")
+
+	if len(fingerprints) == 0 {
+		return nil, fmt.Errorf("%w: no alerts to split", ErrInvalidIncidentOperation)
+	}
+
+	tx, err := ir.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting the transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	incidents, err := selectIncidentsForUpdate(ctx, tx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	original, ok := incidents[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: incident %s", ErrNotFound, id)
+	}
+	if original.MergedIntoID != nil {
+		return nil, fmt.Errorf("%w: incident %s has been merged into %s", ErrInvalidIncidentOperation, id, *original.MergedIntoID)
+	}
+
+	// Divide the alerts of the original incident by the fingerprints
+	splitFingerprints := make(map[string]struct{})
+	for _, fingerprint := range fingerprints {
+		splitFingerprints[fingerprint] = struct{}{}
+	}
+	keptAlerts, splitAlerts := []models.Alert{}, []models.Alert{}
+	for _, alert := range incidentAlerts(original) {
+		if _, ok := splitFingerprints[alert.Identity()]; ok {
+			splitAlerts = append(splitAlerts, alert)
+		} else {
+			keptAlerts = append(keptAlerts, alert)
+		}
+	}
+	keptAlertsData, err := json.Marshal(keptAlerts)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling the alerts: %w", err)
+	}
+	splitAlertsData, err := json.Marshal(splitAlerts)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling the alerts: %w", err)
+	}
+
+	// The new incident is manual, so the handler keeps updating the original incident of the rule
+	currentTimeUTC := time.Now().UTC()
+	newIncident := *original
+	newIncident.ID = uuid.NewString()
+	newIncident.Type = "manual"
+	if summary != "" {
+		newIncident.Summary = summary
+	}
+	newIncident.IsConfirmed = false
+	newIncident.ConfirmationTime = time.Time{}
+	newIncident.Quarter = utils.GetCurrentQuarter()
+	newIncident.Creator = creator
+	newIncident.RuleID = nil
+	newIncident.MatchingCount = 0
+	newIncident.LastMatchingTime = time.Time{}
+	newIncident.AlertsData = string(splitAlertsData)
+	newIncident.IsFlapping = false
+	newIncident.FlapCount = 0
+	newIncident.StateChangesAt = nil
+	newIncident.Version = 1
+	newIncident.CreatedAt = currentTimeUTC
+	newIncident.UpdatedAt = currentTimeUTC
+	if err := newIncident.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIncidentOperation, err.Error())
+	}
+
+	if err := tx.QueryRow(ctx, insertIncidentQuery, incidentInsertArgs(&newIncident)...).Scan(&newIncident.ClosedAt); err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	tag, err := tx.Exec(ctx, splitIncidentAlertsQuery, newIncident.ID, id, fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	if tag.RowsAffected() == 0 && len(splitAlerts) == 0 {
+		return nil, fmt.Errorf("%w: none of the alerts belong to incident %s", ErrInvalidIncidentOperation, id)
+	}
+	if len(splitAlerts) > 0 {
+		if _, err := tx.Exec(ctx, updateSplitIncidentQuery, string(keptAlertsData), currentTimeUTC, id); err != nil {
+			return nil, fmt.Errorf("error executing the query: %w", err)
+		}
+	}
+
+	if err := insertIncidentLinkAndEvent(ctx, tx, newIncident.ID, id, models.SplitFromLinkType, models.SplitEventType,
+		map[string]interface{}{"split_from_id": id, "fingerprints": fingerprints}, creator, currentTimeUTC); err != nil {
+		return nil, err
+	}
+	if err := insertIncidentEvent(ctx, tx, id, models.SplitEventType,
+		map[string]interface{}{"split_into_id": newIncident.ID, "fingerprints": fingerprints}, creator, currentTimeUTC); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error committing the transaction: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id":    id,
+		"newID": newIncident.ID,
+	}).Debug("The incident has been split in the database")
+
+	return &newIncident, nil
+}
+
+// selectIncidentsForUpdate selects and locks the incidents by IDs within the transaction, mapping them by IDs.
+// An ID that can't be a valid one is reported as ErrNotFound, as the missing incidents are by the callers.
+func selectIncidentsForUpdate(ctx context.Context, tx pgx.Tx, ids []string) (map[string]*models.Incident, error) {
+	rows, err := tx.Query(ctx, selectIncidentsForUpdateQuery, ids)
+	if err != nil {
+		return nil, selectRecordError(err)
+	}
+	defer rows.Close()
+
+	incidents := make(map[string]*models.Incident)
+	for rows.Next() {
+		incident := &models.Incident{}
+		if err := rows.Scan(incidentScanDest(incident)...); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		incidents[incident.ID] = incident
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	return incidents, nil
+}
+
+// insertIncidentLinkAndEvent links the incident to the linked one and records the event of the incident within the transaction.
+func insertIncidentLinkAndEvent(ctx context.Context, tx pgx.Tx, incidentID, linkedIncidentID, linkType, eventType string, eventData map[string]interface{}, creator string, at time.Time) error {
+	if _, err := tx.Exec(ctx, insertIncidentLinkQuery, uuid.NewString(), incidentID, linkedIncidentID, linkType, creator, at); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+	return insertIncidentEvent(ctx, tx, incidentID, eventType, eventData, creator, at)
+}
+
+// insertIncidentEvent records an event of the incident within the transaction.
+func insertIncidentEvent(ctx context.Context, tx pgx.Tx, incidentID, eventType string, eventData map[string]interface{}, creator string, at time.Time) error {
+	data, err := json.Marshal(eventData)
+	if err != nil {
+		return fmt.Errorf("error marshaling the event data: %w", err)
+	}
+	if _, err := tx.Exec(ctx, insertIncidentEventQuery, uuid.NewString(), incidentID, eventType, string(data), creator, at); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+	return nil
+}
+
+// incidentAlerts returns the alerts stored in the alerts data of the incident; manual incidents may have none.
+func incidentAlerts(incident *models.Incident) []models.Alert {
+	alerts := []models.Alert{}
+	if incident.AlertsData != "" {
+		if err := json.Unmarshal([]byte(incident.AlertsData), &alerts); err != nil {
+			log.WithFields(log.Fields{
+				"id":    incident.ID,
+				"error": err.Error(),
+			}).Warn("Failed to unmarshal the alerts data of the incident; ignoring it")
+			return []models.Alert{}
+		}
+	}
+	return alerts
+}
+
+// uniqueAlerts returns the alerts without repeated ones, keeping the first occurrence of each alert.
+func uniqueAlerts(alerts []models.Alert) []models.Alert {
+	unique := []models.Alert{}
+	seen := make(map[string]struct{})
+	for _, alert := range alerts {
+		if _, ok := seen[alert.Identity()]; ok {
+			continue
+		}
+		seen[alert.Identity()] = struct{}{}
+		unique = append(unique, alert)
+	}
+	return unique
+}
@@ -0,0 +1,143 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// reportDimensions maps the dimensions of the incidents reports to the SQL expressions and joins grouping the incidents by them.
+// The incidents with several sale channels or trouble services are counted in the group of each of them.
+var reportDimensions = map[string]struct {
+	key  string
+	join string
+}{
+	"quarter":          {key: "quarter::TEXT"},
+	"departament":      {key: "departament"},
+	"failure_type":     {key: "failure_type"},
+	"trouble_services": {key: "dimension.key", join: " CROSS JOIN LATERAL UNNEST(trouble_services) AS dimension(key)"},
+	"sale_channels":    {key: "dimension.key", join: " CROSS JOIN LATERAL UNNEST(sale_channels) AS dimension(key)"},
+}
+
+// IsReportDimension reports whether the incidents reports can be grouped by the dimension.
+func IsReportDimension(dimension string) bool {
+	_, ok := reportDimensions[dimension]
+	return ok
+}
+
+// ReportDimensions returns the dimensions the incidents reports can be grouped by, sorted by name.
+func ReportDimensions() []string {
+	dimensions := make([]string, 0, len(reportDimensions))
+	for dimension := range reportDimensions {
+		dimensions = append(dimensions, dimension)
+	}
+	slices.Sort(dimensions)
+	return dimensions
+}
+
+// GetIncidentsReport aggregates the incidents matching the filters and the creation time range by the dimension.
+// The incidents merged into other ones are left out, so an outage is counted once.
+func (ir *IncidentsRepository) GetIncidentsReport(ctx context.Context, dimension string, filterBy map[string]interface{}, startTime, endTime time.Time) ([]*models.IncidentsReportRow, error) {
+	log.WithFields(log.Fields{
+		"dimension": dimension,
+		"filterBy":  filterBy,
+		"startTime": startTime,
+		"endTime":   endTime,
+	}).Debug("Aggregating incidents for a report in the database")
+
+	query, args, err := buildReportQueryForIncidents(dimension, filterBy, startTime, endTime, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ir.dbPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	reportRows := []*models.IncidentsReportRow{}
+	for rows.Next() {
+		row := &models.IncidentsReportRow{}
+		if err := rows.Scan(
+			&row.Key, &row.Count, &row.TotalDurationSeconds, &row.MeanDurationSeconds,
+			&row.MTTASeconds, &row.MTTRSeconds, &row.DowntimeMinutes, &row.FinLosses,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		reportRows = append(reportRows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"dimension": dimension,
+		"rowsCount": len(reportRows),
+	}).Debug("Incidents report successfully aggregated in the database")
+
+	return reportRows, nil
+}
+
+// buildReportQueryForIncidents constructs the SQL query aggregating the incidents by the dimension.
+// The first argument is the time of the report the actual incidents last until.
+func buildReportQueryForIncidents(dimension string, filterBy map[string]interface{}, startTime, endTime, reportTime time.Time) (string, []interface{}, error) {
+	dim, ok := reportDimensions[dimension]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown report dimension: %s", dimension)
+	}
+
+	// Duration of an incident in seconds; an actual incident has no end time yet
+	duration := "EXTRACT(EPOCH FROM (CASE WHEN status = 'actual' THEN $1 ELSE to_at END) - from_at)"
+
+	baseQuery := `SELECT ` + dim.key + ` AS key,
+		COUNT(id),
+		COALESCE(SUM(` + duration + `), 0)::FLOAT8,
+		COALESCE(AVG(` + duration + `), 0)::FLOAT8,
+		COALESCE(AVG(EXTRACT(EPOCH FROM confirmation_time - from_at)) FILTER (WHERE is_confirmed), 0)::FLOAT8,
+		COALESCE(AVG(` + duration + `) FILTER (WHERE status <> 'actual'), 0)::FLOAT8,
+		(COALESCE(SUM(` + duration + `) FILTER (WHERE is_downtime), 0) / 60)::FLOAT8,
+		COALESCE(SUM(fin_losses), 0)::BIGINT
+		FROM a2i_incidents` + dim.join + `
+		WHERE merged_into_id IS NULL`
+	args := []interface{}{reportTime}
+	argId := 2
+
+	// Add conditions based on the filters provided.
+	for field, value := range filterBy {
+		if field == "created_at" || field == "updated_at" {
+			continue
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			placeholders := make([]string, len(v))
+			for i := range v {
+				placeholders[i] = fmt.Sprintf("$%d", argId)
+				args = append(args, v[i])
+				argId++
+			}
+			baseQuery += fmt.Sprintf(" AND %s @> ARRAY[%s]", field, strings.Join(placeholders, ","))
+		default:
+			baseQuery += fmt.Sprintf(" AND %s = $%d", field, argId)
+			args = append(args, value)
+			argId++
+		}
+	}
+
+	// Filter by date range if specified.
+	if !startTime.IsZero() && !endTime.IsZero() {
+		baseQuery += fmt.Sprintf(" AND created_at BETWEEN $%d AND $%d", argId, argId+1)
+		args = append(args, startTime, endTime)
+	}
+
+	baseQuery += " GROUP BY 1 ORDER BY 1"
+
+	return baseQuery, args, nil
+}
@@ -2,11 +2,13 @@ package repositories // dnywonnt.me/alerts2incidents/internal/database/repositor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	log "github.com/sirupsen/logrus"
@@ -14,40 +16,92 @@ import (
 
 // SQL queries as constants for code cleanliness and maintainability.
 const (
-	// insertIncidentQuery represents an SQL query for inserting a new incident into the database.
-	insertIncidentQuery = `
-		INSERT INTO a2i_incidents (
+	// incidentColumns lists all columns of an incident in the order they are inserted and scanned in, see incidentScanDest.
+	// The closing time comes last: it isn't inserted as is, but set by the queries on the transition to the closed status.
+	incidentColumns = `
 		    id, type, status, summary, description, from_at, to_at, is_confirmed, confirmation_time,
 		    quarter, departament, client_affect, is_manageable, sale_channels, trouble_services,
 		    fin_losses, failure_type, is_deploy, deploy_link, labels, is_downtime,
 		    postmortem_link, creator, rule_id, matching_count, last_matching_time, alerts_data,
-		    created_at, updated_at
-		) VALUES (
+		    is_flapping, flap_count, state_changes_at, severity, merged_into_id, version, created_at, updated_at, closed_at
+	`
+
+	// insertIncidentQuery represents an SQL query for inserting a new incident into the database;
+	// an incident created closed is closed at its creation.
+	insertIncidentQuery = `
+		INSERT INTO a2i_incidents (` + incidentColumns + `) VALUES (
 		    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
-		    $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29
+		    $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35,
+		    CASE WHEN $3 = 'closed' THEN $35::TIMESTAMP END
 		)
+		RETURNING closed_at
 	`
 
 	// selectIncidentQuery represents an SQL query for selecting an incident by ID from the database.
 	selectIncidentQuery = `
-		SELECT id, type, status, summary, description, from_at, to_at, is_confirmed, confirmation_time,
-		    quarter, departament, client_affect, is_manageable, sale_channels, trouble_services,
-		    fin_losses, failure_type, is_deploy, deploy_link, labels, is_downtime,
-		    postmortem_link, creator, rule_id, matching_count, last_matching_time, alerts_data,
-		    created_at, updated_at
+		SELECT ` + incidentColumns + `
 		FROM a2i_incidents
 		WHERE id = $1
 	`
 
-	// updateIncidentQuery represents an SQL query for updating an existing incident in the database.
+	// updateIncidentQuery represents an SQL query for updating an existing incident in the database
+	// if it still has the version the update is based on; the version is incremented. The matching and flapping columns
+	// are owned by the handler and left intact, see updateIncidentMatchingQuery. The closing time is set when the incident
+	// gets closed, kept while it stays closed and cleared when it's reopened.
 	updateIncidentQuery = `
 		UPDATE a2i_incidents
 		SET status = $1, summary = $2, description = $3, from_at = $4, to_at = $5, is_confirmed = $6,
 		    confirmation_time = $7, departament = $8, client_affect = $9, is_manageable = $10,
 		    sale_channels = $11, trouble_services = $12, fin_losses = $13, failure_type = $14, is_deploy = $15,
-		    deploy_link = $16, labels = $17, is_downtime = $18, postmortem_link = $19, 
-		    matching_count = $20, last_matching_time = $21, updated_at = $22
-		WHERE id = $23
+		    deploy_link = $16, labels = $17, is_downtime = $18, postmortem_link = $19,
+		    severity = $20, updated_at = $21, version = version + 1,
+		    closed_at = CASE
+		        WHEN $1 <> 'closed' THEN NULL
+		        WHEN status = 'closed' THEN closed_at
+		        ELSE $21
+		    END
+		WHERE id = $22 AND version = $23
+		RETURNING version, closed_at
+	`
+
+	// updateIncidentMatchingQuery represents an SQL query for updating the columns of an incident owned by the handler,
+	// so the columns edited by hand in the meantime aren't overwritten. A closed incident keeps its status and end time,
+	// and the severity is only raised, by the severity derived from the matching alerts, if any. The version is only
+	// incremented if the status, the end time or the severity, which can also be edited by hand, change: the matching
+	// and flapping details change on every poll of the collectors and would otherwise fail every conditional update of the incident.
+	updateIncidentMatchingQuery = `
+		UPDATE a2i_incidents
+		SET status = CASE WHEN status = 'closed' THEN status ELSE $1 END,
+		    to_at = CASE WHEN status = 'closed' THEN to_at ELSE $2 END,
+		    matching_count = $3, last_matching_time = $4, is_flapping = $5, flap_count = $6, state_changes_at = $7,
+		    severity = LEAST(severity, NULLIF($8, '')), updated_at = $9,
+		    version = version + CASE
+		        WHEN status <> 'closed' AND (status IS DISTINCT FROM $1 OR to_at IS DISTINCT FROM $2) THEN 1
+		        WHEN severity IS DISTINCT FROM LEAST(severity, NULLIF($8, '')) THEN 1
+		        ELSE 0
+		    END
+		WHERE id = $10
+		RETURNING ` + incidentColumns + `
+	`
+
+	// selectIncidentsForDigestQuery represents an SQL query for selecting the incidents opened, finished or closed
+	// within a time window, as well as the auto incidents that are still actual and unconfirmed.
+	selectIncidentsForDigestQuery = `
+		SELECT ` + incidentColumns + `
+		FROM a2i_incidents
+		WHERE from_at BETWEEN $1 AND $2
+		    OR (status IN ('finished', 'closed') AND to_at BETWEEN $1 AND $2)
+		    OR closed_at BETWEEN $1 AND $2
+		    OR (type = 'auto' AND status = 'actual' AND is_confirmed = false)
+		ORDER BY from_at
+	`
+
+	// selectUnconfirmedAutoIncidentsQuery represents an SQL query for selecting the auto incidents that are still actual and unconfirmed.
+	selectUnconfirmedAutoIncidentsQuery = `
+		SELECT ` + incidentColumns + `
+		FROM a2i_incidents
+		WHERE type = 'auto' AND status = 'actual' AND is_confirmed = false
+		ORDER BY created_at
 	`
 
 	// deleteIncidentQuery represents an SQL query for deleting an incident from the database by ID.
@@ -76,16 +130,11 @@ func (ir *IncidentsRepository) CreateIncident(ctx context.Context, incident *mod
 		"id": incident.ID,
 	}).Debug("Creating a new incident in the database")
 
-	if _, err := ir.dbPool.Exec(
+	if err := ir.dbPool.QueryRow(
 		ctx,
 		insertIncidentQuery,
-		incident.ID, incident.Type, incident.Status, incident.Summary, incident.Description, incident.FromAt, incident.ToAt,
-		incident.IsConfirmed, incident.ConfirmationTime, incident.Quarter, incident.Departament, incident.ClientAffect,
-		incident.IsManageable, incident.SaleChannels, incident.TroubleServices, incident.FinLosses, incident.FailureType,
-		incident.IsDeploy, incident.DeployLink, incident.Labels, incident.IsDowntime, incident.PostmortemLink,
-		incident.Creator, incident.RuleID, incident.MatchingCount, incident.LastMatchingTime, incident.AlertsData,
-		incident.CreatedAt, incident.UpdatedAt,
-	); err != nil {
+		incidentInsertArgs(incident)...,
+	).Scan(&incident.ClosedAt); err != nil {
 		return fmt.Errorf("error executing the query: %w", err)
 	}
 
@@ -97,22 +146,16 @@ func (ir *IncidentsRepository) CreateIncident(ctx context.Context, incident *mod
 }
 
 // GetIncident retrieves an incident from the database based on the incident ID.
-// This method performs a database query to select the incident and maps the result to an Incident model.
+// This method performs a database query to select the incident and maps the result to an Incident model;
+// it returns ErrNotFound if there is no such incident.
 func (ir *IncidentsRepository) GetIncident(ctx context.Context, id string) (*models.Incident, error) {
 	log.WithFields(log.Fields{
 		"id": id,
 	}).Debug("Retrieving an incident from the database")
 
 	incident := &models.Incident{}
-	if err := ir.dbPool.QueryRow(ctx, selectIncidentQuery, id).Scan(
-		&incident.ID, &incident.Type, &incident.Status, &incident.Summary, &incident.Description, &incident.FromAt, &incident.ToAt,
-		&incident.IsConfirmed, &incident.ConfirmationTime, &incident.Quarter, &incident.Departament, &incident.ClientAffect,
-		&incident.IsManageable, &incident.SaleChannels, &incident.TroubleServices, &incident.FinLosses, &incident.FailureType,
-		&incident.IsDeploy, &incident.DeployLink, &incident.Labels, &incident.IsDowntime, &incident.PostmortemLink,
-		&incident.Creator, &incident.RuleID, &incident.MatchingCount, &incident.LastMatchingTime,
-		&incident.AlertsData, &incident.CreatedAt, &incident.UpdatedAt,
-	); err != nil {
-		return nil, fmt.Errorf("error executing the query: %w", err)
+	if err := ir.dbPool.QueryRow(ctx, selectIncidentQuery, id).Scan(incidentScanDest(incident)...); err != nil {
+		return nil, selectRecordError(err)
 	}
 
 	log.WithFields(log.Fields{
@@ -124,21 +167,18 @@ func (ir *IncidentsRepository) GetIncident(ctx context.Context, id string) (*mod
 
 // UpdateIncident updates an existing incident in the database.
 // This method logs the update process and executes an SQL query to update the incident data based on provided changes.
+// The update only succeeds if the incident still has the version of the model, otherwise ErrVersionConflict is returned;
+// on success the model gets the new version. The matching and flapping details are owned by the handler and aren't written.
 func (ir *IncidentsRepository) UpdateIncident(ctx context.Context, incident *models.Incident) error {
 	log.WithFields(log.Fields{
-		"id": incident.ID,
+		"id":      incident.ID,
+		"version": incident.Version,
 	}).Debug("Updating an incident in the database")
 
-	if _, err := ir.dbPool.Exec(
-		ctx,
-		updateIncidentQuery,
-		incident.Status, incident.Summary, incident.Description, incident.FromAt, incident.ToAt, incident.IsConfirmed,
-		incident.ConfirmationTime, incident.Departament, incident.ClientAffect, incident.IsManageable,
-		incident.SaleChannels, incident.TroubleServices, incident.FinLosses, incident.FailureType, incident.IsDeploy,
-		incident.DeployLink, incident.Labels, incident.IsDowntime, incident.PostmortemLink,
-		incident.MatchingCount, incident.LastMatchingTime,
-		incident.UpdatedAt, incident.ID,
-	); err != nil {
+	if err := ir.dbPool.QueryRow(ctx, updateIncidentQuery, incidentUpdateArgs(incident)...).Scan(&incident.Version, &incident.ClosedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVersionConflict
+		}
 		return fmt.Errorf("error executing the query: %w", err)
 	}
 
@@ -149,6 +189,32 @@ func (ir *IncidentsRepository) UpdateIncident(ctx context.Context, incident *mod
 	return nil
 }
 
+// UpdateIncidentMatching updates the columns of an incident owned by the handler: the status, the end time and the matching
+// and flapping details, raising the severity to the derived one unless it's empty. The other columns may have been edited by hand
+// since the incident has been cached, so they're left intact; the model is refreshed with the stored incident.
+// The version is only incremented if the status, the end time or the severity change.
+func (ir *IncidentsRepository) UpdateIncidentMatching(ctx context.Context, incident *models.Incident, derivedSeverity string) error {
+	log.WithFields(log.Fields{
+		"id": incident.ID,
+	}).Debug("Updating the matching details of an incident in the database")
+
+	if err := ir.dbPool.QueryRow(
+		ctx,
+		updateIncidentMatchingQuery,
+		incident.Status, incident.ToAt, incident.MatchingCount, incident.LastMatchingTime, incident.IsFlapping,
+		incident.FlapCount, incident.StateChangesAt, derivedSeverity, incident.UpdatedAt, incident.ID,
+	).Scan(incidentScanDest(incident)...); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id":      incident.ID,
+		"version": incident.Version,
+	}).Debug("The matching details of the incident have been updated in the database")
+
+	return nil
+}
+
 // DeleteIncident removes an incident from the database by its ID.
 // This method logs the deletion process and executes an SQL query to delete the incident.
 func (ir *IncidentsRepository) DeleteIncident(ctx context.Context, id string) error {
@@ -194,14 +260,7 @@ func (ir *IncidentsRepository) GetIncidents(ctx context.Context, filterBy map[st
 	incidents := []*models.Incident{}
 	for rows.Next() {
 		incident := &models.Incident{}
-		if err := rows.Scan(
-			&incident.ID, &incident.Type, &incident.Status, &incident.Summary, &incident.Description, &incident.FromAt, &incident.ToAt,
-			&incident.IsConfirmed, &incident.ConfirmationTime, &incident.Quarter, &incident.Departament, &incident.ClientAffect,
-			&incident.IsManageable, &incident.SaleChannels, &incident.TroubleServices, &incident.FinLosses, &incident.FailureType,
-			&incident.IsDeploy, &incident.DeployLink, &incident.Labels, &incident.IsDowntime, &incident.PostmortemLink,
-			&incident.Creator, &incident.RuleID, &incident.MatchingCount, &incident.LastMatchingTime,
-			&incident.AlertsData, &incident.CreatedAt, &incident.UpdatedAt,
-		); err != nil {
+		if err := rows.Scan(incidentScanDest(incident)...); err != nil {
 			return nil, fmt.Errorf("error scanning the row: %w", err)
 		}
 		incidents = append(incidents, incident)
@@ -218,6 +277,113 @@ func (ir *IncidentsRepository) GetIncidents(ctx context.Context, filterBy map[st
 	return incidents, nil
 }
 
+// StreamIncidents retrieves all incidents matching the filters and the date range in the given order
+// and passes them to the function one by one, without loading the whole result set into memory.
+// Iteration stops at the first error returned by the function.
+func (ir *IncidentsRepository) StreamIncidents(ctx context.Context, filterBy map[string]interface{}, sortBy, sortOrder string, startTime, endTime time.Time, fn func(*models.Incident) error) error {
+	log.WithFields(log.Fields{
+		"filterBy":  filterBy,
+		"sortBy":    sortBy,
+		"sortOrder": sortOrder,
+		"startTime": startTime,
+		"endTime":   endTime,
+	}).Debug("Streaming incidents with filters and sorting from the database")
+
+	// Build the SQL query without pagination.
+	query, args := buildGetQueryForIncidents(filterBy, sortBy, sortOrder, 0, 0, startTime, endTime)
+
+	rows, err := ir.dbPool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	// Scan every row into the same incident, as the function is not expected to keep it.
+	incidentsCount := 0
+	incident := &models.Incident{}
+	for rows.Next() {
+		*incident = models.Incident{}
+		if err := rows.Scan(incidentScanDest(incident)...); err != nil {
+			return fmt.Errorf("error scanning the row: %w", err)
+		}
+		if err := fn(incident); err != nil {
+			return err
+		}
+		incidentsCount++
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"incidentsCount": incidentsCount,
+	}).Debug("Incidents successfully streamed from the database")
+
+	return nil
+}
+
+// GetIncidentsForDigest retrieves the incidents opened, finished or closed within the time window
+// together with the auto incidents that are still actual and unconfirmed, ordered by their start time.
+func (ir *IncidentsRepository) GetIncidentsForDigest(ctx context.Context, startTime, endTime time.Time) ([]*models.Incident, error) {
+	log.WithFields(log.Fields{
+		"startTime": startTime,
+		"endTime":   endTime,
+	}).Debug("Retrieving incidents for the digest from the database")
+
+	incidents, err := ir.queryIncidents(ctx, selectIncidentsForDigestQuery, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"incidentsCount": len(incidents),
+	}).Debug("Incidents for the digest successfully retrieved from the database")
+
+	return incidents, nil
+}
+
+// GetUnconfirmedAutoIncidents retrieves the auto incidents that are still actual and unconfirmed, ordered by their creation time.
+func (ir *IncidentsRepository) GetUnconfirmedAutoIncidents(ctx context.Context) ([]*models.Incident, error) {
+	log.Debug("Retrieving unconfirmed auto incidents from the database")
+
+	incidents, err := ir.queryIncidents(ctx, selectUnconfirmedAutoIncidentsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"incidentsCount": len(incidents),
+	}).Debug("Unconfirmed auto incidents successfully retrieved from the database")
+
+	return incidents, nil
+}
+
+// queryIncidents executes a query selecting all incident columns and scans the resulting rows.
+func (ir *IncidentsRepository) queryIncidents(ctx context.Context, query string, args ...interface{}) ([]*models.Incident, error) {
+	rows, err := ir.dbPool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	// Iterate through the result set and populate the incidents slice.
+	incidents := []*models.Incident{}
+	for rows.Next() {
+		incident := &models.Incident{}
+		if err := rows.Scan(incidentScanDest(incident)...); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return incidents, nil
+}
+
 // GetTotalIncidents counts the total number of incidents in the database that match specified filters and date range.
 // This method constructs a count query dynamically and executes it to obtain the total count of matching incidents.
 func (ir *IncidentsRepository) GetTotalIncidents(ctx context.Context, filterBy map[string]interface{}, startTime, endTime time.Time) (int, error) {
@@ -243,13 +409,46 @@ func (ir *IncidentsRepository) GetTotalIncidents(ctx context.Context, filterBy m
 	return totalIncidents, nil
 }
 
+// incidentScanDest returns the destinations for scanning all columns of an incident, in the order of incidentColumns.
+func incidentScanDest(incident *models.Incident) []interface{} {
+	return []interface{}{
+		&incident.ID, &incident.Type, &incident.Status, &incident.Summary, &incident.Description, &incident.FromAt, &incident.ToAt,
+		&incident.IsConfirmed, &incident.ConfirmationTime, &incident.Quarter, &incident.Departament, &incident.ClientAffect,
+		&incident.IsManageable, &incident.SaleChannels, &incident.TroubleServices, &incident.FinLosses, &incident.FailureType,
+		&incident.IsDeploy, &incident.DeployLink, &incident.Labels, &incident.IsDowntime, &incident.PostmortemLink,
+		&incident.Creator, &incident.RuleID, &incident.MatchingCount, &incident.LastMatchingTime,
+		&incident.AlertsData, &incident.IsFlapping, &incident.FlapCount, &incident.StateChangesAt, &incident.Severity, &incident.MergedIntoID, &incident.Version, &incident.CreatedAt, &incident.UpdatedAt,
+		&incident.ClosedAt,
+	}
+}
+
+// incidentInsertArgs returns the values of all columns of an incident but the closing time for inserting it, in the order of incidentColumns.
+func incidentInsertArgs(incident *models.Incident) []interface{} {
+	return []interface{}{
+		incident.ID, incident.Type, incident.Status, incident.Summary, incident.Description, incident.FromAt, incident.ToAt,
+		incident.IsConfirmed, incident.ConfirmationTime, incident.Quarter, incident.Departament, incident.ClientAffect,
+		incident.IsManageable, incident.SaleChannels, incident.TroubleServices, incident.FinLosses, incident.FailureType,
+		incident.IsDeploy, incident.DeployLink, incident.Labels, incident.IsDowntime, incident.PostmortemLink,
+		incident.Creator, incident.RuleID, incident.MatchingCount, incident.LastMatchingTime, incident.AlertsData,
+		incident.IsFlapping, incident.FlapCount, incident.StateChangesAt, incident.Severity, incident.MergedIntoID, incident.Version, incident.CreatedAt, incident.UpdatedAt,
+	}
+}
+
+// incidentUpdateArgs returns the arguments of updateIncidentQuery for updating an incident of the version of the model.
+func incidentUpdateArgs(incident *models.Incident) []interface{} {
+	return []interface{}{
+		incident.Status, incident.Summary, incident.Description, incident.FromAt, incident.ToAt, incident.IsConfirmed,
+		incident.ConfirmationTime, incident.Departament, incident.ClientAffect, incident.IsManageable,
+		incident.SaleChannels, incident.TroubleServices, incident.FinLosses, incident.FailureType, incident.IsDeploy,
+		incident.DeployLink, incident.Labels, incident.IsDowntime, incident.PostmortemLink,
+		incident.Severity, incident.UpdatedAt, incident.ID, incident.Version,
+	}
+}
+
 // buildGetQueryForIncidents constructs a dynamic SQL query for retrieving incidents based on various filters and pagination settings.
 // This internal function assembles the SQL query string and corresponding arguments based on the specified criteria.
 func buildGetQueryForIncidents(filterBy map[string]interface{}, sortBy, sortOrder string, pageNum, pageSize int, startTime, endTime time.Time) (string, []interface{}) {
-	baseQuery := `SELECT id, type, status, summary, description, from_at, to_at, is_confirmed, confirmation_time,
-        quarter, departament, client_affect, is_manageable, sale_channels, trouble_services, fin_losses, failure_type,
-        is_deploy, deploy_link, labels, is_downtime, postmortem_link, creator, rule_id, matching_count, last_matching_time, alerts_data,
-        created_at, updated_at FROM a2i_incidents WHERE 1 = 1`
+	baseQuery := `SELECT ` + incidentColumns + ` FROM a2i_incidents WHERE 1 = 1`
 	args := []interface{}{}
 	argId := 1
 
@@ -286,9 +485,12 @@ func buildGetQueryForIncidents(filterBy map[string]interface{}, sortBy, sortOrde
 		baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
 	}
 
-	offset := (pageNum - 1) * pageSize
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argId, argId+1)
-	args = append(args, pageSize, offset)
+	// A non-positive page size selects all matching incidents.
+	if pageSize > 0 {
+		offset := (pageNum - 1) * pageSize
+		baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argId, argId+1)
+		args = append(args, pageSize, offset)
+	}
 
 	return baseQuery, args
 }
@@ -0,0 +1,181 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for CRUD operations on maintenance windows
+const (
+	// Query for inserting a new maintenance window into the database
+	insertMaintenanceWindowQuery = `
+		INSERT INTO a2i_maintenance_windows (
+		    id, description, from_at, to_at, recurrence, timezone, rule_ids, labels, trouble_services,
+		    creator, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	// Query for selecting a maintenance window by ID
+	selectMaintenanceWindowQuery = `
+		SELECT id, description, from_at, to_at, recurrence, timezone, rule_ids, labels, trouble_services,
+		    creator, created_at, updated_at
+		FROM a2i_maintenance_windows
+		WHERE id = $1
+	`
+
+	// Query for selecting all maintenance windows, optionally only those that haven't ended by the given time
+	selectMaintenanceWindowsQuery = `
+		SELECT id, description, from_at, to_at, recurrence, timezone, rule_ids, labels, trouble_services,
+		    creator, created_at, updated_at
+		FROM a2i_maintenance_windows
+		WHERE $1::TIMESTAMP IS NULL OR recurrence <> 'none' OR to_at > $1
+		ORDER BY from_at
+	`
+
+	// Query for updating an existing maintenance window
+	updateMaintenanceWindowQuery = `
+		UPDATE a2i_maintenance_windows
+		SET description = $1, from_at = $2, to_at = $3, recurrence = $4, timezone = $5, rule_ids = $6,
+		    labels = $7, trouble_services = $8, updated_at = $9
+		WHERE id = $10
+	`
+
+	// Query for deleting a maintenance window by ID
+	deleteMaintenanceWindowQuery = `
+		DELETE FROM a2i_maintenance_windows
+		WHERE id = $1
+	`
+)
+
+// MaintenanceWindowsRepository struct defines the structure for the repository
+type MaintenanceWindowsRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for MaintenanceWindowsRepository
+func NewMaintenanceWindowsRepository(dbPool *pgxpool.Pool) *MaintenanceWindowsRepository {
+	log.Debug("Initializing the maintenance windows repository")
+	return &MaintenanceWindowsRepository{dbPool: dbPool}
+}
+
+// CreateMaintenanceWindow inserts a new maintenance window into the database
+func (mwr *MaintenanceWindowsRepository) CreateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	log.WithFields(log.Fields{
+		"id": window.ID,
+	}).Debug("Creating a new maintenance window in the database")
+
+	if _, err := mwr.dbPool.Exec(
+		ctx,
+		insertMaintenanceWindowQuery,
+		window.ID, window.Description, window.FromAt, window.ToAt, window.Recurrence, window.Timezone, window.RuleIDs,
+		window.Labels, window.TroubleServices, window.Creator, window.CreatedAt, window.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": window.ID,
+	}).Debug("The maintenance window has been created in the database")
+
+	return nil
+}
+
+// GetMaintenanceWindow retrieves a maintenance window by ID from the database, or ErrNotFound if there is no such window
+func (mwr *MaintenanceWindowsRepository) GetMaintenanceWindow(ctx context.Context, id string) (*models.MaintenanceWindow, error) {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Retrieving a maintenance window from the database")
+
+	window := &models.MaintenanceWindow{}
+	if err := mwr.dbPool.QueryRow(ctx, selectMaintenanceWindowQuery, id).Scan(
+		&window.ID, &window.Description, &window.FromAt, &window.ToAt, &window.Recurrence, &window.Timezone, &window.RuleIDs,
+		&window.Labels, &window.TroubleServices, &window.Creator, &window.CreatedAt, &window.UpdatedAt,
+	); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Maintenance window successfully retrieved from the database")
+
+	return window, nil
+}
+
+// GetMaintenanceWindows retrieves the maintenance windows from the database.
+// If notEndedAt isn't nil, the one-time windows that ended by that time are left out.
+func (mwr *MaintenanceWindowsRepository) GetMaintenanceWindows(ctx context.Context, notEndedAt *time.Time) ([]*models.MaintenanceWindow, error) {
+	log.Debug("Retrieving maintenance windows from the database")
+
+	rows, err := mwr.dbPool.Query(ctx, selectMaintenanceWindowsQuery, notEndedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []*models.MaintenanceWindow{}
+	for rows.Next() {
+		window := &models.MaintenanceWindow{}
+		if err := rows.Scan(
+			&window.ID, &window.Description, &window.FromAt, &window.ToAt, &window.Recurrence, &window.Timezone, &window.RuleIDs,
+			&window.Labels, &window.TroubleServices, &window.Creator, &window.CreatedAt, &window.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		windows = append(windows, window)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"windowsCount": len(windows),
+	}).Debug("Maintenance windows successfully retrieved from the database")
+
+	return windows, nil
+}
+
+// UpdateMaintenanceWindow updates an existing maintenance window in the database
+func (mwr *MaintenanceWindowsRepository) UpdateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) error {
+	log.WithFields(log.Fields{
+		"id": window.ID,
+	}).Debug("Updating a maintenance window in the database")
+
+	if _, err := mwr.dbPool.Exec(
+		ctx,
+		updateMaintenanceWindowQuery,
+		window.Description, window.FromAt, window.ToAt, window.Recurrence, window.Timezone, window.RuleIDs,
+		window.Labels, window.TroubleServices, window.UpdatedAt, window.ID,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": window.ID,
+	}).Debug("The maintenance window has been updated in the database")
+
+	return nil
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window by ID from the database; its suppressions are kept
+func (mwr *MaintenanceWindowsRepository) DeleteMaintenanceWindow(ctx context.Context, id string) error {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Deleting a maintenance window from the database")
+
+	if _, err := mwr.dbPool.Exec(ctx, deleteMaintenanceWindowQuery, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The maintenance window has been deleted from the database")
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for operations on maintenance suppressions
+const (
+	// Query for recording a suppressed match; the matches of a rule within the same occurrence of a window are counted in one record
+	upsertMaintenanceSuppressionQuery = `
+		INSERT INTO a2i_maintenance_suppressions (
+		    id, window_id, window_from_at, rule_id, matching_count, first_matching_time, last_matching_time, alerts_data
+		) VALUES ($1, $2, $3, $4, 1, $5, $5, $6)
+		ON CONFLICT (window_id, window_from_at, rule_id) DO UPDATE
+		SET matching_count = a2i_maintenance_suppressions.matching_count + 1,
+		    last_matching_time = EXCLUDED.last_matching_time,
+		    alerts_data = EXCLUDED.alerts_data
+	`
+
+	// Query for selecting the suppressions of a maintenance window
+	selectMaintenanceSuppressionsQuery = `
+		SELECT id, window_id, window_from_at, rule_id, matching_count, first_matching_time, last_matching_time, alerts_data
+		FROM a2i_maintenance_suppressions
+		WHERE window_id = $1
+		ORDER BY first_matching_time DESC
+	`
+)
+
+// MaintenanceSuppressionsRepository struct defines the structure for the repository
+type MaintenanceSuppressionsRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for MaintenanceSuppressionsRepository
+func NewMaintenanceSuppressionsRepository(dbPool *pgxpool.Pool) *MaintenanceSuppressionsRepository {
+	log.Debug("Initializing the maintenance suppressions repository")
+	return &MaintenanceSuppressionsRepository{dbPool: dbPool}
+}
+
+// RecordMaintenanceSuppression records a match of the rule suppressed by the occurrence of the maintenance window.
+// Only the ID, window, occurrence start, rule, last matching time and alerts of the suppression are used.
+func (msr *MaintenanceSuppressionsRepository) RecordMaintenanceSuppression(ctx context.Context, suppression *models.MaintenanceSuppression) error {
+	log.WithFields(log.Fields{
+		"windowID": suppression.WindowID,
+		"ruleID":   suppression.RuleID,
+	}).Debug("Recording a maintenance suppression in the database")
+
+	if _, err := msr.dbPool.Exec(
+		ctx,
+		upsertMaintenanceSuppressionQuery,
+		suppression.ID, suppression.WindowID, suppression.WindowFromAt, suppression.RuleID, suppression.LastMatchingTime, suppression.AlertsData,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"windowID": suppression.WindowID,
+		"ruleID":   suppression.RuleID,
+	}).Debug("The maintenance suppression has been recorded in the database")
+
+	return nil
+}
+
+// GetMaintenanceSuppressions retrieves the suppressions of a maintenance window from the database, the latest first
+func (msr *MaintenanceSuppressionsRepository) GetMaintenanceSuppressions(ctx context.Context, windowID string) ([]*models.MaintenanceSuppression, error) {
+	log.WithFields(log.Fields{
+		"windowID": windowID,
+	}).Debug("Retrieving maintenance suppressions from the database")
+
+	rows, err := msr.dbPool.Query(ctx, selectMaintenanceSuppressionsQuery, windowID)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	suppressions := []*models.MaintenanceSuppression{}
+	for rows.Next() {
+		suppression := &models.MaintenanceSuppression{}
+		if err := rows.Scan(
+			&suppression.ID, &suppression.WindowID, &suppression.WindowFromAt, &suppression.RuleID, &suppression.MatchingCount,
+			&suppression.FirstMatchingTime, &suppression.LastMatchingTime, &suppression.AlertsData,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		suppressions = append(suppressions, suppression)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"windowID":          windowID,
+		"suppressionsCount": len(suppressions),
+	}).Debug("Maintenance suppressions successfully retrieved from the database")
+
+	return suppressions, nil
+}
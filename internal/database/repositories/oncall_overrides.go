@@ -0,0 +1,153 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for operations on on-call overrides
+const (
+	// Query for inserting a new on-call override into the database
+	insertOnCallOverrideQuery = `
+		INSERT INTO a2i_oncall_overrides (id, schedule_id, user_id, from_at, to_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	// Query for selecting the overrides of a schedule that haven't ended yet
+	selectOnCallOverridesQuery = `
+		SELECT id, schedule_id, user_id, from_at, to_at, created_at
+		FROM a2i_oncall_overrides
+		WHERE schedule_id = $1 AND to_at > $2
+		ORDER BY from_at
+	`
+
+	// Query for selecting the override of a schedule active at a time; the latest override wins if they overlap
+	selectActiveOnCallOverrideQuery = `
+		SELECT id, schedule_id, user_id, from_at, to_at, created_at
+		FROM a2i_oncall_overrides
+		WHERE schedule_id = $1 AND from_at <= $2 AND to_at > $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	// Query for deleting an on-call override by ID
+	deleteOnCallOverrideQuery = `
+		DELETE FROM a2i_oncall_overrides
+		WHERE id = $1
+	`
+)
+
+// OnCallOverridesRepository struct defines the structure for the repository
+type OnCallOverridesRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for OnCallOverridesRepository
+func NewOnCallOverridesRepository(dbPool *pgxpool.Pool) *OnCallOverridesRepository {
+	log.Debug("Initializing the on-call overrides repository")
+	return &OnCallOverridesRepository{dbPool: dbPool}
+}
+
+// CreateOnCallOverride inserts a new on-call override into the database
+func (or *OnCallOverridesRepository) CreateOnCallOverride(ctx context.Context, override *models.OnCallOverride) error {
+	log.WithFields(log.Fields{
+		"id":         override.ID,
+		"scheduleID": override.ScheduleID,
+	}).Debug("Creating a new on-call override in the database")
+
+	if _, err := or.dbPool.Exec(
+		ctx,
+		insertOnCallOverrideQuery,
+		override.ID, override.ScheduleID, override.UserID, override.FromAt, override.ToAt, override.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": override.ID,
+	}).Debug("The on-call override has been created in the database")
+
+	return nil
+}
+
+// GetOnCallOverrides retrieves the overrides of a schedule that haven't ended by the given time
+func (or *OnCallOverridesRepository) GetOnCallOverrides(ctx context.Context, scheduleID string, at time.Time) ([]*models.OnCallOverride, error) {
+	log.WithFields(log.Fields{
+		"scheduleID": scheduleID,
+		"at":         at,
+	}).Debug("Retrieving on-call overrides from the database")
+
+	rows, err := or.dbPool.Query(ctx, selectOnCallOverridesQuery, scheduleID, at)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	overrides := []*models.OnCallOverride{}
+	for rows.Next() {
+		override := &models.OnCallOverride{}
+		if err := rows.Scan(
+			&override.ID, &override.ScheduleID, &override.UserID, &override.FromAt, &override.ToAt, &override.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		overrides = append(overrides, override)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"scheduleID":     scheduleID,
+		"overridesCount": len(overrides),
+	}).Debug("On-call overrides successfully retrieved from the database")
+
+	return overrides, nil
+}
+
+// GetActiveOnCallOverride retrieves the override of a schedule active at the given time.
+// It returns nil without an error if there is no such override.
+func (or *OnCallOverridesRepository) GetActiveOnCallOverride(ctx context.Context, scheduleID string, at time.Time) (*models.OnCallOverride, error) {
+	log.WithFields(log.Fields{
+		"scheduleID": scheduleID,
+		"at":         at,
+	}).Debug("Retrieving the active on-call override from the database")
+
+	override := &models.OnCallOverride{}
+	if err := or.dbPool.QueryRow(ctx, selectActiveOnCallOverrideQuery, scheduleID, at).Scan(
+		&override.ID, &override.ScheduleID, &override.UserID, &override.FromAt, &override.ToAt, &override.CreatedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+
+	return override, nil
+}
+
+// DeleteOnCallOverride deletes an on-call override by ID from the database
+func (or *OnCallOverridesRepository) DeleteOnCallOverride(ctx context.Context, id string) error {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Deleting an on-call override from the database")
+
+	if _, err := or.dbPool.Exec(ctx, deleteOnCallOverrideQuery, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The on-call override has been deleted from the database")
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for CRUD operations on on-call schedules
+const (
+	// Query for inserting a new on-call schedule into the database
+	insertOnCallScheduleQuery = `
+		INSERT INTO a2i_oncall_schedules (
+			id, name, description, timezone, rotation, rotation_start, user_ids, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	// Query for selecting an on-call schedule by ID
+	selectOnCallScheduleQuery = `
+		SELECT id, name, description, timezone, rotation, rotation_start, user_ids, created_at, updated_at
+		FROM a2i_oncall_schedules
+		WHERE id = $1
+	`
+
+	// Query for selecting an on-call schedule by name
+	selectOnCallScheduleByNameQuery = `
+		SELECT id, name, description, timezone, rotation, rotation_start, user_ids, created_at, updated_at
+		FROM a2i_oncall_schedules
+		WHERE name = $1
+	`
+
+	// Query for selecting all on-call schedules
+	selectOnCallSchedulesQuery = `
+		SELECT id, name, description, timezone, rotation, rotation_start, user_ids, created_at, updated_at
+		FROM a2i_oncall_schedules
+		ORDER BY name
+	`
+
+	// Query for updating an existing on-call schedule
+	updateOnCallScheduleQuery = `
+		UPDATE a2i_oncall_schedules
+		SET name = $1, description = $2, timezone = $3, rotation = $4, rotation_start = $5, user_ids = $6, updated_at = $7
+		WHERE id = $8
+	`
+
+	// Query for deleting an on-call schedule by ID
+	deleteOnCallScheduleQuery = `
+		DELETE FROM a2i_oncall_schedules
+		WHERE id = $1
+	`
+)
+
+// OnCallSchedulesRepository struct defines the structure for the repository
+type OnCallSchedulesRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for OnCallSchedulesRepository
+func NewOnCallSchedulesRepository(dbPool *pgxpool.Pool) *OnCallSchedulesRepository {
+	log.Debug("Initializing the on-call schedules repository")
+	return &OnCallSchedulesRepository{dbPool: dbPool}
+}
+
+// CreateOnCallSchedule inserts a new on-call schedule into the database
+func (sr *OnCallSchedulesRepository) CreateOnCallSchedule(ctx context.Context, schedule *models.OnCallSchedule) error {
+	log.WithFields(log.Fields{
+		"id": schedule.ID,
+	}).Debug("Creating a new on-call schedule in the database")
+
+	if _, err := sr.dbPool.Exec(
+		ctx,
+		insertOnCallScheduleQuery,
+		schedule.ID, schedule.Name, schedule.Description, schedule.Timezone, schedule.Rotation, schedule.RotationStart,
+		schedule.UserIDs, schedule.CreatedAt, schedule.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": schedule.ID,
+	}).Debug("The on-call schedule has been created in the database")
+
+	return nil
+}
+
+// GetOnCallSchedule retrieves an on-call schedule by ID from the database, or ErrNotFound if there is no such schedule
+func (sr *OnCallSchedulesRepository) GetOnCallSchedule(ctx context.Context, id string) (*models.OnCallSchedule, error) {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Retrieving an on-call schedule from the database")
+
+	schedule := &models.OnCallSchedule{}
+	if err := sr.dbPool.QueryRow(ctx, selectOnCallScheduleQuery, id).Scan(
+		&schedule.ID, &schedule.Name, &schedule.Description, &schedule.Timezone, &schedule.Rotation, &schedule.RotationStart,
+		&schedule.UserIDs, &schedule.CreatedAt, &schedule.UpdatedAt,
+	); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("On-call schedule successfully retrieved from the database")
+
+	return schedule, nil
+}
+
+// GetOnCallScheduleByName retrieves an on-call schedule by name from the database, or ErrNotFound if there is no such schedule
+func (sr *OnCallSchedulesRepository) GetOnCallScheduleByName(ctx context.Context, name string) (*models.OnCallSchedule, error) {
+	log.WithFields(log.Fields{
+		"name": name,
+	}).Debug("Retrieving an on-call schedule by name from the database")
+
+	schedule := &models.OnCallSchedule{}
+	if err := sr.dbPool.QueryRow(ctx, selectOnCallScheduleByNameQuery, name).Scan(
+		&schedule.ID, &schedule.Name, &schedule.Description, &schedule.Timezone, &schedule.Rotation, &schedule.RotationStart,
+		&schedule.UserIDs, &schedule.CreatedAt, &schedule.UpdatedAt,
+	); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	log.WithFields(log.Fields{
+		"name": name,
+	}).Debug("On-call schedule successfully retrieved from the database")
+
+	return schedule, nil
+}
+
+// GetOnCallSchedules retrieves all on-call schedules from the database
+func (sr *OnCallSchedulesRepository) GetOnCallSchedules(ctx context.Context) ([]*models.OnCallSchedule, error) {
+	log.Debug("Retrieving on-call schedules from the database")
+
+	rows, err := sr.dbPool.Query(ctx, selectOnCallSchedulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []*models.OnCallSchedule{}
+	for rows.Next() {
+		schedule := &models.OnCallSchedule{}
+		if err := rows.Scan(
+			&schedule.ID, &schedule.Name, &schedule.Description, &schedule.Timezone, &schedule.Rotation, &schedule.RotationStart,
+			&schedule.UserIDs, &schedule.CreatedAt, &schedule.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"schedulesCount": len(schedules),
+	}).Debug("On-call schedules successfully retrieved from the database")
+
+	return schedules, nil
+}
+
+// UpdateOnCallSchedule updates an existing on-call schedule in the database
+func (sr *OnCallSchedulesRepository) UpdateOnCallSchedule(ctx context.Context, schedule *models.OnCallSchedule) error {
+	log.WithFields(log.Fields{
+		"id": schedule.ID,
+	}).Debug("Updating an on-call schedule in the database")
+
+	if _, err := sr.dbPool.Exec(
+		ctx,
+		updateOnCallScheduleQuery,
+		schedule.Name, schedule.Description, schedule.Timezone, schedule.Rotation, schedule.RotationStart,
+		schedule.UserIDs, schedule.UpdatedAt, schedule.ID,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": schedule.ID,
+	}).Debug("The on-call schedule has been updated in the database")
+
+	return nil
+}
+
+// DeleteOnCallSchedule deletes an on-call schedule by ID from the database
+func (sr *OnCallSchedulesRepository) DeleteOnCallSchedule(ctx context.Context, id string) error {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Deleting an on-call schedule from the database")
+
+	if _, err := sr.dbPool.Exec(ctx, deleteOnCallScheduleQuery, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The on-call schedule has been deleted from the database")
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for CRUD operations on on-call users
+const (
+	// Query for inserting a new on-call user into the database
+	insertOnCallUserQuery = `
+		INSERT INTO a2i_oncall_users (id, name, telegram_id, email, ldap_login, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	// Query for selecting an on-call user by ID
+	selectOnCallUserQuery = `
+		SELECT id, name, telegram_id, email, ldap_login, created_at, updated_at
+		FROM a2i_oncall_users
+		WHERE id = $1
+	`
+
+	// Query for selecting all on-call users
+	selectOnCallUsersQuery = `
+		SELECT id, name, telegram_id, email, ldap_login, created_at, updated_at
+		FROM a2i_oncall_users
+		ORDER BY name
+	`
+
+	// Query for updating an existing on-call user
+	updateOnCallUserQuery = `
+		UPDATE a2i_oncall_users
+		SET name = $1, telegram_id = $2, email = $3, ldap_login = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	// Query for deleting an on-call user by ID
+	deleteOnCallUserQuery = `
+		DELETE FROM a2i_oncall_users
+		WHERE id = $1
+	`
+)
+
+// OnCallUsersRepository struct defines the structure for the repository
+type OnCallUsersRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for OnCallUsersRepository
+func NewOnCallUsersRepository(dbPool *pgxpool.Pool) *OnCallUsersRepository {
+	log.Debug("Initializing the on-call users repository")
+	return &OnCallUsersRepository{dbPool: dbPool}
+}
+
+// CreateOnCallUser inserts a new on-call user into the database
+func (ur *OnCallUsersRepository) CreateOnCallUser(ctx context.Context, user *models.OnCallUser) error {
+	log.WithFields(log.Fields{
+		"id": user.ID,
+	}).Debug("Creating a new on-call user in the database")
+
+	if _, err := ur.dbPool.Exec(
+		ctx,
+		insertOnCallUserQuery,
+		user.ID, user.Name, user.TelegramID, user.Email, user.LDAPLogin, user.CreatedAt, user.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": user.ID,
+	}).Debug("The on-call user has been created in the database")
+
+	return nil
+}
+
+// GetOnCallUser retrieves an on-call user by ID from the database, or ErrNotFound if there is no such user
+func (ur *OnCallUsersRepository) GetOnCallUser(ctx context.Context, id string) (*models.OnCallUser, error) {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Retrieving an on-call user from the database")
+
+	user := &models.OnCallUser{}
+	if err := ur.dbPool.QueryRow(ctx, selectOnCallUserQuery, id).Scan(
+		&user.ID, &user.Name, &user.TelegramID, &user.Email, &user.LDAPLogin, &user.CreatedAt, &user.UpdatedAt,
+	); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("On-call user successfully retrieved from the database")
+
+	return user, nil
+}
+
+// GetOnCallUsers retrieves all on-call users from the database
+func (ur *OnCallUsersRepository) GetOnCallUsers(ctx context.Context) ([]*models.OnCallUser, error) {
+	log.Debug("Retrieving on-call users from the database")
+
+	rows, err := ur.dbPool.Query(ctx, selectOnCallUsersQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.OnCallUser{}
+	for rows.Next() {
+		user := &models.OnCallUser{}
+		if err := rows.Scan(
+			&user.ID, &user.Name, &user.TelegramID, &user.Email, &user.LDAPLogin, &user.CreatedAt, &user.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"usersCount": len(users),
+	}).Debug("On-call users successfully retrieved from the database")
+
+	return users, nil
+}
+
+// UpdateOnCallUser updates an existing on-call user in the database
+func (ur *OnCallUsersRepository) UpdateOnCallUser(ctx context.Context, user *models.OnCallUser) error {
+	log.WithFields(log.Fields{
+		"id": user.ID,
+	}).Debug("Updating an on-call user in the database")
+
+	if _, err := ur.dbPool.Exec(
+		ctx,
+		updateOnCallUserQuery,
+		user.Name, user.TelegramID, user.Email, user.LDAPLogin, user.UpdatedAt, user.ID,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": user.ID,
+	}).Debug("The on-call user has been updated in the database")
+
+	return nil
+}
+
+// DeleteOnCallUser deletes an on-call user by ID from the database
+func (ur *OnCallUsersRepository) DeleteOnCallUser(ctx context.Context, id string) error {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Deleting an on-call user from the database")
+
+	if _, err := ur.dbPool.Exec(ctx, deleteOnCallUserQuery, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The on-call user has been deleted from the database")
+
+	return nil
+}
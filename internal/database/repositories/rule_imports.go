@@ -0,0 +1,155 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for importing rules
+const (
+	// Query for locking the rules table against concurrent changes, still allowing reads, for the rest of the transaction
+	lockRulesTableQuery = `
+		LOCK TABLE a2i_rules IN EXCLUSIVE MODE
+	`
+
+	// Query for selecting all rules ordered by ID
+	selectAllRulesQuery = `
+		SELECT ` + ruleColumns + `
+		FROM a2i_rules
+		ORDER BY id
+	`
+)
+
+// GetAllRules retrieves all rules ordered by ID, e.g. for exporting them
+func (rr *RulesRepository) GetAllRules(ctx context.Context) ([]*models.Rule, error) {
+	log.Debug("Retrieving all rules from the database")
+
+	rows, err := rr.dbPool.Query(ctx, selectAllRulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	rules, err := collectRules(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"rulesCount": len(rules),
+	}).Debug("All rules successfully retrieved from the database")
+
+	return rules, nil
+}
+
+// ImportRules brings the stored rules in line with the validated rules document in a single transaction:
+// the rules of the document are created or updated by their IDs, keeping the mute state of the existing ones,
+// and, if prune is set, the rules absent from the document are deleted. Importing the same document again changes nothing.
+// With dryRun the changes are only planned and rolled back. The rules handler caches are refreshed by the notifications
+// of the changed rules, which are only delivered once the transaction is committed.
+func (rr *RulesRepository) ImportRules(ctx context.Context, document *models.RulesDocument, prune, dryRun bool) (*models.RulesImportResult, error) {
+	log.WithFields(log.Fields{
+		"rulesCount": len(document.Rules),
+		"prune":      prune,
+		"dryRun":     dryRun,
+	}).Debug("Importing rules into the database")
+
+	tx, err := rr.dbPool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting the transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, lockRulesTableQuery); err != nil {
+		return nil, fmt.Errorf("error locking the rules: %w", err)
+	}
+	rows, err := tx.Query(ctx, selectAllRulesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	storedRules, err := collectRules(rows)
+	if err != nil {
+		return nil, err
+	}
+	storedRulesByID := make(map[string]*models.Rule, len(storedRules))
+	for _, rule := range storedRules {
+		storedRulesByID[rule.ID] = rule
+	}
+
+	currentTimeUTC := time.Now().UTC()
+	result := &models.RulesImportResult{DryRun: dryRun, Changes: []*models.RulesImportChange{}}
+	importedIDs := make(map[string]struct{}, len(document.Rules))
+	for _, spec := range document.Rules {
+		importedIDs[spec.ID] = struct{}{}
+
+		rule, ok := storedRulesByID[spec.ID]
+		if !ok {
+			rule = &models.Rule{Version: 1, CreatedAt: currentTimeUTC, UpdatedAt: currentTimeUTC}
+			spec.ApplyTo(rule)
+			if _, err := tx.Exec(ctx, insertRuleQuery, ruleInsertArgs(rule)...); err != nil {
+				return nil, fmt.Errorf("error creating rule %s: %w", rule.ID, err)
+			}
+			result.Changes = append(result.Changes, &models.RulesImportChange{ID: rule.ID, Action: models.CreateRulesImportAction})
+			continue
+		}
+
+		fields := spec.ChangedFields(rule)
+		if len(fields) == 0 {
+			result.Unchanged++
+			continue
+		}
+		spec.ApplyTo(rule)
+		rule.UpdatedAt = currentTimeUTC
+		if err := tx.QueryRow(ctx, updateRuleQuery, ruleUpdateArgs(rule)...).Scan(&rule.Version); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = ErrVersionConflict
+			}
+			return nil, fmt.Errorf("error updating rule %s: %w", rule.ID, err)
+		}
+		result.Changes = append(result.Changes, &models.RulesImportChange{ID: rule.ID, Action: models.UpdateRulesImportAction, Fields: fields})
+	}
+
+	if prune {
+		for _, rule := range storedRules {
+			if _, ok := importedIDs[rule.ID]; ok {
+				continue
+			}
+			if _, err := tx.Exec(ctx, deleteRuleQuery, rule.ID); err != nil {
+				return nil, fmt.Errorf("error deleting rule %s: %w", rule.ID, err)
+			}
+			result.Changes = append(result.Changes, &models.RulesImportChange{ID: rule.ID, Action: models.DeleteRulesImportAction})
+		}
+	}
+
+	// A dry run leaves the rules intact, the deferred rollback discards the changes.
+	if !dryRun {
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("error committing the transaction: %w", err)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"changesCount": len(result.Changes),
+		"unchanged":    result.Unchanged,
+		"dryRun":       dryRun,
+	}).Debug("The rules have been imported into the database")
+
+	return result, nil
+}
+
+// collectRules scans all rows of a rules query and closes them.
+func collectRules(rows pgx.Rows) ([]*models.Rule, error) {
+	rules, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.Rule, error) {
+		rule := &models.Rule{}
+		return rule, row.Scan(ruleScanDest(rule)...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning the rows: %w", err)
+	}
+	return rules, nil
+}
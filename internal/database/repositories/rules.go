@@ -2,11 +2,13 @@ package repositories // dnywonnt.me/alerts2incidents/internal/database/repositor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	log "github.com/sirupsen/logrus"
@@ -21,32 +23,48 @@ const (
 			incident_life_time, incident_finishing_interval, set_incident_summary, set_incident_description, set_incident_departament, 
 			set_incident_client_affect, set_incident_is_manageable, set_incident_sale_channels, 
 			set_incident_trouble_services, set_incident_failure_type, set_incident_labels, 
-			set_incident_is_downtime, created_at, updated_at
+			set_incident_is_downtime, mute_until, mute_reason, muted_by, flapping_window, flapping_threshold,
+			set_incident_severity, derive_incident_severity, version, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27)
+	`
+
+	// ruleColumns lists all columns of a rule in the order they are scanned in, see ruleScanDest
+	ruleColumns = `
+		id, is_muted, description, alerts_summary_conditions, alerts_activity_interval_conditions,
+		incident_life_time, incident_finishing_interval, set_incident_summary, set_incident_description, set_incident_departament,
+		set_incident_client_affect, set_incident_is_manageable, set_incident_sale_channels, set_incident_trouble_services,
+		set_incident_failure_type, set_incident_labels, set_incident_is_downtime, mute_until, mute_reason, muted_by,
+		flapping_window, flapping_threshold, set_incident_severity, derive_incident_severity, version, created_at, updated_at
 	`
 
 	// Query for selecting a rule by ID
 	selectRuleQuery = `
-		SELECT id, is_muted, description, alerts_summary_conditions, alerts_activity_interval_conditions, 
-			incident_life_time, incident_finishing_interval, set_incident_summary, set_incident_description, set_incident_departament, 
-			set_incident_client_affect, set_incident_is_manageable, set_incident_sale_channels, 
-			set_incident_trouble_services, set_incident_failure_type, set_incident_labels, 
-			set_incident_is_downtime, created_at, updated_at
+		SELECT ` + ruleColumns + `
 		FROM a2i_rules
 		WHERE id = $1
 	`
 
-	// Query for updating an existing rule
+	// Query for updating an existing rule if it still has the version the update is based on; the version is incremented
 	updateRuleQuery = `
 		UPDATE a2i_rules
 		SET is_muted = $1, description = $2, alerts_summary_conditions = $3, alerts_activity_interval_conditions = $4, 
 			incident_life_time = $5, incident_finishing_interval = $6, set_incident_summary = $7, set_incident_description = $8, 
 			set_incident_departament = $9, set_incident_client_affect = $10, set_incident_is_manageable = $11, 
 			set_incident_sale_channels = $12, set_incident_trouble_services = $13, set_incident_failure_type = $14, 
-			set_incident_labels = $15, set_incident_is_downtime = $16, 
-			updated_at = $17
-		WHERE id = $18
+			set_incident_labels = $15, set_incident_is_downtime = $16, mute_until = $17, mute_reason = $18, muted_by = $19,
+			flapping_window = $20, flapping_threshold = $21, set_incident_severity = $22, derive_incident_severity = $23,
+			updated_at = $24, version = version + 1
+		WHERE id = $25 AND version = $26
+		RETURNING version
+	`
+
+	// Query for unmuting the rules whose mutes have expired; the trigger notifies about every unmuted rule
+	unmuteExpiredRulesQuery = `
+		UPDATE a2i_rules
+		SET is_muted = false, mute_until = NULL, mute_reason = '', muted_by = '', updated_at = $1, version = version + 1
+		WHERE is_muted AND mute_until <= $1
+		RETURNING id
 	`
 
 	// Query for deleting a rule by ID
@@ -73,14 +91,7 @@ func (rr *RulesRepository) CreateRule(ctx context.Context, rule *models.Rule) er
 		"id": rule.ID,
 	}).Debug("Creating a new rule in the database")
 
-	if _, err := rr.dbPool.Exec(
-		ctx,
-		insertRuleQuery,
-		rule.ID, rule.IsMuted, rule.Description, rule.AlertsSummaryConditions, rule.AlertsActivityIntervalConditions,
-		rule.IncidentLifeTime, rule.IncidentFinishingInterval, rule.SetIncidentSummary, rule.SetIncidentDescription, rule.SetIncidentDepartament,
-		rule.SetIncidentClientAffect, rule.SetIncidentIsManageable, rule.SetIncidentSaleChannels, rule.SetIncidentTroubleServices,
-		rule.SetIncidentFailureType, rule.SetIncidentLabels, rule.SetIncidentIsDowntime, rule.CreatedAt, rule.UpdatedAt,
-	); err != nil {
+	if _, err := rr.dbPool.Exec(ctx, insertRuleQuery, ruleInsertArgs(rule)...); err != nil {
 		return fmt.Errorf("error executing the query: %w", err)
 	}
 
@@ -91,20 +102,15 @@ func (rr *RulesRepository) CreateRule(ctx context.Context, rule *models.Rule) er
 	return nil
 }
 
-// GetRule retrieves a rule by ID from the database
+// GetRule retrieves a rule by ID from the database, or ErrNotFound if there is no such rule
 func (rr *RulesRepository) GetRule(ctx context.Context, id string) (*models.Rule, error) {
 	log.WithFields(log.Fields{
 		"id": id,
 	}).Debug("Retrieving a rule from the database")
 
 	rule := &models.Rule{}
-	if err := rr.dbPool.QueryRow(ctx, selectRuleQuery, id).Scan(
-		&rule.ID, &rule.IsMuted, &rule.Description, &rule.AlertsSummaryConditions, &rule.AlertsActivityIntervalConditions,
-		&rule.IncidentLifeTime, &rule.IncidentFinishingInterval, &rule.SetIncidentSummary, &rule.SetIncidentDescription, &rule.SetIncidentDepartament,
-		&rule.SetIncidentClientAffect, &rule.SetIncidentIsManageable, &rule.SetIncidentSaleChannels, &rule.SetIncidentTroubleServices,
-		&rule.SetIncidentFailureType, &rule.SetIncidentLabels, &rule.SetIncidentIsDowntime, &rule.CreatedAt, &rule.UpdatedAt,
-	); err != nil {
-		return nil, fmt.Errorf("error executing the query: %w", err)
+	if err := rr.dbPool.QueryRow(ctx, selectRuleQuery, id).Scan(ruleScanDest(rule)...); err != nil {
+		return nil, selectRecordError(err)
 	}
 
 	log.WithFields(log.Fields{
@@ -114,20 +120,18 @@ func (rr *RulesRepository) GetRule(ctx context.Context, id string) (*models.Rule
 	return rule, nil
 }
 
-// UpdateRule updates an existing rule in the database
+// UpdateRule updates an existing rule in the database.
+// The update only succeeds if the rule still has the version of the model, otherwise ErrVersionConflict is returned;
+// on success the model gets the new version
 func (rr *RulesRepository) UpdateRule(ctx context.Context, rule *models.Rule) error {
 	log.WithFields(log.Fields{
 		"id": rule.ID,
 	}).Debug("Updating a rule in the database")
 
-	if _, err := rr.dbPool.Exec(
-		ctx,
-		updateRuleQuery,
-		rule.IsMuted, rule.Description, rule.AlertsSummaryConditions, rule.AlertsActivityIntervalConditions,
-		rule.IncidentLifeTime, rule.IncidentFinishingInterval, rule.SetIncidentSummary, rule.SetIncidentDescription, rule.SetIncidentDepartament,
-		rule.SetIncidentClientAffect, rule.SetIncidentIsManageable, rule.SetIncidentSaleChannels, rule.SetIncidentTroubleServices,
-		rule.SetIncidentFailureType, rule.SetIncidentLabels, rule.SetIncidentIsDowntime, rule.UpdatedAt, rule.ID,
-	); err != nil {
+	if err := rr.dbPool.QueryRow(ctx, updateRuleQuery, ruleUpdateArgs(rule)...).Scan(&rule.Version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVersionConflict
+		}
 		return fmt.Errorf("error executing the query: %w", err)
 	}
 
@@ -155,6 +159,38 @@ func (rr *RulesRepository) DeleteRule(ctx context.Context, id string) error {
 	return nil
 }
 
+// UnmuteExpiredRules unmutes the rules whose mutes have expired by the given time and returns their IDs
+func (rr *RulesRepository) UnmuteExpiredRules(ctx context.Context, at time.Time) ([]string, error) {
+	log.WithFields(log.Fields{
+		"at": at,
+	}).Debug("Unmuting the rules with expired mutes in the database")
+
+	rows, err := rr.dbPool.Query(ctx, unmuteExpiredRulesQuery, at)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		id := ""
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"rulesCount": len(ids),
+	}).Debug("The rules with expired mutes have been unmuted in the database")
+
+	return ids, nil
+}
+
 // GetRules retrieves rules with filters, sorting, and pagination
 func (rr *RulesRepository) GetRules(ctx context.Context, filterBy map[string]interface{}, sortBy string, sortOrder string, pageNum int, pageSize int, startTime time.Time, endTime time.Time) ([]*models.Rule, error) {
 	log.WithFields(log.Fields{
@@ -178,12 +214,7 @@ func (rr *RulesRepository) GetRules(ctx context.Context, filterBy map[string]int
 	rules := []*models.Rule{}
 	for rows.Next() {
 		rule := &models.Rule{}
-		if err := rows.Scan(
-			&rule.ID, &rule.IsMuted, &rule.Description, &rule.AlertsSummaryConditions, &rule.AlertsActivityIntervalConditions,
-			&rule.IncidentLifeTime, &rule.IncidentFinishingInterval, &rule.SetIncidentSummary, &rule.SetIncidentDescription, &rule.SetIncidentDepartament,
-			&rule.SetIncidentClientAffect, &rule.SetIncidentIsManageable, &rule.SetIncidentSaleChannels, &rule.SetIncidentTroubleServices,
-			&rule.SetIncidentFailureType, &rule.SetIncidentLabels, &rule.SetIncidentIsDowntime, &rule.CreatedAt, &rule.UpdatedAt,
-		); err != nil {
+		if err := rows.Scan(ruleScanDest(rule)...); err != nil {
 			return nil, fmt.Errorf("error scanning the row: %w", err)
 		}
 		rules = append(rules, rule)
@@ -222,13 +253,42 @@ func (rr *RulesRepository) GetTotalRules(ctx context.Context, filterBy map[strin
 	return totalRules, nil
 }
 
+// ruleScanDest returns the destinations for scanning all columns of a rule, in the order of ruleColumns
+func ruleScanDest(rule *models.Rule) []interface{} {
+	return []interface{}{
+		&rule.ID, &rule.IsMuted, &rule.Description, &rule.AlertsSummaryConditions, &rule.AlertsActivityIntervalConditions,
+		&rule.IncidentLifeTime, &rule.IncidentFinishingInterval, &rule.SetIncidentSummary, &rule.SetIncidentDescription, &rule.SetIncidentDepartament,
+		&rule.SetIncidentClientAffect, &rule.SetIncidentIsManageable, &rule.SetIncidentSaleChannels, &rule.SetIncidentTroubleServices,
+		&rule.SetIncidentFailureType, &rule.SetIncidentLabels, &rule.SetIncidentIsDowntime, &rule.MuteUntil, &rule.MuteReason, &rule.MutedBy,
+		&rule.FlappingWindow, &rule.FlappingThreshold, &rule.SetIncidentSeverity, &rule.DeriveIncidentSeverity, &rule.Version, &rule.CreatedAt, &rule.UpdatedAt,
+	}
+}
+
+// ruleInsertArgs returns the values of all columns of a rule for inserting it, in the order of ruleColumns
+func ruleInsertArgs(rule *models.Rule) []interface{} {
+	return []interface{}{
+		rule.ID, rule.IsMuted, rule.Description, rule.AlertsSummaryConditions, rule.AlertsActivityIntervalConditions,
+		rule.IncidentLifeTime, rule.IncidentFinishingInterval, rule.SetIncidentSummary, rule.SetIncidentDescription, rule.SetIncidentDepartament,
+		rule.SetIncidentClientAffect, rule.SetIncidentIsManageable, rule.SetIncidentSaleChannels, rule.SetIncidentTroubleServices,
+		rule.SetIncidentFailureType, rule.SetIncidentLabels, rule.SetIncidentIsDowntime, rule.MuteUntil, rule.MuteReason, rule.MutedBy,
+		rule.FlappingWindow, rule.FlappingThreshold, rule.SetIncidentSeverity, rule.DeriveIncidentSeverity, rule.Version, rule.CreatedAt, rule.UpdatedAt,
+	}
+}
+
+// ruleUpdateArgs returns the arguments of updateRuleQuery for updating a rule of the version of the model
+func ruleUpdateArgs(rule *models.Rule) []interface{} {
+	return []interface{}{
+		rule.IsMuted, rule.Description, rule.AlertsSummaryConditions, rule.AlertsActivityIntervalConditions,
+		rule.IncidentLifeTime, rule.IncidentFinishingInterval, rule.SetIncidentSummary, rule.SetIncidentDescription, rule.SetIncidentDepartament,
+		rule.SetIncidentClientAffect, rule.SetIncidentIsManageable, rule.SetIncidentSaleChannels, rule.SetIncidentTroubleServices,
+		rule.SetIncidentFailureType, rule.SetIncidentLabels, rule.SetIncidentIsDowntime, rule.MuteUntil, rule.MuteReason, rule.MutedBy,
+		rule.FlappingWindow, rule.FlappingThreshold, rule.SetIncidentSeverity, rule.DeriveIncidentSeverity, rule.UpdatedAt, rule.ID, rule.Version,
+	}
+}
+
 // buildGetQueryForRules builds a dynamic query for retrieving rules based on filters and pagination
 func buildGetQueryForRules(filterBy map[string]interface{}, sortBy string, sortOrder string, pageNum int, pageSize int, startTime time.Time, endTime time.Time) (string, []interface{}) {
-	baseQuery := `SELECT id, is_muted, description, alerts_summary_conditions, alerts_activity_interval_conditions,
-		incident_life_time, incident_finishing_interval, set_incident_summary, set_incident_description, set_incident_departament,
-		set_incident_client_affect, set_incident_is_manageable, set_incident_sale_channels, set_incident_trouble_services,
-		set_incident_failure_type, set_incident_labels, set_incident_is_downtime,
-		created_at, updated_at FROM a2i_rules WHERE 1 = 1`
+	baseQuery := `SELECT ` + ruleColumns + ` FROM a2i_rules WHERE 1 = 1`
 	args := make([]interface{}, 0)
 	argId := 1
 
@@ -236,6 +296,12 @@ func buildGetQueryForRules(filterBy map[string]interface{}, sortBy string, sortO
 		if field == "created_at" || field == "updated_at" {
 			continue
 		}
+		if field == "mute_expires_before" {
+			baseQuery += fmt.Sprintf(" AND is_muted AND mute_until <= $%d", argId)
+			args = append(args, value)
+			argId++
+			continue
+		}
 		switch v := value.(type) {
 		case []interface{}:
 			placeholders := make([]string, len(v))
@@ -262,9 +328,12 @@ func buildGetQueryForRules(filterBy map[string]interface{}, sortBy string, sortO
 		baseQuery += fmt.Sprintf(" ORDER BY %s %s", sortBy, sortOrder)
 	}
 
-	offset := (pageNum - 1) * pageSize
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argId, argId+1)
-	args = append(args, pageSize, offset)
+	// A non-positive page size selects all matching rules
+	if pageSize > 0 {
+		offset := (pageNum - 1) * pageSize
+		baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argId, argId+1)
+		args = append(args, pageSize, offset)
+	}
 
 	return baseQuery, args
 }
@@ -279,6 +348,12 @@ func buildCountQueryForRules(filterBy map[string]interface{}, startTime, endTime
 		if field == "created_at" || field == "updated_at" {
 			continue
 		}
+		if field == "mute_expires_before" {
+			baseQuery += fmt.Sprintf(" AND is_muted AND mute_until <= $%d", argId)
+			args = append(args, value)
+			argId++
+			continue
+		}
 		switch v := value.(type) {
 		case []interface{}:
 			placeholders := make([]string, len(v))
@@ -0,0 +1,268 @@
+package repositories // dnywonnt.me/alerts2incidents/internal/database/repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SQL queries as constants for CRUD operations on service-level objectives
+const (
+	// Query for inserting a new objective into the database
+	insertSLOQuery = `
+		INSERT INTO a2i_slos (id, description, target_type, target, objective, period, burn_alerted_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	// Query for selecting an objective by ID
+	selectSLOQuery = `
+		SELECT id, description, target_type, target, objective, period, burn_alerted_at, created_at, updated_at
+		FROM a2i_slos
+		WHERE id = $1
+	`
+
+	// Query for selecting all objectives
+	selectSLOsQuery = `
+		SELECT id, description, target_type, target, objective, period, burn_alerted_at, created_at, updated_at
+		FROM a2i_slos
+		ORDER BY target_type, target, period
+	`
+
+	// Query for updating the definition of an existing objective; the time of the burn alert is kept
+	updateSLOQuery = `
+		UPDATE a2i_slos
+		SET description = $1, target_type = $2, target = $3, objective = $4, period = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	// Query for recording the time the alert about the burned error budget of an objective was sent at
+	updateSLOBurnAlertedAtQuery = `
+		UPDATE a2i_slos
+		SET burn_alerted_at = $1
+		WHERE id = $2
+	`
+
+	// Query for deleting an objective by ID
+	deleteSLOQuery = `
+		DELETE FROM a2i_slos
+		WHERE id = $1
+	`
+
+	// Query for selecting the downtime intervals of the incidents affecting a target within a period.
+	// The column holding the targets is inserted by the target type; the first argument is the time the actual incidents last until.
+	// The incidents merged into other ones are left out, since their primary incidents cover them
+	selectSLODowntimeIntervalsQuery = `
+		SELECT from_at, CASE WHEN status = 'actual' THEN $1 ELSE to_at END
+		FROM a2i_incidents
+		WHERE is_downtime AND merged_into_id IS NULL AND $2 = ANY(%s)
+		  AND from_at < $3 AND (status = 'actual' OR to_at > $4)
+		ORDER BY from_at
+	`
+)
+
+// sloTargetColumns maps the target types of the objectives to the incident columns holding the targets
+var sloTargetColumns = map[string]string{
+	models.TroubleServiceSLOTarget: "trouble_services",
+	models.SaleChannelSLOTarget:    "sale_channels",
+}
+
+// SLOsRepository struct defines the structure for the repository
+type SLOsRepository struct {
+	dbPool *pgxpool.Pool // Database pool for PostgreSQL
+}
+
+// Constructor for SLOsRepository
+func NewSLOsRepository(dbPool *pgxpool.Pool) *SLOsRepository {
+	log.Debug("Initializing the SLOs repository")
+	return &SLOsRepository{dbPool: dbPool}
+}
+
+// CreateSLO inserts a new objective into the database
+func (sr *SLOsRepository) CreateSLO(ctx context.Context, slo *models.SLO) error {
+	log.WithFields(log.Fields{
+		"id": slo.ID,
+	}).Debug("Creating a new SLO in the database")
+
+	if _, err := sr.dbPool.Exec(
+		ctx,
+		insertSLOQuery,
+		slo.ID, slo.Description, slo.TargetType, slo.Target, slo.Objective, slo.Period, slo.BurnAlertedAt, slo.CreatedAt, slo.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": slo.ID,
+	}).Debug("The SLO has been created in the database")
+
+	return nil
+}
+
+// GetSLO retrieves an objective by ID from the database, or ErrNotFound if there is no such objective
+func (sr *SLOsRepository) GetSLO(ctx context.Context, id string) (*models.SLO, error) {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Retrieving an SLO from the database")
+
+	slo := &models.SLO{}
+	if err := sr.dbPool.QueryRow(ctx, selectSLOQuery, id).Scan(
+		&slo.ID, &slo.Description, &slo.TargetType, &slo.Target, &slo.Objective, &slo.Period, &slo.BurnAlertedAt, &slo.CreatedAt, &slo.UpdatedAt,
+	); err != nil {
+		return nil, selectRecordError(err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("SLO successfully retrieved from the database")
+
+	return slo, nil
+}
+
+// GetSLOs retrieves all objectives from the database
+func (sr *SLOsRepository) GetSLOs(ctx context.Context) ([]*models.SLO, error) {
+	log.Debug("Retrieving SLOs from the database")
+
+	rows, err := sr.dbPool.Query(ctx, selectSLOsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	slos := []*models.SLO{}
+	for rows.Next() {
+		slo := &models.SLO{}
+		if err := rows.Scan(
+			&slo.ID, &slo.Description, &slo.TargetType, &slo.Target, &slo.Objective, &slo.Period, &slo.BurnAlertedAt, &slo.CreatedAt, &slo.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		slos = append(slos, slo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"slosCount": len(slos),
+	}).Debug("SLOs successfully retrieved from the database")
+
+	return slos, nil
+}
+
+// UpdateSLO updates the definition of an existing objective in the database
+func (sr *SLOsRepository) UpdateSLO(ctx context.Context, slo *models.SLO) error {
+	log.WithFields(log.Fields{
+		"id": slo.ID,
+	}).Debug("Updating an SLO in the database")
+
+	if _, err := sr.dbPool.Exec(
+		ctx,
+		updateSLOQuery,
+		slo.Description, slo.TargetType, slo.Target, slo.Objective, slo.Period, slo.UpdatedAt, slo.ID,
+	); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": slo.ID,
+	}).Debug("The SLO has been updated in the database")
+
+	return nil
+}
+
+// SetSLOBurnAlertedAt records the time the alert about the burned error budget of an objective was sent at
+func (sr *SLOsRepository) SetSLOBurnAlertedAt(ctx context.Context, id string, alertedAt time.Time) error {
+	log.WithFields(log.Fields{
+		"id":        id,
+		"alertedAt": alertedAt,
+	}).Debug("Recording the burn alert of an SLO in the database")
+
+	if _, err := sr.dbPool.Exec(ctx, updateSLOBurnAlertedAtQuery, alertedAt, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSLO deletes an objective by ID from the database
+func (sr *SLOsRepository) DeleteSLO(ctx context.Context, id string) error {
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("Deleting an SLO from the database")
+
+	if _, err := sr.dbPool.Exec(ctx, deleteSLOQuery, id); err != nil {
+		return fmt.Errorf("error executing the query: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"id": id,
+	}).Debug("The SLO has been deleted from the database")
+
+	return nil
+}
+
+// GetSLOStatus computes the consumption of the error budget of the objective for its period containing the given time
+// from the durations of the downtime incidents affecting its target
+func (sr *SLOsRepository) GetSLOStatus(ctx context.Context, slo *models.SLO, at time.Time) (*models.SLOStatus, error) {
+	log.WithFields(log.Fields{
+		"id": slo.ID,
+		"at": at,
+	}).Debug("Computing the status of an SLO")
+
+	column, ok := sloTargetColumns[slo.TargetType]
+	if !ok {
+		return nil, fmt.Errorf("unknown SLO target type: %s", slo.TargetType)
+	}
+
+	at = at.UTC()
+	periodFromAt, periodToAt := slo.PeriodAt(at)
+	if at.Before(periodToAt) {
+		periodToAt = at
+	}
+
+	rows, err := sr.dbPool.Query(ctx, fmt.Sprintf(selectSLODowntimeIntervalsQuery, column), at, slo.Target, periodToAt, periodFromAt)
+	if err != nil {
+		return nil, fmt.Errorf("error executing the query: %w", err)
+	}
+	defer rows.Close()
+
+	intervals := []models.DowntimeInterval{}
+	for rows.Next() {
+		interval := models.DowntimeInterval{}
+		if err := rows.Scan(&interval.FromAt, &interval.ToAt); err != nil {
+			return nil, fmt.Errorf("error scanning the row: %w", err)
+		}
+		intervals = append(intervals, interval)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rows: %w", err)
+	}
+
+	return models.NewSLOStatus(slo, at, intervals), nil
+}
+
+// GetSLOStatuses computes the statuses of all objectives for their periods containing the given time
+func (sr *SLOsRepository) GetSLOStatuses(ctx context.Context, at time.Time) ([]*models.SLOStatus, error) {
+	slos, err := sr.GetSLOs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]*models.SLOStatus, 0, len(slos))
+	for _, slo := range slos {
+		status, err := sr.GetSLOStatus(ctx, slo, at)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,56 @@
+package export // dnywonnt.me/alerts2incidents/internal/export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// csvWriter writes a table in the CSV format.
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// newCSVWriter creates a CSV writer; the UTF-8 byte order mark lets spreadsheet applications detect the encoding.
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return nil, fmt.Errorf("error writing the byte order mark: %w", err)
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}, nil
+}
+
+// WriteRow writes the next row of the table.
+func (cw *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case string:
+			// A leading apostrophe makes the spreadsheet applications take the value as text, not as a formula
+			if isFormulaLike(v) {
+				v = "'" + v
+			}
+			record[i] = v
+		case int:
+			record[i] = strconv.Itoa(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return cw.writer.Write(record)
+}
+
+// Flush writes the buffered rows to the underlying stream.
+func (cw *csvWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// Close completes the table.
+func (cw *csvWriter) Close() error {
+	return cw.Flush()
+}
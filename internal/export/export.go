@@ -0,0 +1,57 @@
+package export // dnywonnt.me/alerts2incidents/internal/export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats of the exported tables.
+const (
+	CSVFormat  = "csv"
+	XLSXFormat = "xlsx"
+)
+
+// TableWriter represents an interface for writing a table row by row to a stream, without keeping the rows in memory.
+// The values of the cells are strings, integers, floats or booleans.
+type TableWriter interface {
+	// WriteRow writes the next row of the table.
+	WriteRow(values []interface{}) error
+
+	// Flush writes the buffered rows to the underlying stream.
+	Flush() error
+
+	// Close completes the table; it doesn't close the underlying stream.
+	Close() error
+}
+
+// NewTableWriter creates a table writer of the format writing to the stream.
+func NewTableWriter(format string, w io.Writer) (TableWriter, error) {
+	switch format {
+	case CSVFormat:
+		return newCSVWriter(w)
+	case XLSXFormat:
+		return newXLSXWriter(w, "Incidents")
+	default:
+		return nil, fmt.Errorf("unknown export format: %s", format)
+	}
+}
+
+// formulaPrefixes are the first characters that make spreadsheet applications evaluate a text cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// isFormulaLike reports whether a spreadsheet application would evaluate the text as a formula,
+// e.g. a summary rendered from the content of an alert.
+func isFormulaLike(text string) bool {
+	return text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0]))
+}
+
+// ContentType returns the MIME type of the format.
+func ContentType(format string) string {
+	switch format {
+	case XLSXFormat:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
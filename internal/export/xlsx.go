@@ -0,0 +1,178 @@
+package export // dnywonnt.me/alerts2incidents/internal/export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Parts of the XLSX package besides the worksheet; the worksheet is written row by row as the last part.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+
+	// The second cell format has the quote prefix, so the text of its cells stays text even when edited, see xlsxTextStyle
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" quotePrefix="1"/></cellXfs>` +
+		`</styleSheet>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxTextStyle is the index of the cell format with the quote prefix in xlsxStyles.
+const xlsxTextStyle = 1
+
+// xlsxWriter writes a table as a single-sheet XLSX workbook; strings are stored inline, so no shared strings table is kept in memory.
+type xlsxWriter struct {
+	zipWriter *zip.Writer
+	sheet     *bufio.Writer
+	rowNum    int
+}
+
+// newXLSXWriter creates an XLSX writer with a sheet of the given name.
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	zipWriter := zip.NewWriter(w)
+
+	var escapedName xmlBuffer
+	if err := xml.EscapeText(&escapedName, []byte(sheetName)); err != nil {
+		return nil, fmt.Errorf("error escaping the sheet name: %w", err)
+	}
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapedName)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		partWriter, err := zipWriter.Create(part.name)
+		if err != nil {
+			return nil, fmt.Errorf("error creating the %s part: %w", part.name, err)
+		}
+		if _, err := io.WriteString(partWriter, part.content); err != nil {
+			return nil, fmt.Errorf("error writing the %s part: %w", part.name, err)
+		}
+	}
+
+	sheetWriter, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("error creating the worksheet part: %w", err)
+	}
+	sheet := bufio.NewWriter(sheetWriter)
+	if _, err := sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, fmt.Errorf("error writing the worksheet part: %w", err)
+	}
+
+	return &xlsxWriter{zipWriter: zipWriter, sheet: sheet}, nil
+}
+
+// WriteRow writes the next row of the table.
+func (xw *xlsxWriter) WriteRow(values []interface{}) error {
+	xw.rowNum++
+	row := strconv.Itoa(xw.rowNum)
+
+	fmt.Fprintf(xw.sheet, `<row r="%s">`, row)
+	for i, value := range values {
+		ref := xlsxColumnName(i) + row
+		switch v := value.(type) {
+		case int:
+			fmt.Fprintf(xw.sheet, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(xw.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			boolValue := 0
+			if v {
+				boolValue = 1
+			}
+			fmt.Fprintf(xw.sheet, `<c r="%s" t="b"><v>%d</v></c>`, ref, boolValue)
+		default:
+			// The inline strings aren't evaluated, but the formula-like ones are also quoted against evaluation once edited
+			text := fmt.Sprint(v)
+			if isFormulaLike(text) {
+				fmt.Fprintf(xw.sheet, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">`, ref, xlsxTextStyle)
+			} else {
+				fmt.Fprintf(xw.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			}
+			if err := xml.EscapeText(xw.sheet, []byte(text)); err != nil {
+				return fmt.Errorf("error escaping the cell value: %w", err)
+			}
+			xw.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := xw.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Flush writes the buffered rows to the underlying stream.
+func (xw *xlsxWriter) Flush() error {
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zipWriter.Flush()
+}
+
+// Close completes the worksheet and the package.
+func (xw *xlsxWriter) Close() error {
+	if _, err := xw.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zipWriter.Close()
+}
+
+// xlsxColumnName returns the name of the column with the zero-based index, e.g. "A", "Z", "AA".
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// xmlBuffer collects escaped XML text.
+type xmlBuffer string
+
+// Write appends the bytes to the buffer.
+func (xb *xmlBuffer) Write(p []byte) (int, error) {
+	*xb += xmlBuffer(p)
+	return len(p), nil
+}
@@ -1,9 +1,39 @@
 package models // dnywonnt.me/alerts2incidents/internal/models
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
 
 // Alert represents the data structure for an alert.
 type Alert struct {
-	Summary   string    `json:"summary"`    // Brief description of the alert
-	CreatedAt time.Time `json:"created_at"` // Time when the alert was created
+	Summary     string    `json:"summary"`               // Brief description of the alert
+	Host        string    `json:"host,omitempty"`        // Host the alert is about, if the source reports it
+	Severity    string    `json:"severity,omitempty"`    // Incident severity ("SEV1" to "SEV4") the source severity of the alert corresponds to, if known
+	Fingerprint string    `json:"fingerprint,omitempty"` // Identifier of the alert at its source, e.g. "zabbix:13491"; empty if the source doesn't identify alerts
+	IsResolved  bool      `json:"is_resolved,omitempty"` // Flag indicating if the source reports the alert as resolved
+	CreatedAt   time.Time `json:"created_at"`            // Time when the alert was created
+}
+
+// Identity returns the fingerprint of the alert, or the hash of its summary if the source doesn't identify alerts,
+// so the identity of an alert with a long summary still fits the fingerprint column.
+func (a *Alert) Identity() string {
+	if a.Fingerprint != "" {
+		return a.Fingerprint
+	}
+	hash := sha256.Sum256([]byte(a.Summary))
+	return "summary:" + hex.EncodeToString(hash[:])
+}
+
+// IncidentAlert represents an alert that has contributed to an incident over its lifetime.
+type IncidentAlert struct {
+	IncidentID    string     `json:"incident_id"`    // Incident the alert has contributed to
+	Fingerprint   string     `json:"fingerprint"`    // Identity of the alert, see Alert.Identity
+	Summary       string     `json:"summary"`        // Brief description of the alert when it was last seen
+	CreatedAt     time.Time  `json:"created_at"`     // Time when the alert was created at its source
+	MatchingCount int        `json:"matching_count"` // Number of the matches of the incident the alert has taken part in
+	FirstSeenAt   time.Time  `json:"first_seen_at"`  // Time of the first match the alert has taken part in
+	LastSeenAt    time.Time  `json:"last_seen_at"`   // Time of the last match the alert has taken part in
+	ResolvedAt    *time.Time `json:"resolved_at"`    // Time the alert has been reported resolved by its source at, nil while it's firing
 }
@@ -0,0 +1,137 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// Actions of the bulk operations on incidents and rules.
+const (
+	CloseBulkAction     = "close"      // Close the incidents.
+	ConfirmBulkAction   = "confirm"    // Confirm the incidents.
+	SetLabelsBulkAction = "set_labels" // Replace the labels of the incidents or the incident labels of the rules.
+	MuteBulkAction      = "mute"       // Mute the rules.
+	UnmuteBulkAction    = "unmute"     // Unmute the rules.
+	DeleteBulkAction    = "delete"     // Delete the incidents or the rules.
+)
+
+// Actions of the bulk operations supported by incidents and rules.
+var (
+	IncidentBulkActions = []string{CloseBulkAction, ConfirmBulkAction, SetLabelsBulkAction, DeleteBulkAction}
+	RuleBulkActions     = []string{MuteBulkAction, UnmuteBulkAction, SetLabelsBulkAction, DeleteBulkAction}
+)
+
+// Outcomes of a bulk operation for a single record.
+const (
+	UpdatedBulkItemStatus  = "updated"   // The record has been updated.
+	DeletedBulkItemStatus  = "deleted"   // The record has been deleted.
+	SkippedBulkItemStatus  = "skipped"   // The action doesn't change the record, e.g. the incident is already closed.
+	NotFoundBulkItemStatus = "not_found" // No record has the requested ID.
+	FailedBulkItemStatus   = "failed"    // The action can't be applied to the record; the other records are still processed.
+)
+
+// BulkAction represents an action of a bulk operation with its parameters.
+type BulkAction struct {
+	Name       string     `validate:"required"`                    // Name of the action, e.g. close
+	Labels     []string   `validate:"required_if=Name set_labels"` // Labels to set for the set_labels action
+	MuteUntil  *time.Time `validate:"omitempty"`                   // Time the rules are automatically unmuted at for the mute action
+	MuteReason string     `validate:"omitempty"`                   // Reason the rules are muted for by the mute action
+	MutedBy    string     `validate:"omitempty"`                   // Identifier of the user who mutes the rules by the mute action
+	At         time.Time  `validate:"required"`                    // Time the action is applied at
+}
+
+// Validate runs validation rules on a BulkAction instance; the action must be one of the allowed ones.
+func (a *BulkAction) Validate(allowed []string) error {
+	if !slices.Contains(allowed, a.Name) {
+		return fmt.Errorf("unsupported bulk action %q, expected one of: %s", a.Name, strings.Join(allowed, ", "))
+	}
+	// The mute details only make sense for the mute action.
+	if a.Name != MuteBulkAction && (a.MuteUntil != nil || a.MuteReason != "" || a.MutedBy != "") {
+		return errors.New("mute_until, mute_reason and muted_by can only be set for the mute action")
+	}
+	return utils.ValidateStruct(a)
+}
+
+// BulkItemResult represents the outcome of a bulk operation for a single record.
+type BulkItemResult struct {
+	ID      string `json:"id"`                // Identifier of the record
+	Status  string `json:"status"`            // Outcome: updated, deleted, skipped, not_found or failed
+	Message string `json:"message,omitempty"` // Reason the action has failed
+	Version int    `json:"version,omitempty"` // New version of the updated record
+}
+
+// ApplyBulkAction applies the action of a bulk operation to the incident and validates the result.
+// It reports whether the incident has been changed; deleting isn't applied to the model.
+func (i *Incident) ApplyBulkAction(action *BulkAction) (bool, error) {
+	switch action.Name {
+	case CloseBulkAction:
+		if i.Status == "closed" {
+			return false, nil
+		}
+		// An actual incident ends when it's closed; a finished one keeps its end time.
+		if i.ToAt.IsZero() {
+			i.ToAt = action.At
+		}
+		i.Status = "closed"
+	case ConfirmBulkAction:
+		if i.IsConfirmed {
+			return false, nil
+		}
+		// The confirmation time must be within the incident, so an ended incident is confirmed at its end.
+		i.IsConfirmed = true
+		i.ConfirmationTime = action.At
+		if !i.ToAt.IsZero() && i.ToAt.Before(action.At) {
+			i.ConfirmationTime = i.ToAt
+		}
+	case SetLabelsBulkAction:
+		if slices.Equal(i.Labels, action.Labels) {
+			return false, nil
+		}
+		i.Labels = action.Labels
+	default:
+		return false, fmt.Errorf("unsupported bulk action %q for an incident", action.Name)
+	}
+
+	i.UpdatedAt = action.At
+	return true, i.Validate()
+}
+
+// ApplyBulkAction applies the action of a bulk operation to the rule and validates the result.
+// It reports whether the rule has been changed; deleting isn't applied to the model.
+func (r *Rule) ApplyBulkAction(action *BulkAction) (bool, error) {
+	switch action.Name {
+	case MuteBulkAction:
+		sameMuteUntil := (r.MuteUntil == nil && action.MuteUntil == nil) ||
+			(r.MuteUntil != nil && action.MuteUntil != nil && r.MuteUntil.Equal(*action.MuteUntil))
+		if r.IsMuted && sameMuteUntil && r.MuteReason == action.MuteReason && r.MutedBy == action.MutedBy {
+			return false, nil
+		}
+		r.IsMuted = true
+		r.MuteUntil = action.MuteUntil
+		r.MuteReason = action.MuteReason
+		r.MutedBy = action.MutedBy
+	case UnmuteBulkAction:
+		if !r.IsMuted {
+			return false, nil
+		}
+		r.IsMuted = false
+		r.MuteUntil = nil
+		r.MuteReason = ""
+		r.MutedBy = ""
+	case SetLabelsBulkAction:
+		if slices.Equal(r.SetIncidentLabels, action.Labels) {
+			return false, nil
+		}
+		r.SetIncidentLabels = action.Labels
+	default:
+		return false, fmt.Errorf("unsupported bulk action %q for a rule", action.Name)
+	}
+
+	r.UpdatedAt = action.At
+	return true, r.Validate()
+}
@@ -0,0 +1,47 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// EscalationStep defines who is notified once an auto incident stays unconfirmed for a while.
+type EscalationStep struct {
+	After  time.Duration `json:"after" validate:"required,min=1m"`                          // Time since the incident creation after which the step is executed
+	Chats  []string      `json:"chats" validate:"omitempty"`                                // Telegram chats in the "ChatID[:ThreadID][@Locale]" or "oncall:<schedule>[@Locale]" format
+	Emails []string      `json:"emails" validate:"omitempty,dive,email|startswith=oncall:"` // Email addresses or "oncall:<schedule>" targets
+}
+
+// EscalationPolicy defines the escalation steps for the unconfirmed auto incidents of a rule or a departament.
+// A policy of the rule takes precedence over a policy of the departament of the incident.
+type EscalationPolicy struct {
+	ID          string           `json:"id" validate:"required"`                                                                                       // Unique identifier for the policy
+	Description string           `json:"description" validate:"omitempty"`                                                                             // Optional description of the policy
+	RuleID      *string          `json:"rule_id" validate:"required_without=Departament,excluded_with=Departament"`                                    // Rule the policy is attached to
+	Departament *string          `json:"departament" validate:"required_without=RuleID,omitempty,oneof=internal_digital internal_it external_service"` // Departament the policy is attached to
+	Steps       []EscalationStep `json:"steps" validate:"required,min=1,dive"`                                                                         // Escalation steps in the order of execution
+	CreatedAt   time.Time        `json:"created_at" validate:"required"`                                                                               // Timestamp when the policy was created
+	UpdatedAt   time.Time        `json:"updated_at" validate:"required"`                                                                               // Timestamp when the policy was last updated
+}
+
+// Validate runs validation rules on an EscalationPolicy instance.
+func (ep *EscalationPolicy) Validate() error {
+	if err := utils.ValidateStruct(ep); err != nil {
+		return err
+	}
+
+	// Ensure every step notifies someone and the steps are executed one after another.
+	for i, step := range ep.Steps {
+		if len(step.Chats) == 0 && len(step.Emails) == 0 {
+			return fmt.Errorf("step %d has neither chats nor emails", i+1)
+		}
+		if i > 0 && step.After <= ep.Steps[i-1].After {
+			return errors.New("steps must be ordered by their delay in ascending order")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// Types of the incident events.
+const (
+	EscalationEventType = "escalation" // An escalation step of the escalation policy has been executed for the incident.
+	MergeEventType      = "merge"      // Duplicate incidents have been merged into the incident, or the incident into a primary one.
+	SplitEventType      = "split"      // Alerts of the incident have been split into a new incident.
+)
+
+// IncidentEvent represents an entry of the history of an incident.
+type IncidentEvent struct {
+	ID         string    `json:"id" validate:"required"`                                // Unique identifier for the event
+	IncidentID string    `json:"incident_id" validate:"required"`                       // Identifier of the incident the event belongs to
+	Type       string    `json:"type" validate:"required,oneof=escalation merge split"` // Type of the event
+	Data       string    `json:"data" validate:"required,json"`                         // Details of the event in JSON format
+	Creator    string    `json:"creator" validate:"required"`                           // Creator of the event record
+	CreatedAt  time.Time `json:"created_at" validate:"required"`                        // Timestamp when the event was created
+}
+
+// Validate runs validation rules on an IncidentEvent instance.
+func (ie *IncidentEvent) Validate() error {
+	return utils.ValidateStruct(ie)
+}
@@ -0,0 +1,30 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// Types of the links between incidents; a link reads "incident <type> linked incident".
+const (
+	ChildOfLinkType     = "child_of"     // The incident is a part of the larger linked incident.
+	DuplicateOfLinkType = "duplicate_of" // The incident describes the same outage as the linked one; set by merges.
+	RelatedToLinkType   = "related_to"   // The incidents are related without a hierarchy.
+	SplitFromLinkType   = "split_from"   // The incident has been split from the linked one.
+)
+
+// IncidentLink represents a relation between two incidents.
+type IncidentLink struct {
+	ID               string    `json:"id" validate:"required"`                                                     // Unique identifier for the link
+	IncidentID       string    `json:"incident_id" validate:"required"`                                            // Incident the link starts from
+	LinkedIncidentID string    `json:"linked_incident_id" validate:"required,nefield=IncidentID"`                  // Incident the link points to
+	Type             string    `json:"type" validate:"required,oneof=child_of duplicate_of related_to split_from"` // Type of the link
+	Creator          string    `json:"creator" validate:"required"`                                                // Identifier of the user who created the link
+	CreatedAt        time.Time `json:"created_at" validate:"required"`                                             // Timestamp when the link was created
+}
+
+// Validate runs validation rules on an IncidentLink instance.
+func (il *IncidentLink) Validate() error {
+	return utils.ValidateStruct(il)
+}
@@ -0,0 +1,113 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// incidentTemplateFuncs are the functions available in the incident summary and description templates of rules.
+var incidentTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"formatTime": func(t time.Time, layout string) string {
+		return t.Format(layout)
+	},
+}
+
+// IncidentTemplateData is the data the incident summary and description templates of a rule are evaluated against,
+// e.g. "Payments 5xx on {{ (index .Alerts 0).Host }}".
+type IncidentTemplateData struct {
+	Alerts    []Alert   // Matching alerts
+	Summaries []string  // Summaries of the matching alerts
+	Hosts     []string  // Distinct hosts of the matching alerts
+	Count     int       // Number of the matching alerts
+	FirstAt   time.Time // Creation time of the earliest matching alert
+	LastAt    time.Time // Creation time of the latest matching alert
+}
+
+// NewIncidentTemplateData builds the template data of the matching alerts.
+func NewIncidentTemplateData(alerts []Alert) *IncidentTemplateData {
+	data := &IncidentTemplateData{
+		Alerts:    alerts,
+		Summaries: []string{},
+		Hosts:     []string{},
+		Count:     len(alerts),
+	}
+
+	seenHosts := make(map[string]struct{})
+	for i, alert := range alerts {
+		data.Summaries = append(data.Summaries, alert.Summary)
+		if _, ok := seenHosts[alert.Host]; !ok && alert.Host != "" {
+			seenHosts[alert.Host] = struct{}{}
+			data.Hosts = append(data.Hosts, alert.Host)
+		}
+		if i == 0 || alert.CreatedAt.Before(data.FirstAt) {
+			data.FirstAt = alert.CreatedAt
+		}
+		if alert.CreatedAt.After(data.LastAt) {
+			data.LastAt = alert.CreatedAt
+		}
+	}
+
+	return data
+}
+
+// RenderIncidentSummary evaluates the incident summary template of the rule against the matching alerts.
+func (r *Rule) RenderIncidentSummary(alerts []Alert) (string, error) {
+	summary, err := renderIncidentTemplate("set_incident_summary", r.SetIncidentSummary, NewIncidentTemplateData(alerts))
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(summary) == "" {
+		return "", errors.New("set_incident_summary template evaluates to an empty summary")
+	}
+	return summary, nil
+}
+
+// RenderIncidentDescription evaluates the incident description template of the rule against the matching alerts.
+func (r *Rule) RenderIncidentDescription(alerts []Alert) (string, error) {
+	return renderIncidentTemplate("set_incident_description", r.SetIncidentDescription, NewIncidentTemplateData(alerts))
+}
+
+// validateIncidentTemplates evaluates the incident templates of the rule against sample alerts, one per summary condition
+// as the matches of the rule have, so that both syntax errors and references to unknown fields are reported when the rule is saved.
+func (r *Rule) validateIncidentTemplates() error {
+	sampleAlerts := []Alert{}
+	for i := 0; i == 0 || i < len(r.AlertsSummaryConditions); i++ {
+		sampleAlerts = append(sampleAlerts, Alert{
+			Summary:     fmt.Sprintf("[sample-host-%d] Sample alert", i+1),
+			Host:        fmt.Sprintf("sample-host-%d", i+1),
+			Fingerprint: fmt.Sprintf("sample:%d", i+1),
+			CreatedAt:   time.Now().UTC(),
+		})
+	}
+
+	if r.SetIncidentSummary != "" {
+		if _, err := r.RenderIncidentSummary(sampleAlerts); err != nil {
+			return err
+		}
+	}
+	if _, err := r.RenderIncidentDescription(sampleAlerts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// renderIncidentTemplate parses and executes an incident template.
+func renderIncidentTemplate(name, text string, data *IncidentTemplateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(incidentTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s template: %w", name, err)
+	}
+
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("error executing %s template: %w", name, err)
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,115 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"errors"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// recurrenceDays maps the recurrences of maintenance windows to their periods in days.
+var recurrenceDays = map[string]int{
+	"daily":  1,
+	"weekly": 7,
+}
+
+// MaintenanceWindow represents a period of planned work during which matching rules don't create incidents.
+// A recurring window repeats its first occurrence daily or weekly at the same wall clock time in its time zone,
+// so an occurrence across a daylight saving time change is an hour shorter or longer than the first one.
+type MaintenanceWindow struct {
+	ID              string    `json:"id" validate:"required"`                                 // Unique identifier for the window
+	Description     string    `json:"description" validate:"omitempty"`                       // Optional description of the planned work
+	FromAt          time.Time `json:"from_at" validate:"required"`                            // Start of the (first occurrence of the) window
+	ToAt            time.Time `json:"to_at" validate:"required,gtfield=FromAt"`               // End of the (first occurrence of the) window
+	Recurrence      string    `json:"recurrence" validate:"required,oneof=none daily weekly"` // Recurrence of the window
+	Timezone        string    `json:"timezone" validate:"required,timezone"`                  // IANA time zone the window recurs in, e.g. "Europe/Moscow"
+	RuleIDs         []string  `json:"rule_ids" validate:"omitempty"`                          // Rules the window applies to
+	Labels          []string  `json:"labels" validate:"omitempty"`                            // Incident labels of the rules the window applies to
+	TroubleServices []string  `json:"trouble_services" validate:"omitempty"`                  // Trouble services of the rules the window applies to
+	Creator         string    `json:"creator" validate:"required"`                            // Identifier of the user who created the window
+	CreatedAt       time.Time `json:"created_at" validate:"required"`                         // Timestamp when the window was created
+	UpdatedAt       time.Time `json:"updated_at" validate:"required"`                         // Timestamp when the window was last updated
+}
+
+// Validate runs validation rules on a MaintenanceWindow instance.
+func (mw *MaintenanceWindow) Validate() error {
+	if len(mw.RuleIDs) == 0 && len(mw.Labels) == 0 && len(mw.TroubleServices) == 0 {
+		return errors.New("at least one of rule_ids, labels and trouble_services is required")
+	}
+	if days, ok := recurrenceDays[mw.Recurrence]; ok && mw.ToAt.Sub(mw.FromAt) > time.Duration(days)*24*time.Hour {
+		return errors.New("window must not be longer than its recurrence period")
+	}
+	return utils.ValidateStruct(mw)
+}
+
+// AppliesTo reports whether the window scopes the rule by its ID, one of its labels or one of its trouble services.
+func (mw *MaintenanceWindow) AppliesTo(rule *Rule) bool {
+	return containsAny(mw.RuleIDs, []string{rule.ID}) ||
+		containsAny(mw.Labels, rule.SetIncidentLabels) ||
+		containsAny(mw.TroubleServices, rule.SetIncidentTroubleServices)
+}
+
+// OccurrenceAt returns the bounds of the occurrence of the window active at the given time.
+// It reports false if the window isn't active at that time.
+func (mw *MaintenanceWindow) OccurrenceAt(at time.Time) (time.Time, time.Time, bool) {
+	if at.Before(mw.FromAt) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	periodDays, ok := recurrenceDays[mw.Recurrence]
+	if !ok {
+		return mw.FromAt, mw.ToAt, at.Before(mw.ToAt)
+	}
+	location, err := time.LoadLocation(mw.Timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	// Count the calendar days between the first occurrence and the time in the time zone of the window;
+	// the latest occurrence started on or before that day, and it's the only one that may still last.
+	start := mw.FromAt.In(location)
+	end := mw.ToAt.In(location)
+	now := at.In(location)
+	days := int(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+
+	for occurrence := days / periodDays; occurrence >= 0 && occurrence >= days/periodDays-1; occurrence-- {
+		fromAt := time.Date(start.Year(), start.Month(), start.Day()+occurrence*periodDays,
+			start.Hour(), start.Minute(), start.Second(), 0, location)
+		if at.Before(fromAt) {
+			continue
+		}
+		toAt := time.Date(end.Year(), end.Month(), end.Day()+occurrence*periodDays,
+			end.Hour(), end.Minute(), end.Second(), 0, location)
+		if at.Before(toAt) {
+			return fromAt.UTC(), toAt.UTC(), true
+		}
+		break
+	}
+
+	return time.Time{}, time.Time{}, false
+}
+
+// MaintenanceSuppression records the matches of a rule suppressed by an occurrence of a maintenance window.
+type MaintenanceSuppression struct {
+	ID                string    `json:"id"`                  // Unique identifier for the suppression
+	WindowID          string    `json:"window_id"`           // Window that suppressed the matches
+	WindowFromAt      time.Time `json:"window_from_at"`      // Start of the occurrence of the window
+	RuleID            string    `json:"rule_id"`             // Rule whose matches were suppressed
+	MatchingCount     int       `json:"matching_count"`      // Number of the suppressed matches
+	FirstMatchingTime time.Time `json:"first_matching_time"` // Time of the first suppressed match
+	LastMatchingTime  time.Time `json:"last_matching_time"`  // Time of the last suppressed match
+	AlertsData        string    `json:"alerts_data"`         // Alerts of the last suppressed match in JSON format
+}
+
+// containsAny reports whether any of the values is in the list.
+func containsAny(list []string, values []string) bool {
+	for _, item := range list {
+		for _, value := range values {
+			if item == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowOccurrenceAt(t *testing.T) {
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	window := func(recurrence string, fromAt, toAt time.Time) *MaintenanceWindow {
+		return &MaintenanceWindow{FromAt: fromAt, ToAt: toAt, Recurrence: recurrence, Timezone: "Europe/Berlin"}
+	}
+
+	// Berlin is UTC+1 in winter and UTC+2 in summer; it moves to the summer time on 2024-03-31 and back on 2024-10-27
+	once := window("none", utc(3, 1, 10, 0), utc(3, 1, 12, 0))
+	daily := window("daily", utc(3, 1, 8, 0), utc(3, 1, 9, 0))                // 09:00-10:00
+	weekly := window("weekly", utc(3, 1, 17, 0), utc(3, 1, 19, 0))            // Fridays, 18:00-20:00
+	dailyOverMidnight := window("daily", utc(3, 1, 22, 0), utc(3, 2, 0, 0))   // 23:00-01:00
+	weeklyOverMidnight := window("weekly", utc(3, 1, 22, 0), utc(3, 2, 1, 0)) // Fridays 23:00 to Saturdays 02:00
+	nightly := window("daily", utc(3, 29, 21, 0), utc(3, 30, 5, 0))           // 22:00-06:00
+	unknownTimezone := window("daily", utc(3, 1, 8, 0), utc(3, 1, 9, 0))
+	unknownTimezone.Timezone = "Nowhere/City"
+
+	tests := []struct {
+		name       string
+		window     *MaintenanceWindow
+		at         time.Time
+		wantActive bool
+		wantFromAt time.Time
+		wantToAt   time.Time
+	}{
+		{"once before", once, utc(3, 1, 9, 59), false, time.Time{}, time.Time{}},
+		{"once at the start", once, utc(3, 1, 10, 0), true, utc(3, 1, 10, 0), utc(3, 1, 12, 0)},
+		{"once before the end", once, utc(3, 1, 11, 59), true, utc(3, 1, 10, 0), utc(3, 1, 12, 0)},
+		{"once at the end", once, utc(3, 1, 12, 0), false, time.Time{}, time.Time{}},
+		{"once doesn't recur", once, utc(3, 2, 11, 0), false, time.Time{}, time.Time{}},
+
+		{"daily before the first occurrence", daily, utc(2, 29, 8, 30), false, time.Time{}, time.Time{}},
+		{"daily first occurrence", daily, utc(3, 1, 8, 30), true, utc(3, 1, 8, 0), utc(3, 1, 9, 0)},
+		{"daily later occurrence", daily, utc(3, 5, 8, 30), true, utc(3, 5, 8, 0), utc(3, 5, 9, 0)},
+		{"daily between the occurrences", daily, utc(3, 5, 9, 0), false, time.Time{}, time.Time{}},
+		{"daily in the summer time", daily, utc(4, 5, 7, 30), true, utc(4, 5, 7, 0), utc(4, 5, 8, 0)},
+		{"daily in the summer time at the winter time", daily, utc(4, 5, 8, 30), false, time.Time{}, time.Time{}},
+
+		{"weekly first occurrence", weekly, utc(3, 1, 18, 0), true, utc(3, 1, 17, 0), utc(3, 1, 19, 0)},
+		{"weekly next week", weekly, utc(3, 8, 17, 30), true, utc(3, 8, 17, 0), utc(3, 8, 19, 0)},
+		{"weekly on another day", weekly, utc(3, 7, 17, 30), false, time.Time{}, time.Time{}},
+		{"weekly in the summer time", weekly, utc(4, 5, 16, 30), true, utc(4, 5, 16, 0), utc(4, 5, 18, 0)},
+
+		{"over midnight before it", dailyOverMidnight, utc(3, 10, 22, 30), true, utc(3, 10, 22, 0), utc(3, 11, 0, 0)},
+		{"over midnight after it", dailyOverMidnight, utc(3, 10, 23, 30), true, utc(3, 10, 22, 0), utc(3, 11, 0, 0)},
+		{"over midnight at the end", dailyOverMidnight, utc(3, 11, 0, 0), false, time.Time{}, time.Time{}},
+		{"weekly over midnight on the next day", weeklyOverMidnight, utc(3, 9, 0, 30), true, utc(3, 8, 22, 0), utc(3, 9, 1, 0)},
+		{"weekly over midnight two days later", weeklyOverMidnight, utc(3, 10, 0, 30), false, time.Time{}, time.Time{}},
+
+		// The occurrences across the DST changes start and end at the same wall clock times as the first one
+		{"spring forward night", nightly, utc(3, 31, 3, 30), true, utc(3, 30, 21, 0), utc(3, 31, 4, 0)},
+		{"spring forward night after the end", nightly, utc(3, 31, 4, 30), false, time.Time{}, time.Time{}},
+		{"night after the spring forward", nightly, utc(4, 1, 3, 30), true, utc(3, 31, 20, 0), utc(4, 1, 4, 0)},
+		{"fall back night", nightly, utc(10, 27, 4, 30), true, utc(10, 26, 20, 0), utc(10, 27, 5, 0)},
+		{"fall back night after the end", nightly, utc(10, 27, 5, 0), false, time.Time{}, time.Time{}},
+
+		{"unknown time zone", unknownTimezone, utc(3, 5, 8, 30), false, time.Time{}, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromAt, toAt, active := tt.window.OccurrenceAt(tt.at)
+			if active != tt.wantActive {
+				t.Fatalf("active is %t, want %t", active, tt.wantActive)
+			}
+			// The bounds are only meaningful for an active occurrence
+			if active && (!fromAt.Equal(tt.wantFromAt) || !toAt.Equal(tt.wantToAt)) {
+				t.Errorf("occurrence is %v - %v, want %v - %v", fromAt, toAt, tt.wantFromAt, tt.wantToAt)
+			}
+		})
+	}
+}
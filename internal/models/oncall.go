@@ -0,0 +1,117 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// rotationDays maps the rotations of on-call schedules to the length of their shifts in days.
+var rotationDays = map[string]int{
+	"daily":  1,
+	"weekly": 7,
+}
+
+// OnCallUser represents a person who takes on-call shifts.
+type OnCallUser struct {
+	ID         string    `json:"id" validate:"required"`           // Unique identifier for the user
+	Name       string    `json:"name" validate:"required"`         // Display name of the user
+	TelegramID int64     `json:"telegram_id" validate:"omitempty"` // Telegram user ID used to message the user directly
+	Email      string    `json:"email" validate:"omitempty,email"` // Email address of the user
+	LDAPLogin  string    `json:"ldap_login" validate:"omitempty"`  // LDAP login of the user
+	CreatedAt  time.Time `json:"created_at" validate:"required"`   // Timestamp when the user was created
+	UpdatedAt  time.Time `json:"updated_at" validate:"required"`   // Timestamp when the user was last updated
+}
+
+// Validate runs validation rules on an OnCallUser instance.
+func (u *OnCallUser) Validate() error {
+	return utils.ValidateStruct(u)
+}
+
+// OnCallSchedule represents a rotation of users taking daily or weekly on-call shifts.
+// Shifts are handed off at the wall clock time of the rotation start in the time zone of the schedule,
+// so the handoff time stays the same across daylight saving time changes.
+type OnCallSchedule struct {
+	ID            string    `json:"id" validate:"required"`                          // Unique identifier for the schedule
+	Name          string    `json:"name" validate:"required,excludesall=:@ "`        // Unique name of the schedule used in the on-call targets, e.g. "oncall:backend"
+	Description   string    `json:"description" validate:"omitempty"`                // Optional description of the schedule
+	Timezone      string    `json:"timezone" validate:"required,timezone"`           // IANA time zone the shifts are handed off in, e.g. "Europe/Moscow"
+	Rotation      string    `json:"rotation" validate:"required,oneof=daily weekly"` // Length of a shift
+	RotationStart time.Time `json:"rotation_start" validate:"required"`              // Start of the shift of the first user of the rotation
+	UserIDs       []string  `json:"user_ids" validate:"required,min=1"`              // Users of the rotation in the order of their shifts
+	CreatedAt     time.Time `json:"created_at" validate:"required"`                  // Timestamp when the schedule was created
+	UpdatedAt     time.Time `json:"updated_at" validate:"required"`                  // Timestamp when the schedule was last updated
+}
+
+// Validate runs validation rules on an OnCallSchedule instance.
+func (s *OnCallSchedule) Validate() error {
+	return utils.ValidateStruct(s)
+}
+
+// ShiftAt returns the user of the rotation on call at the given time together with the bounds of their shift.
+func (s *OnCallSchedule) ShiftAt(at time.Time) (string, time.Time, time.Time, error) {
+	if len(s.UserIDs) == 0 {
+		return "", time.Time{}, time.Time{}, errors.New("schedule has no users")
+	}
+	periodDays, ok := rotationDays[s.Rotation]
+	if !ok {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("unknown rotation %s", s.Rotation)
+	}
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("error loading time zone: %w", err)
+	}
+
+	// Count the calendar days between the rotation start and the time in the time zone of the schedule;
+	// the day doesn't count until its handoff time has come.
+	start := s.RotationStart.In(location)
+	now := at.In(location)
+	days := int(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	startClock := start.Hour()*3600 + start.Minute()*60 + start.Second()
+	nowClock := now.Hour()*3600 + now.Minute()*60 + now.Second()
+	if nowClock < startClock {
+		days--
+	}
+
+	// Floor the division, so the rotation also extends to the time before its start.
+	shift := days / periodDays
+	if days < 0 && days%periodDays != 0 {
+		shift--
+	}
+	userIndex := shift % len(s.UserIDs)
+	if userIndex < 0 {
+		userIndex += len(s.UserIDs)
+	}
+
+	fromAt := time.Date(start.Year(), start.Month(), start.Day()+shift*periodDays, start.Hour(), start.Minute(), start.Second(), 0, location)
+	toAt := fromAt.AddDate(0, 0, periodDays)
+
+	return s.UserIDs[userIndex], fromAt.UTC(), toAt.UTC(), nil
+}
+
+// OnCallOverride represents a time-bounded replacement of the on-call user of a schedule.
+type OnCallOverride struct {
+	ID         string    `json:"id" validate:"required"`                   // Unique identifier for the override
+	ScheduleID string    `json:"schedule_id" validate:"required"`          // Schedule the override belongs to
+	UserID     string    `json:"user_id" validate:"required"`              // User on call during the override
+	FromAt     time.Time `json:"from_at" validate:"required"`              // Start of the override
+	ToAt       time.Time `json:"to_at" validate:"required,gtfield=FromAt"` // End of the override
+	CreatedAt  time.Time `json:"created_at" validate:"required"`           // Timestamp when the override was created
+}
+
+// Validate runs validation rules on an OnCallOverride instance.
+func (o *OnCallOverride) Validate() error {
+	return utils.ValidateStruct(o)
+}
+
+// OnCallShift represents who is on call for a schedule at a given time.
+type OnCallShift struct {
+	Schedule   *OnCallSchedule `json:"schedule"`    // Schedule of the shift
+	User       *OnCallUser     `json:"user"`        // User on call
+	IsOverride bool            `json:"is_override"` // Flag indicating if the user is on call because of an override
+	FromAt     time.Time       `json:"from_at"`     // Start of the shift or the override
+	ToAt       time.Time       `json:"to_at"`       // End of the shift or the override
+}
@@ -0,0 +1,86 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestOnCallScheduleShiftAt(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	// On 2024-03-31 Berlin moves from 02:00 CET (UTC+1) to 03:00 CEST (UTC+2), so the handoffs at 09:00
+	// are at 08:00 UTC before the change and at 07:00 UTC after it
+	weekly := &OnCallSchedule{
+		Timezone:      "Europe/Berlin",
+		Rotation:      "weekly",
+		RotationStart: utc(2024, 3, 25, 8, 0),
+		UserIDs:       []string{"alice", "bob", "carol"},
+	}
+	daily := &OnCallSchedule{
+		Timezone:      "Europe/Berlin",
+		Rotation:      "daily",
+		RotationStart: utc(2024, 3, 29, 8, 0),
+		UserIDs:       []string{"alice", "bob"},
+	}
+
+	tests := []struct {
+		name       string
+		schedule   *OnCallSchedule
+		at         time.Time
+		wantUserID string
+		wantFromAt time.Time
+		wantToAt   time.Time
+	}{
+		{"weekly at the rotation start", weekly, utc(2024, 3, 25, 8, 0), "alice", utc(2024, 3, 25, 8, 0), utc(2024, 4, 1, 7, 0)},
+		{"weekly within the DST week", weekly, utc(2024, 3, 31, 12, 0), "alice", utc(2024, 3, 25, 8, 0), utc(2024, 4, 1, 7, 0)},
+		{"weekly before the handoff after DST", weekly, utc(2024, 4, 1, 6, 59), "alice", utc(2024, 3, 25, 8, 0), utc(2024, 4, 1, 7, 0)},
+		{"weekly at the handoff after DST", weekly, utc(2024, 4, 1, 7, 0), "bob", utc(2024, 4, 1, 7, 0), utc(2024, 4, 8, 7, 0)},
+		{"weekly third shift", weekly, utc(2024, 4, 10, 12, 0), "carol", utc(2024, 4, 8, 7, 0), utc(2024, 4, 15, 7, 0)},
+		{"weekly rotation wraps around", weekly, utc(2024, 4, 15, 7, 0), "alice", utc(2024, 4, 15, 7, 0), utc(2024, 4, 22, 7, 0)},
+		{"weekly before the rotation start", weekly, utc(2024, 3, 25, 7, 59), "carol", utc(2024, 3, 18, 8, 0), utc(2024, 3, 25, 8, 0)},
+		{"weekly two shifts before the rotation start", weekly, utc(2024, 3, 12, 0, 0), "bob", utc(2024, 3, 11, 8, 0), utc(2024, 3, 18, 8, 0)},
+
+		{"daily first shift", daily, utc(2024, 3, 29, 20, 0), "alice", utc(2024, 3, 29, 8, 0), utc(2024, 3, 30, 8, 0)},
+		{"daily before the handoff", daily, utc(2024, 3, 30, 7, 59), "alice", utc(2024, 3, 29, 8, 0), utc(2024, 3, 30, 8, 0)},
+		{"daily shift shortened by DST", daily, utc(2024, 3, 30, 8, 0), "bob", utc(2024, 3, 30, 8, 0), utc(2024, 3, 31, 7, 0)},
+		{"daily handoff after DST", daily, utc(2024, 3, 31, 7, 0), "alice", utc(2024, 3, 31, 7, 0), utc(2024, 4, 1, 7, 0)},
+		{"daily before the rotation start", daily, utc(2024, 3, 28, 12, 0), "bob", utc(2024, 3, 28, 8, 0), utc(2024, 3, 29, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, fromAt, toAt, err := tt.schedule.ShiftAt(tt.at)
+			if err != nil {
+				t.Fatalf("error getting the shift: %v", err)
+			}
+			if userID != tt.wantUserID {
+				t.Errorf("user is %s, want %s", userID, tt.wantUserID)
+			}
+			if !fromAt.Equal(tt.wantFromAt) || !toAt.Equal(tt.wantToAt) {
+				t.Errorf("shift is %v - %v, want %v - %v", fromAt, toAt, tt.wantFromAt, tt.wantToAt)
+			}
+		})
+	}
+}
+
+func TestOnCallScheduleShiftAtErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *OnCallSchedule
+	}{
+		{"no users", &OnCallSchedule{Timezone: "UTC", Rotation: "daily"}},
+		{"unknown rotation", &OnCallSchedule{Timezone: "UTC", Rotation: "monthly", UserIDs: []string{"alice"}}},
+		{"unknown time zone", &OnCallSchedule{Timezone: "Nowhere/City", Rotation: "daily", UserIDs: []string{"alice"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := tt.schedule.ShiftAt(time.Now()); err == nil {
+				t.Error("ShiftAt succeeded, want an error")
+			}
+		})
+	}
+}
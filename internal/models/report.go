@@ -0,0 +1,14 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+// IncidentsReportRow represents the statistics of the incidents of one group of a report, e.g. of one departament.
+// The durations are in seconds; the duration of an actual incident lasts until the time of the report.
+type IncidentsReportRow struct {
+	Key                  string  `json:"key"`                    // Value of the dimension the incidents are grouped by
+	Count                int     `json:"count"`                  // Number of the incidents
+	TotalDurationSeconds float64 `json:"total_duration_seconds"` // Total duration of the incidents
+	MeanDurationSeconds  float64 `json:"mean_duration_seconds"`  // Mean duration of the incidents
+	MTTASeconds          float64 `json:"mtta_seconds"`           // Mean time to acknowledge: from the start to the confirmation of the confirmed incidents
+	MTTRSeconds          float64 `json:"mttr_seconds"`           // Mean time to resolve: the mean duration of the finished and closed incidents
+	DowntimeMinutes      float64 `json:"downtime_minutes"`       // Total duration of the downtime incidents
+	FinLosses            int64   `json:"fin_losses"`             // Total financial losses of the incidents
+}
@@ -1,6 +1,7 @@
 package models // dnywonnt.me/alerts2incidents/internal/models
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,13 +12,16 @@ import (
 type Rule struct {
 	ID                               string          `json:"id" validate:"required"`                                                                                                                                                          // Unique identifier for the rule, mandatory.
 	IsMuted                          bool            `json:"is_muted" validate:"-"`                                                                                                                                                           // Indicates whether the rule is currently muted.
+	MuteUntil                        *time.Time      `json:"mute_until" validate:"omitempty"`                                                                                                                                                 // Time the rule is automatically unmuted at; the rule stays muted until it's unmuted by hand if not set.
+	MuteReason                       string          `json:"mute_reason" validate:"omitempty"`                                                                                                                                                // Optional reason the rule is muted for.
+	MutedBy                          string          `json:"muted_by" validate:"omitempty"`                                                                                                                                                   // Optional identifier of the user who muted the rule.
 	Description                      string          `json:"description" validate:"omitempty"`                                                                                                                                                // Optional description of the rule.
 	AlertsSummaryConditions          []string        `json:"alerts_summary_conditions" validate:"required,min=1"`                                                                                                                             // Conditions under which alerts are summarized, at least one condition is required.
 	AlertsActivityIntervalConditions []time.Duration `json:"alerts_activity_interval_conditions" validate:"required,min=1"`                                                                                                                   // Time intervals for monitoring alert activity, at least one interval is required.
 	IncidentLifeTime                 time.Duration   `json:"incident_life_time" validate:"required"`                                                                                                                                          // Duration for which an incident is considered active.
 	IncidentFinishingInterval        time.Duration   `json:"incident_finishing_interval" validate:"required"`                                                                                                                                 // Duration after which an incident is considered finished.
-	SetIncidentSummary               string          `json:"set_incident_summary" validate:"required"`                                                                                                                                        // Mandatory summary description for the incident.
-	SetIncidentDescription           string          `json:"set_incident_description" validate:"omitempty"`                                                                                                                                   // Optional detailed description of the incident.
+	SetIncidentSummary               string          `json:"set_incident_summary" validate:"required"`                                                                                                                                        // Mandatory summary description for the incident; a template evaluated against the matching alerts.
+	SetIncidentDescription           string          `json:"set_incident_description" validate:"omitempty"`                                                                                                                                   // Optional detailed description of the incident; a template evaluated against the matching alerts.
 	SetIncidentDepartament           string          `json:"set_incident_departament" validate:"required,oneof=internal_digital internal_it external_service"`                                                                                // Department responsible for handling the incident, required.
 	SetIncidentClientAffect          string          `json:"set_incident_client_affect" validate:"omitempty"`                                                                                                                                 // Optional description of how the incident affects clients.
 	SetIncidentIsManageable          string          `json:"set_incident_is_manageable" validate:"required,oneof=yes no indirectly"`                                                                                                          // Required field indicating if the incident is manageable, valid values are 'yes', 'no', 'indirectly'.
@@ -26,6 +30,11 @@ type Rule struct {
 	SetIncidentFailureType           string          `json:"set_incident_failure_type" validate:"required,oneof=err_network err_acquiring err_development err_security err_infrastructure err_configuration err_menu err_external err_other"` // Specifies the type of failure that caused the incident. This field is required and must be one of the predefined error types: 'err_network', 'err_acquiring', 'err_development', 'err_security', 'err_infrastructure', 'err_configuration', 'err_menu', 'err_external', or 'err_other'.
 	SetIncidentLabels                []string        `json:"set_incident_labels" validate:"required"`                                                                                                                                         // Labels associated with the incident, requires at least one label.
 	SetIncidentIsDowntime            bool            `json:"set_incident_is_downtime" validate:"-"`                                                                                                                                           // Indicates if the incident causes downtime.
+	FlappingWindow                   time.Duration   `json:"flapping_window" validate:"required_with=FlappingThreshold"`                                                                                                                      // Window the state changes of an incident are counted within to detect flapping.
+	FlappingThreshold                int             `json:"flapping_threshold" validate:"omitempty,min=2"`                                                                                                                                   // Number of state changes within the window that makes an incident flapping; 0 disables the detection.
+	SetIncidentSeverity              string          `json:"set_incident_severity" validate:"required,oneof=SEV1 SEV2 SEV3 SEV4"`                                                                                                             // Severity of the incident, SEV1 being the most severe.
+	DeriveIncidentSeverity           bool            `json:"derive_incident_severity" validate:"-"`                                                                                                                                           // Indicates if the incident takes the highest severity of the matching alerts, if it's higher than the set one.
+	Version                          int             `json:"version" validate:"-"`                                                                                                                                                            // Version of the rule, incremented on every update for optimistic concurrency control.
 	CreatedAt                        time.Time       `json:"created_at" validate:"required"`                                                                                                                                                  // Timestamp of when the rule was created, required.
 	UpdatedAt                        time.Time       `json:"updated_at" validate:"required"`                                                                                                                                                  // Timestamp of the last update to the rule, required.
 }
@@ -37,6 +46,19 @@ func (r *Rule) Validate() error {
 		return fmt.Errorf("mismatch in number of summary conditions (%d) and activity intervals (%d)",
 			len(r.AlertsSummaryConditions), len(r.AlertsActivityIntervalConditions))
 	}
+	// The mute details only make sense for a muted rule.
+	if !r.IsMuted && (r.MuteUntil != nil || r.MuteReason != "" || r.MutedBy != "") {
+		return errors.New("mute_until, mute_reason and muted_by can only be set for a muted rule")
+	}
+	// The incident summary and description are templates, so they must be evaluated without errors.
+	if err := r.validateIncidentTemplates(); err != nil {
+		return err
+	}
 	// Use the validator library to validate the struct according to tags.
 	return utils.ValidateStruct(r)
 }
+
+// IsMutedAt reports whether the rule is muted at the given time; a mute that has expired doesn't count.
+func (r *Rule) IsMutedAt(t time.Time) bool {
+	return r.IsMuted && (r.MuteUntil == nil || t.Before(*r.MuteUntil))
+}
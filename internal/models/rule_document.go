@@ -0,0 +1,168 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Actions of the changes made by a rules import.
+const (
+	CreateRulesImportAction = "create"
+	UpdateRulesImportAction = "update"
+	DeleteRulesImportAction = "delete"
+)
+
+// RulesDocument represents the rules exported to or imported from a YAML or JSON document, e.g. kept in git.
+type RulesDocument struct {
+	Rules []*RuleSpec `json:"rules" yaml:"rules"` // Rules of the document, identified by their stable IDs
+}
+
+// RuleSpec represents the definition of a rule in a rules document. The mute state and the timestamps
+// of a rule are operational rather than a part of its definition, so they aren't exported or imported.
+type RuleSpec struct {
+	ID                               string          `json:"id" yaml:"id"`
+	Description                      string          `json:"description" yaml:"description"`
+	AlertsSummaryConditions          []string        `json:"alerts_summary_conditions" yaml:"alerts_summary_conditions"`
+	AlertsActivityIntervalConditions []time.Duration `json:"alerts_activity_interval_conditions" yaml:"alerts_activity_interval_conditions"`
+	IncidentLifeTime                 time.Duration   `json:"incident_life_time" yaml:"incident_life_time"`
+	IncidentFinishingInterval        time.Duration   `json:"incident_finishing_interval" yaml:"incident_finishing_interval"`
+	SetIncidentSummary               string          `json:"set_incident_summary" yaml:"set_incident_summary"`
+	SetIncidentDescription           string          `json:"set_incident_description" yaml:"set_incident_description"`
+	SetIncidentDepartament           string          `json:"set_incident_departament" yaml:"set_incident_departament"`
+	SetIncidentClientAffect          string          `json:"set_incident_client_affect" yaml:"set_incident_client_affect"`
+	SetIncidentIsManageable          string          `json:"set_incident_is_manageable" yaml:"set_incident_is_manageable"`
+	SetIncidentSaleChannels          []string        `json:"set_incident_sale_channels" yaml:"set_incident_sale_channels"`
+	SetIncidentTroubleServices       []string        `json:"set_incident_trouble_services" yaml:"set_incident_trouble_services"`
+	SetIncidentFailureType           string          `json:"set_incident_failure_type" yaml:"set_incident_failure_type"`
+	SetIncidentLabels                []string        `json:"set_incident_labels" yaml:"set_incident_labels"`
+	SetIncidentIsDowntime            bool            `json:"set_incident_is_downtime" yaml:"set_incident_is_downtime"`
+	FlappingWindow                   time.Duration   `json:"flapping_window" yaml:"flapping_window"`
+	FlappingThreshold                int             `json:"flapping_threshold" yaml:"flapping_threshold"`
+	SetIncidentSeverity              string          `json:"set_incident_severity" yaml:"set_incident_severity"`
+	DeriveIncidentSeverity           bool            `json:"derive_incident_severity" yaml:"derive_incident_severity"`
+}
+
+// RulesImportChange represents a change of a single rule made, or planned by a dry run, by a rules import.
+type RulesImportChange struct {
+	ID     string   `json:"id"`               // Identifier of the rule
+	Action string   `json:"action"`           // Action: create, update or delete
+	Fields []string `json:"fields,omitempty"` // Fields of the definition changed by an update
+}
+
+// RulesImportResult represents the outcome of a rules import.
+type RulesImportResult struct {
+	DryRun    bool                 `json:"dry_run"`   // Indicates if the changes have only been planned, not made
+	Changes   []*RulesImportChange `json:"changes"`   // Changes of the rules, in the order of the document, then the deletions
+	Unchanged int                  `json:"unchanged"` // Number of rules of the document that already match the stored ones
+}
+
+// NewRuleSpec returns the definition of the rule for a rules document.
+func NewRuleSpec(rule *Rule) *RuleSpec {
+	spec := &RuleSpec{
+		ID:                               rule.ID,
+		Description:                      rule.Description,
+		AlertsSummaryConditions:          rule.AlertsSummaryConditions,
+		AlertsActivityIntervalConditions: rule.AlertsActivityIntervalConditions,
+		IncidentLifeTime:                 rule.IncidentLifeTime,
+		IncidentFinishingInterval:        rule.IncidentFinishingInterval,
+		SetIncidentSummary:               rule.SetIncidentSummary,
+		SetIncidentDescription:           rule.SetIncidentDescription,
+		SetIncidentDepartament:           rule.SetIncidentDepartament,
+		SetIncidentClientAffect:          rule.SetIncidentClientAffect,
+		SetIncidentIsManageable:          rule.SetIncidentIsManageable,
+		SetIncidentSaleChannels:          rule.SetIncidentSaleChannels,
+		SetIncidentTroubleServices:       rule.SetIncidentTroubleServices,
+		SetIncidentFailureType:           rule.SetIncidentFailureType,
+		SetIncidentLabels:                rule.SetIncidentLabels,
+		SetIncidentIsDowntime:            rule.SetIncidentIsDowntime,
+		FlappingWindow:                   rule.FlappingWindow,
+		FlappingThreshold:                rule.FlappingThreshold,
+		SetIncidentSeverity:              rule.SetIncidentSeverity,
+		DeriveIncidentSeverity:           rule.DeriveIncidentSeverity,
+	}
+	spec.normalize()
+	return spec
+}
+
+// ApplyTo sets the definition of the rule to the spec, leaving its mute state and timestamps intact.
+func (s *RuleSpec) ApplyTo(rule *Rule) {
+	rule.ID = s.ID
+	rule.Description = s.Description
+	rule.AlertsSummaryConditions = s.AlertsSummaryConditions
+	rule.AlertsActivityIntervalConditions = s.AlertsActivityIntervalConditions
+	rule.IncidentLifeTime = s.IncidentLifeTime
+	rule.IncidentFinishingInterval = s.IncidentFinishingInterval
+	rule.SetIncidentSummary = s.SetIncidentSummary
+	rule.SetIncidentDescription = s.SetIncidentDescription
+	rule.SetIncidentDepartament = s.SetIncidentDepartament
+	rule.SetIncidentClientAffect = s.SetIncidentClientAffect
+	rule.SetIncidentIsManageable = s.SetIncidentIsManageable
+	rule.SetIncidentSaleChannels = s.SetIncidentSaleChannels
+	rule.SetIncidentTroubleServices = s.SetIncidentTroubleServices
+	rule.SetIncidentFailureType = s.SetIncidentFailureType
+	rule.SetIncidentLabels = s.SetIncidentLabels
+	rule.SetIncidentIsDowntime = s.SetIncidentIsDowntime
+	rule.FlappingWindow = s.FlappingWindow
+	rule.FlappingThreshold = s.FlappingThreshold
+	rule.SetIncidentSeverity = s.SetIncidentSeverity
+	rule.DeriveIncidentSeverity = s.DeriveIncidentSeverity
+}
+
+// ChangedFields returns the names of the fields of the definition of the rule that differ from the spec.
+func (s *RuleSpec) ChangedFields(rule *Rule) []string {
+	current := reflect.ValueOf(*NewRuleSpec(rule))
+	desired := reflect.ValueOf(*s)
+
+	fields := []string{}
+	for i := 0; i < desired.NumField(); i++ {
+		if !reflect.DeepEqual(current.Field(i).Interface(), desired.Field(i).Interface()) {
+			name, _, _ := strings.Cut(desired.Type().Field(i).Tag.Get("json"), ",")
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// normalize replaces the missing lists with empty ones and sets the default severity, so the specs
+// decoded from documents compare equal to the ones of the stored rules.
+func (s *RuleSpec) normalize() {
+	for _, list := range []*[]string{&s.AlertsSummaryConditions, &s.SetIncidentSaleChannels, &s.SetIncidentTroubleServices, &s.SetIncidentLabels} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	if s.AlertsActivityIntervalConditions == nil {
+		s.AlertsActivityIntervalConditions = []time.Duration{}
+	}
+	if s.SetIncidentSeverity == "" {
+		s.SetIncidentSeverity = DefaultSeverity
+	}
+}
+
+// Validate normalizes the rules of the document and validates them; the IDs must be present and unique.
+func (d *RulesDocument) Validate() error {
+	seen := make(map[string]struct{}, len(d.Rules))
+	for i, spec := range d.Rules {
+		if spec == nil || spec.ID == "" {
+			return fmt.Errorf("rule #%d: id is required", i+1)
+		}
+		if _, ok := seen[spec.ID]; ok {
+			return fmt.Errorf("rule %s: the id is listed twice", spec.ID)
+		}
+		seen[spec.ID] = struct{}{}
+
+		spec.normalize()
+		rule := &Rule{CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+		spec.ApplyTo(rule)
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", spec.ID, err)
+		}
+	}
+	if len(d.Rules) == 0 {
+		return errors.New("the document has no rules")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import "strings"
+
+// DefaultSeverity is the severity of the incidents and rules that don't specify one, the least severe level.
+const DefaultSeverity = "SEV4"
+
+// alertSeverities maps the severities reported by the sources, lowercased, to the incident severities.
+var alertSeverities = map[string]string{
+	"sev1":     "SEV1",
+	"sev2":     "SEV2",
+	"sev3":     "SEV3",
+	"sev4":     "SEV4",
+	"disaster": "SEV1",
+	"critical": "SEV1",
+	"high":     "SEV2",
+	"major":    "SEV2",
+	"error":    "SEV2",
+	"average":  "SEV3",
+	"warning":  "SEV3",
+	"minor":    "SEV3",
+	"info":     "SEV4",
+}
+
+// SeverityFromAlert converts the severity reported by a source, e.g. "critical", to an incident severity.
+// It returns an empty string for unknown severities.
+func SeverityFromAlert(severity string) string {
+	return alertSeverities[strings.ToLower(severity)]
+}
+
+// HighestSeverity returns the most severe of the severities, ignoring empty ones.
+// The severities are "SEV1" to "SEV4", so the most severe one is the least in the string order.
+func HighestSeverity(severities ...string) string {
+	highest := ""
+	for _, severity := range severities {
+		if severity != "" && (highest == "" || severity < highest) {
+			highest = severity
+		}
+	}
+	return highest
+}
+
+// IncidentSeverityFor returns the severity of a new incident of the rule from the matching alerts.
+func (r *Rule) IncidentSeverityFor(alerts []Alert) string {
+	if !r.DeriveIncidentSeverity {
+		return r.SetIncidentSeverity
+	}
+
+	severities := []string{r.SetIncidentSeverity}
+	for _, alert := range alerts {
+		severities = append(severities, alert.Severity)
+	}
+	return HighestSeverity(severities...)
+}
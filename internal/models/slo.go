@@ -0,0 +1,158 @@
+package models // dnywonnt.me/alerts2incidents/internal/models
+
+import (
+	"sort"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// Target types of the service-level objectives.
+const (
+	TroubleServiceSLOTarget = "trouble_service"
+	SaleChannelSLOTarget    = "sale_channel"
+)
+
+// Periods the error budgets of the service-level objectives are computed over.
+const (
+	MonthSLOPeriod   = "month"
+	QuarterSLOPeriod = "quarter"
+)
+
+// SLO represents a service-level objective: the share of a calendar period a trouble service or a sale channel
+// must be available for, e.g. 99.9% per quarter. The rest of the period is the error budget consumed by the downtime incidents.
+type SLO struct {
+	ID            string     `json:"id" validate:"required"`                                             // Unique identifier for the objective
+	Description   string     `json:"description" validate:"omitempty"`                                   // Optional description of the objective
+	TargetType    string     `json:"target_type" validate:"required,oneof=trouble_service sale_channel"` // Type of the target the objective is defined for
+	Target        string     `json:"target" validate:"required"`                                         // Trouble service or sale channel the objective is defined for
+	Objective     float64    `json:"objective" validate:"gt=0,lt=100"`                                   // Required availability in percent, e.g. 99.9
+	Period        string     `json:"period" validate:"required,oneof=month quarter"`                     // Calendar period of the error budget
+	BurnAlertedAt *time.Time `json:"burn_alerted_at" validate:"-"`                                       // Time the last alert about the burned error budget was sent at
+	CreatedAt     time.Time  `json:"created_at" validate:"required"`                                     // Timestamp when the objective was created
+	UpdatedAt     time.Time  `json:"updated_at" validate:"required"`                                     // Timestamp when the objective was last updated
+}
+
+// Validate runs validation rules on an SLO instance.
+func (s *SLO) Validate() error {
+	return utils.ValidateStruct(s)
+}
+
+// PeriodAt returns the bounds of the calendar period of the objective containing the given time, in UTC.
+func (s *SLO) PeriodAt(at time.Time) (time.Time, time.Time) {
+	at = at.UTC()
+
+	month, months := at.Month(), 1
+	if s.Period == QuarterSLOPeriod {
+		month, months = (month-1)/3*3+1, 3
+	}
+
+	fromAt := time.Date(at.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	return fromAt, fromAt.AddDate(0, months, 0)
+}
+
+// DowntimeInterval represents the time a downtime incident lasted for; an actual incident lasts until the time of the computation.
+type DowntimeInterval struct {
+	FromAt time.Time // Start of the downtime
+	ToAt   time.Time // End of the downtime
+}
+
+// SLOStatus represents the consumption of the error budget of a service-level objective within one period.
+// The durations are in seconds.
+type SLOStatus struct {
+	SLO              *SLO      `json:"slo"`               // Objective the status is computed for
+	PeriodFromAt     time.Time `json:"period_from_at"`    // Start of the period
+	PeriodToAt       time.Time `json:"period_to_at"`      // End of the period
+	BudgetSeconds    float64   `json:"budget_seconds"`    // Error budget of the whole period
+	DowntimeSeconds  float64   `json:"downtime_seconds"`  // Downtime within the period, with overlapping incidents counted once
+	RemainingSeconds float64   `json:"remaining_seconds"` // Error budget left, negative if the budget is exhausted
+	BurnedFraction   float64   `json:"burned_fraction"`   // Share of the error budget consumed, above 1 if the budget is exhausted
+	Availability     float64   `json:"availability"`      // Availability in percent over the elapsed part of the period
+	IncidentsCount   int       `json:"incidents_count"`   // Number of the downtime incidents within the period
+}
+
+// NewSLOStatus computes the status of the objective for its period containing the given time
+// from the downtime intervals of the incidents affecting its target.
+func NewSLOStatus(slo *SLO, at time.Time, intervals []DowntimeInterval) *SLOStatus {
+	periodFromAt, periodToAt := slo.PeriodAt(at)
+	status := &SLOStatus{
+		SLO:            slo,
+		PeriodFromAt:   periodFromAt,
+		PeriodToAt:     periodToAt,
+		BudgetSeconds:  periodToAt.Sub(periodFromAt).Seconds() * (100 - slo.Objective) / 100,
+		IncidentsCount: len(intervals),
+		Availability:   100,
+	}
+
+	// Only the elapsed part of the period is taken into account
+	elapsedToAt := periodToAt
+	if at.Before(elapsedToAt) {
+		elapsedToAt = at.UTC()
+	}
+	status.DowntimeSeconds = mergedDuration(intervals, periodFromAt, elapsedToAt).Seconds()
+
+	status.RemainingSeconds = status.BudgetSeconds - status.DowntimeSeconds
+	if status.BudgetSeconds > 0 {
+		status.BurnedFraction = status.DowntimeSeconds / status.BudgetSeconds
+	}
+	if elapsed := elapsedToAt.Sub(periodFromAt).Seconds(); elapsed > 0 {
+		status.Availability = 100 * (1 - status.DowntimeSeconds/elapsed)
+	}
+
+	return status
+}
+
+// mergedDuration returns the total duration of the union of the intervals clipped to the bounds,
+// so the overlapping intervals are counted once.
+func mergedDuration(intervals []DowntimeInterval, fromAt, toAt time.Time) time.Duration {
+	clipped := []DowntimeInterval{}
+	for _, interval := range intervals {
+		if interval.FromAt.Before(fromAt) {
+			interval.FromAt = fromAt
+		}
+		if interval.ToAt.After(toAt) {
+			interval.ToAt = toAt
+		}
+		if interval.ToAt.After(interval.FromAt) {
+			clipped = append(clipped, interval)
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool {
+		return clipped[i].FromAt.Before(clipped[j].FromAt)
+	})
+
+	var total time.Duration
+	var current *DowntimeInterval
+	for i := range clipped {
+		if current != nil && !clipped[i].FromAt.After(current.ToAt) {
+			if clipped[i].ToAt.After(current.ToAt) {
+				current.ToAt = clipped[i].ToAt
+			}
+			continue
+		}
+		if current != nil {
+			total += current.ToAt.Sub(current.FromAt)
+		}
+		current = &clipped[i]
+	}
+	if current != nil {
+		total += current.ToAt.Sub(current.FromAt)
+	}
+
+	return total
+}
+
+// Budget returns the error budget of the whole period rounded to seconds, e.g. for the templates.
+func (ss *SLOStatus) Budget() time.Duration {
+	return (time.Duration(ss.BudgetSeconds) * time.Second).Round(time.Second)
+}
+
+// Downtime returns the downtime within the period rounded to seconds, e.g. for the templates.
+func (ss *SLOStatus) Downtime() time.Duration {
+	return (time.Duration(ss.DowntimeSeconds) * time.Second).Round(time.Second)
+}
+
+// BurnedPercent returns the share of the error budget consumed in percent.
+func (ss *SLOStatus) BurnedPercent() float64 {
+	return ss.BurnedFraction * 100
+}
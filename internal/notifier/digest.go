@@ -0,0 +1,68 @@
+package notifier // dnywonnt.me/alerts2incidents/internal/notifier
+
+import (
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+)
+
+// Digest represents the summary of incidents for a time window rendered by the digest templates.
+type Digest struct {
+	From           time.Time          // Start of the digest window.
+	To             time.Time          // End of the digest window.
+	Opened         []*models.Incident // Incidents started within the window.
+	Finished       []*models.Incident // Incidents finished within the window and not closed yet.
+	Closed         []*models.Incident // Incidents closed within the window.
+	Downtime       []*models.Incident // Incidents of the window with a downtime.
+	Unconfirmed    []*models.Incident // Auto incidents that are still actual and unconfirmed, regardless of the window.
+	TotalFinLosses int                // Total financial losses of the incidents of the window.
+}
+
+// IsEmpty reports whether nothing happened within the window and no incident waits for confirmation.
+func (d *Digest) IsEmpty() bool {
+	return len(d.Opened) == 0 && len(d.Finished) == 0 && len(d.Closed) == 0 && len(d.Unconfirmed) == 0
+}
+
+// BuildDigest groups the incidents into a digest for the time window.
+// The incidents are expected to be the result of IncidentsRepository.GetIncidentsForDigest.
+func BuildDigest(from, to time.Time, incidents []*models.Incident) *Digest {
+	digest := &Digest{
+		From: from,
+		To:   to,
+	}
+
+	inWindow := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+
+	for _, incident := range incidents {
+		// Every incident of the window counts once for the losses and downtime, whatever happened to it
+		isOfWindow := false
+
+		if inWindow(incident.FromAt) {
+			digest.Opened = append(digest.Opened, incident)
+			isOfWindow = true
+		}
+		switch {
+		case incident.Status == "finished" && inWindow(incident.ToAt):
+			digest.Finished = append(digest.Finished, incident)
+			isOfWindow = true
+		case incident.Status == "closed" && (inWindow(incident.ToAt) || (incident.ClosedAt != nil && inWindow(*incident.ClosedAt))):
+			digest.Closed = append(digest.Closed, incident)
+			isOfWindow = true
+		}
+		if incident.Type == "auto" && incident.Status == "actual" && !incident.IsConfirmed {
+			digest.Unconfirmed = append(digest.Unconfirmed, incident)
+		}
+
+		if !isOfWindow {
+			continue
+		}
+		digest.TotalFinLosses += incident.FinLosses
+		if incident.IsDowntime {
+			digest.Downtime = append(digest.Downtime, incident)
+		}
+	}
+
+	return digest
+}
@@ -0,0 +1,173 @@
+package impl // dnywonnt.me/alerts2incidents/internal/notifier/impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"dnywonnt.me/alerts2incidents/internal/cache"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// statusTracker remembers the last notified status and severity of incidents, so notifiers that can't edit
+// already sent messages only notify about new incidents and changes of status or severity.
+type statusTracker struct {
+	cache *cache.Cache // Cache of the last notified state of each incident.
+}
+
+// notifiedState is the status and severity of an incident as of its last notification.
+type notifiedState struct {
+	Status   string
+	Severity string
+}
+
+// newStatusTracker creates a new status tracker with a cache of the given size.
+func newStatusTracker(size int, tag string) *statusTracker {
+	return &statusTracker{cache: cache.NewCache(size, tag)}
+}
+
+// shouldNotify reports whether the event is worth a notification.
+// Unknown incidents on UPDATE are remembered without a notification, and deleted incidents are forgotten.
+func (st *statusTracker) shouldNotify(event *notifier.Event) bool {
+	switch event.Action {
+	case notifier.InsertAction:
+		return true
+
+	case notifier.UpdateAction:
+		item, exists := st.cache.GetItem(event.IncidentID)
+		if !exists {
+			log.WithFields(log.Fields{
+				"incidentID": event.IncidentID,
+			}).Warn("The last notified status not found in the cache for incident; skipping notification")
+			st.remember(event)
+			return false
+		}
+		lastState, ok := item.Value.(notifiedState)
+		if ok && lastState.Status != event.Incident.Status && event.Incident.IsFlapping {
+			// A flapping incident is held open, so its reopening is an intermediate change not worth a notification
+			log.WithFields(log.Fields{
+				"incidentID": event.IncidentID,
+			}).Debug("The incident is flapping; skipping notification")
+			st.remember(event)
+			return false
+		}
+		return !ok || lastState.Status != event.Incident.Status || lastState.Severity != event.Incident.Severity
+
+	case notifier.DeleteAction:
+		st.cache.DeleteItem(event.IncidentID)
+	}
+
+	return false
+}
+
+// remember stores the status and severity of the incident from the event as the last notified ones.
+func (st *statusTracker) remember(event *notifier.Event) {
+	if event.Incident != nil {
+		st.cache.SetItem(event.IncidentID, notifiedState{
+			Status:   event.Incident.Status,
+			Severity: event.Incident.Severity,
+		})
+	}
+}
+
+// resolveDepartamentTargets returns the targets configured for the departament in the "departament:target" format,
+// including the "*" entries that match any departament.
+func resolveDepartamentTargets(targetStrs []string, departament string) ([]string, error) {
+	targets := []string{}
+	seen := make(map[string]struct{})
+
+	for _, targetStr := range targetStrs {
+		parts := strings.SplitN(targetStr, ":", 2)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.New("invalid departament target string format")
+		}
+		if parts[0] != "*" && parts[0] != departament {
+			continue
+		}
+		if _, ok := seen[parts[1]]; ok {
+			continue
+		}
+		seen[parts[1]] = struct{}{}
+		targets = append(targets, parts[1])
+	}
+
+	return targets, nil
+}
+
+// resolveOnCallEmails replaces the "oncall:<schedule>" targets with the email of the user currently on call for the schedule.
+// Targets that can't be resolved are logged and skipped, so the other recipients are still notified.
+func resolveOnCallEmails(ctx context.Context, resolver notifier.OnCallResolver, targets []string) []string {
+	emails := []string{}
+	seen := make(map[string]struct{})
+
+	for _, target := range targets {
+		if strings.HasPrefix(target, notifier.OnCallTargetPrefix) {
+			scheduleName := strings.TrimPrefix(target, notifier.OnCallTargetPrefix)
+			if resolver == nil {
+				log.WithFields(log.Fields{
+					"target": target,
+				}).Warn("No on-call resolver configured; skipping the on-call target")
+				continue
+			}
+			user, err := resolver.ResolveOnCallUser(ctx, scheduleName)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":    err.Error(),
+					"schedule": scheduleName,
+				}).Error("Failed to resolve the on-call user")
+				continue
+			}
+			if user.Email == "" {
+				log.WithFields(log.Fields{
+					"schedule": scheduleName,
+					"userID":   user.ID,
+				}).Warn("The on-call user has no email; skipping the on-call target")
+				continue
+			}
+			target = user.Email
+		}
+
+		if _, ok := seen[target]; ok {
+			continue
+		}
+		seen[target] = struct{}{}
+		emails = append(emails, target)
+	}
+
+	return emails
+}
+
+// postJSONToWebhook sends the payload as JSON to an incoming webhook URL.
+func postJSONToWebhook(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error marshaling payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Both Mattermost and Teams respond with 2xx on success; report the body otherwise.
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package impl // dnywonnt.me/alerts2incidents/internal/notifier/impl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// subjectTemplateName is the name of the template defined inside the text template that renders the email subject.
+const subjectTemplateName = "subject"
+
+// EmailNotifier is a struct that holds the configuration and templates for sending incident emails over SMTP.
+type EmailNotifier struct {
+	cfg      *config.EmailNotifierConfig // Configuration for the email notifier.
+	textTmpl *texttemplate.Template      // Template for the plain-text body and the subject.
+	htmlTmpl *htmltemplate.Template      // Template for the HTML body.
+	statuses *statusTracker              // Tracker of the last notified status of each incident.
+	onCall   notifier.OnCallResolver     // Resolver of the "oncall:<schedule>" recipients; may be nil.
+}
+
+// NewEmailNotifier creates a new instance of EmailNotifier with the provided configuration.
+// It loads the plain-text and HTML templates and returns an error if any of them can't be parsed.
+// The on-call resolver is used for the "oncall:<schedule>" recipients and may be nil.
+func NewEmailNotifier(cfg *config.EmailNotifierConfig, onCall notifier.OnCallResolver) (*EmailNotifier, error) {
+	log.Debug("Initializing the email notifier")
+
+	textTmpl, err := notifier.LoadTextTemplateFromFile("emailTextTemplate", cfg.TextTemplateFilepath, cfg.TranslationsFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading text template: %w", err)
+	}
+	if textTmpl.Lookup(subjectTemplateName) == nil {
+		return nil, fmt.Errorf("text template doesn't define the %q template", subjectTemplateName)
+	}
+
+	htmlTmpl, err := notifier.LoadHTMLTemplateFromFile("emailHTMLTemplate", cfg.HTMLTemplateFilepath, cfg.TranslationsFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading HTML template: %w", err)
+	}
+
+	return &EmailNotifier{
+		cfg:      cfg,
+		textTmpl: textTmpl,
+		htmlTmpl: htmlTmpl,
+		statuses: newStatusTracker(cfg.StatusCacheMaxSize, "emailStatuses"),
+		onCall:   onCall,
+	}, nil
+}
+
+// Name returns the name of the notifier.
+func (en *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Notify implements the Notifier interface for EmailNotifier.
+// It sends the first email when an incident is created and threaded follow-ups when its status changes.
+func (en *EmailNotifier) Notify(ctx context.Context, event *notifier.Event) error {
+	if !en.statuses.shouldNotify(event) {
+		return nil
+	}
+
+	recipients, err := resolveDepartamentTargets(en.cfg.Recipients, event.Incident.Departament)
+	if err != nil {
+		return fmt.Errorf("error resolving recipients: %w", err)
+	}
+	recipients = resolveOnCallEmails(ctx, en.onCall, recipients)
+	if len(recipients) == 0 {
+		log.WithFields(log.Fields{
+			"incidentID":  event.IncidentID,
+			"departament": event.Incident.Departament,
+		}).Warn("No email recipients configured for the departament; skipping email")
+		en.statuses.remember(event)
+		return nil
+	}
+
+	// Every email after the first one of the incident is a follow-up in its thread.
+	if err := en.sendIncidentEmail(ctx, event.Incident, recipients, event.Action == notifier.UpdateAction); err != nil {
+		return err
+	}
+	en.statuses.remember(event)
+
+	return nil
+}
+
+// Escalate sends the incident to the recipients of an escalation step as a follow-up in the thread of the incident.
+func (en *EmailNotifier) Escalate(ctx context.Context, incident *models.Incident, recipients []string) error {
+	recipients = resolveOnCallEmails(ctx, en.onCall, recipients)
+	if len(recipients) == 0 {
+		return nil
+	}
+	return en.sendIncidentEmail(ctx, incident, recipients, true)
+}
+
+// sendIncidentEmail renders the incident and sends it to the recipients.
+// Follow-up emails reference the first email of the incident to keep them in one thread.
+func (en *EmailNotifier) sendIncidentEmail(ctx context.Context, incident *models.Incident, recipients []string, isFollowUp bool) error {
+	// Render the subject and both bodies of the email.
+	subject := bytes.Buffer{}
+	if err := en.textTmpl.ExecuteTemplate(&subject, subjectTemplateName, incident); err != nil {
+		return fmt.Errorf("error rendering subject: %w", err)
+	}
+	textBody := bytes.Buffer{}
+	if err := en.textTmpl.Execute(&textBody, incident); err != nil {
+		return fmt.Errorf("error rendering text body: %w", err)
+	}
+	htmlBody := bytes.Buffer{}
+	if err := en.htmlTmpl.Execute(&htmlBody, incident); err != nil {
+		return fmt.Errorf("error rendering HTML body: %w", err)
+	}
+
+	// Build the thread headers. The first email of the incident has a stable Message-ID derived from the
+	// incident ID, so follow-ups can reference it without storing anything.
+	headers := map[string]string{}
+	rootMessageID := en.buildMessageID(incident.ID, "")
+	subjectStr := strings.TrimSpace(subject.String())
+	if isFollowUp {
+		headers["Message-ID"] = en.buildMessageID(incident.ID, fmt.Sprintf("%s.%d", incident.Status, time.Now().UnixNano()))
+		headers["In-Reply-To"] = rootMessageID
+		headers["References"] = rootMessageID
+		subjectStr = "Re: " + subjectStr
+	} else {
+		headers["Message-ID"] = rootMessageID
+	}
+	headers["Subject"] = mime.QEncoding.Encode("utf-8", subjectStr)
+
+	message, err := en.buildMessage(recipients, headers, textBody.Bytes(), htmlBody.Bytes())
+	if err != nil {
+		return fmt.Errorf("error building message: %w", err)
+	}
+
+	// Authenticate only if the SMTP user is configured, e.g. a local SMTP sink doesn't need it.
+	var auth smtp.Auth
+	if en.cfg.SMTPUser != "" {
+		auth = smtp.PlainAuth("", en.cfg.SMTPUser, en.cfg.SMTPPassword, en.cfg.SMTPHost)
+	}
+
+	if err := en.sendMail(ctx, auth, recipients, message); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"incidentID": incident.ID,
+		"status":     incident.Status,
+		"recipients": recipients,
+		"isFollowUp": isFollowUp,
+	}).Info("The email has been sent for incident")
+
+	return nil
+}
+
+// sendMail sends the message to the recipients like smtp.SendMail, but within the timeout of the configuration:
+// the whole SMTP session, from dialing the server to QUIT, must fit into it, so a slow or unresponsive server
+// can't hold up the other notifiers. The session is also aborted when the context is done.
+func (en *EmailNotifier) sendMail(ctx context.Context, auth smtp.Auth, recipients []string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, en.cfg.Timeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(en.cfg.SMTPHost, strconv.Itoa(en.cfg.SMTPPort))
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("error connecting to the SMTP server: %w", err)
+	}
+	defer conn.Close()
+
+	// The deadline bounds every read and write of the session; closing the connection interrupts it on cancellation.
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("error setting the deadline: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, en.cfg.SMTPHost)
+	if err != nil {
+		return fmt.Errorf("error starting the SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: en.cfg.SMTPHost}); err != nil {
+			return fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("the SMTP server doesn't support authentication")
+		}
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+
+	if err := client.Mail(en.cfg.From); err != nil {
+		return fmt.Errorf("error setting the sender: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("error adding the recipient %s: %w", recipient, err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error starting the message data: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("error writing the message data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error finishing the message data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// buildMessage assembles a multipart/alternative email with the plain-text and HTML bodies.
+func (en *EmailNotifier) buildMessage(recipients []string, headers map[string]string, textBody, htmlBody []byte) ([]byte, error) {
+	body := bytes.Buffer{}
+	mw := multipart.NewWriter(&body)
+
+	// Write both alternatives; the plain-text part goes first as the least preferred one.
+	parts := []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain; charset=utf-8", content: textBody},
+		{contentType: "text/html; charset=utf-8", content: htmlBody},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	message := bytes.Buffer{}
+	fmt.Fprintf(&message, "From: %s\r\n", en.cfg.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	for _, name := range []string{"Subject", "Message-ID", "In-Reply-To", "References"} {
+		if value, ok := headers[name]; ok {
+			fmt.Fprintf(&message, "%s: %s\r\n", name, value)
+		}
+	}
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// buildMessageID builds a Message-ID for the incident using the domain of the sender address.
+func (en *EmailNotifier) buildMessageID(incidentID, suffix string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(en.cfg.From, "@"); at != -1 {
+		domain = en.cfg.From[at+1:]
+	}
+
+	if suffix == "" {
+		return fmt.Sprintf("<incident.%s@%s>", incidentID, domain)
+	}
+	return fmt.Sprintf("<incident.%s.%s@%s>", incidentID, suffix, domain)
+}
@@ -0,0 +1,316 @@
+package impl // dnywonnt.me/alerts2incidents/internal/notifier/impl
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+)
+
+// sinkMail is a mail received by the SMTP sink.
+type sinkMail struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// smtpSink is a local SMTP server accepting every mail, or never answering if it's black-holed.
+type smtpSink struct {
+	listener   net.Listener
+	blackHoled bool
+	done       chan struct{}
+
+	mu    sync.Mutex
+	mails []*sinkMail
+}
+
+// newSMTPSink starts an SMTP sink on a random local port; it's stopped at the end of the test.
+func newSMTPSink(t *testing.T, blackHoled bool) *smtpSink {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	sink := &smtpSink{listener: listener, blackHoled: blackHoled, done: make(chan struct{})}
+	t.Cleanup(func() {
+		close(sink.done)
+		listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+	return sink
+}
+
+// serve talks the minimal SMTP dialog of net/smtp with a client.
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	if s.blackHoled {
+		// Hold the connection open without the greeting until the test ends
+		<-s.done
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	mail := &sinkMail{}
+
+	reply("220 sink ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			mail.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			mail.recipients = append(mail.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 Go ahead")
+			data := strings.Builder{}
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			mail.data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			mail = &sinkMail{}
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Not implemented")
+		}
+	}
+}
+
+// received returns the mails received by the sink.
+func (s *smtpSink) received() []*sinkMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*sinkMail{}, s.mails...)
+}
+
+// bodies returns the decoded bodies of the parts of a multipart mail.
+func bodies(t *testing.T, mail *sinkMail) string {
+	t.Helper()
+
+	message, err := netmail.ReadMessage(strings.NewReader(mail.data))
+	if err != nil {
+		t.Fatalf("error parsing the mail: %v", err)
+	}
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("error parsing the content type of the mail: %v", err)
+	}
+
+	// The parts in the quoted-printable encoding are decoded by the multipart reader
+	decoded := strings.Builder{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error reading a part of the mail: %v", err)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("error reading a part of the mail: %v", err)
+		}
+		decoded.Write(content)
+	}
+	return decoded.String()
+}
+
+// newTestEmailNotifier creates an email notifier sending to the sink with minimal templates.
+func newTestEmailNotifier(t *testing.T, sink *smtpSink, timeout time.Duration) *EmailNotifier {
+	t.Helper()
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "email.txt.tmpl")
+	htmlPath := filepath.Join(dir, "email.html.tmpl")
+	if err := os.WriteFile(textPath, []byte(`{{define "subject"}}Incident {{.Summary}}{{end}}Status: {{.Status}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(htmlPath, []byte(`<p>Status: {{.Status}}</p>`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	host, port, _ := net.SplitHostPort(sink.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	en, err := NewEmailNotifier(&config.EmailNotifierConfig{
+		IsActive:             true,
+		SMTPHost:             host,
+		SMTPPort:             portNumber,
+		From:                 "a2i@example.com",
+		Recipients:           []string{"internal_it:it@example.com", "*:boss@example.com"},
+		TextTemplateFilepath: textPath,
+		HTMLTemplateFilepath: htmlPath,
+		StatusCacheMaxSize:   10,
+		Timeout:              timeout,
+	}, nil)
+	if err != nil {
+		t.Fatalf("error creating the notifier: %v", err)
+	}
+	return en
+}
+
+func TestEmailNotifierSendsThreadedEmails(t *testing.T) {
+	sink := newSMTPSink(t, false)
+	en := newTestEmailNotifier(t, sink, 5*time.Second)
+
+	incident := &models.Incident{ID: "42", Summary: "DB is down", Status: "actual", Departament: "internal_it"}
+	if err := en.Notify(context.Background(), &notifier.Event{Action: notifier.InsertAction, IncidentID: incident.ID, Incident: incident}); err != nil {
+		t.Fatalf("error notifying about the new incident: %v", err)
+	}
+	incident.Status = "finished"
+	if err := en.Notify(context.Background(), &notifier.Event{Action: notifier.UpdateAction, IncidentID: incident.ID, Incident: incident}); err != nil {
+		t.Fatalf("error notifying about the finished incident: %v", err)
+	}
+
+	mails := sink.received()
+	if len(mails) != 2 {
+		t.Fatalf("got %d mails, want 2", len(mails))
+	}
+
+	first, followUp := mails[0], mails[1]
+	if first.from != "a2i@example.com" {
+		t.Errorf("sender is %q, want a2i@example.com", first.from)
+	}
+	if strings.Join(first.recipients, " ") != "it@example.com boss@example.com" {
+		t.Errorf("recipients are %v, want it@example.com and boss@example.com", first.recipients)
+	}
+	if !strings.Contains(first.data, "Subject: Incident DB is down\r\n") || !strings.Contains(first.data, "Message-ID: <incident.42@example.com>\r\n") {
+		t.Errorf("the first mail has unexpected headers:\n%s", first.data)
+	}
+	if !strings.Contains(followUp.data, "Subject: Re: Incident DB is down\r\n") || !strings.Contains(followUp.data, "In-Reply-To: <incident.42@example.com>\r\n") {
+		t.Errorf("the follow-up isn't threaded:\n%s", followUp.data)
+	}
+	if !strings.Contains(followUp.data, "Status: finished") {
+		t.Errorf("the follow-up doesn't contain the new status:\n%s", followUp.data)
+	}
+}
+
+func TestEmailNotifierTimesOutOnUnresponsiveServer(t *testing.T) {
+	sink := newSMTPSink(t, true)
+	en := newTestEmailNotifier(t, sink, 200*time.Millisecond)
+
+	incident := &models.Incident{ID: "42", Summary: "DB is down", Status: "actual", Departament: "internal_it"}
+	startTime := time.Now()
+	err := en.Escalate(context.Background(), incident, []string{"it@example.com"})
+	if err == nil {
+		t.Fatal("sending to a black-holed server succeeded")
+	}
+	if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+		t.Errorf("sending took %v despite the timeout of 200ms", elapsed)
+	}
+}
+
+func TestEmailNotifierStopsOnCanceledContext(t *testing.T) {
+	sink := newSMTPSink(t, true)
+	en := newTestEmailNotifier(t, sink, 30*time.Second)
+
+	// The context has no deadline, so only its cancellation can interrupt the session
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	incident := &models.Incident{ID: "42", Summary: "DB is down", Status: "actual", Departament: "internal_it"}
+	startTime := time.Now()
+	if err := en.Escalate(ctx, incident, []string{"it@example.com"}); err == nil {
+		t.Fatal("sending to a black-holed server succeeded")
+	}
+	if elapsed := time.Since(startTime); elapsed > 2*time.Second {
+		t.Errorf("sending took %v despite the canceled context", elapsed)
+	}
+}
+
+func TestEmailNotifierTranslatesEnumValues(t *testing.T) {
+	sink := newSMTPSink(t, false)
+	host, port, _ := net.SplitHostPort(sink.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	// The templates and translations shipped with the service
+	templatesDir := filepath.Join("..", "..", "..", "templates")
+	en, err := NewEmailNotifier(&config.EmailNotifierConfig{
+		IsActive:             true,
+		SMTPHost:             host,
+		SMTPPort:             portNumber,
+		From:                 "a2i@example.com",
+		Recipients:           []string{"*:it@example.com"},
+		TextTemplateFilepath: filepath.Join(templatesDir, "email_incident_ru.txt.tmpl"),
+		HTMLTemplateFilepath: filepath.Join(templatesDir, "email_incident_ru.html.tmpl"),
+		TranslationsFilepath: filepath.Join(templatesDir, "translations_ru.yaml"),
+		StatusCacheMaxSize:   10,
+		Timeout:              5 * time.Second,
+	}, nil)
+	if err != nil {
+		t.Fatalf("error creating the notifier: %v", err)
+	}
+
+	incident := &models.Incident{
+		ID:           "42",
+		Summary:      "DB is down",
+		Status:       "actual",
+		Type:         "manual",
+		Departament:  "internal_it",
+		IsManageable: "indirectly",
+		FailureType:  "err_security",
+		Creator:      "admin",
+	}
+	if err := en.Notify(context.Background(), &notifier.Event{Action: notifier.InsertAction, IncidentID: incident.ID, Incident: incident}); err != nil {
+		t.Fatalf("error notifying about the incident: %v", err)
+	}
+
+	mails := sink.received()
+	if len(mails) != 1 {
+		t.Fatalf("got %d mails, want 1", len(mails))
+	}
+	body := bodies(t, mails[0])
+	for _, translation := range []string{"Внутренний IT", "Косвенно", "Ошибки безопасности"} {
+		if strings.Count(body, translation) != 2 {
+			t.Errorf("the text and HTML parts don't both contain %q:\n%s", translation, body)
+		}
+	}
+}
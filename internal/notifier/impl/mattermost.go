@@ -0,0 +1,145 @@
+package impl // dnywonnt.me/alerts2incidents/internal/notifier/impl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// titleTemplateName is the name of the template defined inside webhook message templates that renders the title.
+const titleTemplateName = "title"
+
+// statusColors maps incident statuses to the colors of Mattermost attachments.
+var statusColors = map[string]string{
+	"actual":   "#D32F2F",
+	"finished": "#F9A825",
+	"closed":   "#388E3C",
+}
+
+// mattermostAttachment represents a message attachment of the Mattermost incoming-webhook payload.
+type mattermostAttachment struct {
+	Fallback string `json:"fallback"`
+	Color    string `json:"color,omitempty"`
+	Title    string `json:"title"`
+	Text     string `json:"text"`
+}
+
+// mattermostPayload represents the Mattermost incoming-webhook payload.
+type mattermostPayload struct {
+	Username    string                 `json:"username,omitempty"`
+	IconURL     string                 `json:"icon_url,omitempty"`
+	Attachments []mattermostAttachment `json:"attachments"`
+}
+
+// MattermostNotifier is a struct that holds the configuration and template for posting incidents to Mattermost.
+type MattermostNotifier struct {
+	cfg      *config.MattermostNotifierConfig // Configuration for the Mattermost notifier.
+	tmpl     *template.Template               // Template for the attachment title and text.
+	statuses *statusTracker                   // Tracker of the last notified status of each incident.
+	client   http.Client                      // HTTP client used to call the incoming webhooks.
+}
+
+// NewMattermostNotifier creates a new instance of MattermostNotifier with the provided configuration.
+// It loads the message template and returns an error if it can't be parsed.
+func NewMattermostNotifier(cfg *config.MattermostNotifierConfig) (*MattermostNotifier, error) {
+	log.Debug("Initializing the Mattermost notifier")
+
+	tmpl, err := notifier.LoadTextTemplateFromFile("mattermostTemplate", cfg.TemplateFilepath, cfg.TranslationsFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading template: %w", err)
+	}
+	if tmpl.Lookup(titleTemplateName) == nil {
+		return nil, fmt.Errorf("template doesn't define the %q template", titleTemplateName)
+	}
+
+	return &MattermostNotifier{
+		cfg:      cfg,
+		tmpl:     tmpl,
+		statuses: newStatusTracker(cfg.StatusCacheMaxSize, "mattermostStatuses"),
+		client:   http.Client{Timeout: cfg.RequestTimeout},
+	}, nil
+}
+
+// Name returns the name of the notifier.
+func (mn *MattermostNotifier) Name() string {
+	return "mattermost"
+}
+
+// Notify implements the Notifier interface for MattermostNotifier.
+// Incoming webhooks can't edit posts, so it posts new incidents and their status changes.
+func (mn *MattermostNotifier) Notify(ctx context.Context, event *notifier.Event) error {
+	if !mn.statuses.shouldNotify(event) {
+		return nil
+	}
+
+	webhooks, err := resolveDepartamentTargets(mn.cfg.Webhooks, event.Incident.Departament)
+	if err != nil {
+		return fmt.Errorf("error resolving webhooks: %w", err)
+	}
+
+	title, text, err := renderTitleAndText(mn.tmpl, event.Incident)
+	if err != nil {
+		return err
+	}
+
+	payload := &mattermostPayload{
+		Username: mn.cfg.Username,
+		IconURL:  mn.cfg.IconURL,
+		Attachments: []mattermostAttachment{{
+			Fallback: title,
+			Color:    statusColors[event.Incident.Status],
+			Title:    title,
+			Text:     text,
+		}},
+	}
+
+	// Post to every webhook, and remember the status only if all of them have got it,
+	// so a failed post is reported and the status is notified again with the next event
+	var errs []error
+	for _, webhook := range webhooks {
+		if err := postJSONToWebhook(ctx, &mn.client, webhook, payload); err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"incidentID": event.IncidentID,
+			}).Error("Failed to post the incident to the Mattermost webhook")
+			errs = append(errs, err)
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"incidentID": event.IncidentID,
+			"status":     event.Incident.Status,
+		}).Info("The incident has been posted to the Mattermost webhook")
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error posting to the Mattermost webhooks: %w", errors.Join(errs...))
+	}
+	mn.statuses.remember(event)
+
+	return nil
+}
+
+// renderTitleAndText renders the title and the text of a webhook message for the incident.
+func renderTitleAndText(tmpl *template.Template, incident *models.Incident) (string, string, error) {
+	title := bytes.Buffer{}
+	if err := tmpl.ExecuteTemplate(&title, titleTemplateName, incident); err != nil {
+		return "", "", fmt.Errorf("error rendering title: %w", err)
+	}
+
+	text := bytes.Buffer{}
+	if err := tmpl.Execute(&text, incident); err != nil {
+		return "", "", fmt.Errorf("error rendering text: %w", err)
+	}
+
+	return strings.TrimSpace(title.String()), strings.TrimSpace(text.String()), nil
+}
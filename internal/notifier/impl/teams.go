@@ -0,0 +1,164 @@
+package impl // dnywonnt.me/alerts2incidents/internal/notifier/impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+
+	"dnywonnt.me/alerts2incidents/internal/config"
+	"dnywonnt.me/alerts2incidents/internal/notifier"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// statusTextColors maps incident statuses to the text colors of Adaptive Cards.
+var statusTextColors = map[string]string{
+	"actual":   "Attention",
+	"finished": "Warning",
+	"closed":   "Good",
+}
+
+// adaptiveCardElement represents a TextBlock element of an Adaptive Card.
+type adaptiveCardElement struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Wrap    bool   `json:"wrap"`
+	Weight  string `json:"weight,omitempty"`
+	Size    string `json:"size,omitempty"`
+	Color   string `json:"color,omitempty"`
+	Spacing string `json:"spacing,omitempty"`
+}
+
+// adaptiveCard represents the Adaptive Card sent to Teams.
+type adaptiveCard struct {
+	Schema  string                `json:"$schema"`
+	Type    string                `json:"type"`
+	Version string                `json:"version"`
+	Body    []adaptiveCardElement `json:"body"`
+}
+
+// teamsAttachment represents an attachment of the Teams incoming-webhook payload.
+type teamsAttachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *adaptiveCard `json:"content"`
+}
+
+// teamsPayload represents the Teams incoming-webhook payload.
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// TeamsNotifier is a struct that holds the configuration and template for posting incidents to Microsoft Teams.
+type TeamsNotifier struct {
+	cfg      *config.TeamsNotifierConfig // Configuration for the Teams notifier.
+	tmpl     *template.Template          // Template for the card title and text.
+	statuses *statusTracker              // Tracker of the last notified status of each incident.
+	client   http.Client                 // HTTP client used to call the incoming webhooks.
+}
+
+// NewTeamsNotifier creates a new instance of TeamsNotifier with the provided configuration.
+// It loads the message template and returns an error if it can't be parsed.
+func NewTeamsNotifier(cfg *config.TeamsNotifierConfig) (*TeamsNotifier, error) {
+	log.Debug("Initializing the Teams notifier")
+
+	tmpl, err := notifier.LoadTextTemplateFromFile("teamsTemplate", cfg.TemplateFilepath, cfg.TranslationsFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading template: %w", err)
+	}
+	if tmpl.Lookup(titleTemplateName) == nil {
+		return nil, fmt.Errorf("template doesn't define the %q template", titleTemplateName)
+	}
+
+	return &TeamsNotifier{
+		cfg:      cfg,
+		tmpl:     tmpl,
+		statuses: newStatusTracker(cfg.StatusCacheMaxSize, "teamsStatuses"),
+		client:   http.Client{Timeout: cfg.RequestTimeout},
+	}, nil
+}
+
+// Name returns the name of the notifier.
+func (tn *TeamsNotifier) Name() string {
+	return "teams"
+}
+
+// Notify implements the Notifier interface for TeamsNotifier.
+// Incoming webhooks can't edit posts, so it posts new incidents and their status changes.
+func (tn *TeamsNotifier) Notify(ctx context.Context, event *notifier.Event) error {
+	if !tn.statuses.shouldNotify(event) {
+		return nil
+	}
+
+	webhooks, err := resolveDepartamentTargets(tn.cfg.Webhooks, event.Incident.Departament)
+	if err != nil {
+		return fmt.Errorf("error resolving webhooks: %w", err)
+	}
+
+	title, text, err := renderTitleAndText(tn.tmpl, event.Incident)
+	if err != nil {
+		return err
+	}
+
+	// Teams collapses single line breaks inside a TextBlock, so each line of the text gets its own block.
+	body := []adaptiveCardElement{{
+		Type:   "TextBlock",
+		Text:   title,
+		Wrap:   true,
+		Weight: "Bolder",
+		Size:   "Medium",
+		Color:  statusTextColors[event.Incident.Status],
+	}}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		body = append(body, adaptiveCardElement{
+			Type:    "TextBlock",
+			Text:    line,
+			Wrap:    true,
+			Spacing: "None",
+		})
+	}
+
+	payload := &teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: &adaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+			},
+		}},
+	}
+
+	// Post to every webhook, and remember the status only if all of them have got it,
+	// so a failed post is reported and the status is notified again with the next event
+	var errs []error
+	for _, webhook := range webhooks {
+		if err := postJSONToWebhook(ctx, &tn.client, webhook, payload); err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"incidentID": event.IncidentID,
+			}).Error("Failed to post the incident to the Teams webhook")
+			errs = append(errs, err)
+			continue
+		}
+
+		log.WithFields(log.Fields{
+			"incidentID": event.IncidentID,
+			"status":     event.Incident.Status,
+		}).Info("The incident has been posted to the Teams webhook")
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error posting to the Teams webhooks: %w", errors.Join(errs...))
+	}
+	tn.statuses.remember(event)
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package notifier // dnywonnt.me/alerts2incidents/internal/notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+)
+
+// EventAction is a custom type defined as a string.
+// It's used to represent the action that happened to an incident in the database.
+type EventAction string
+
+// Below are constants of type EventAction, each representing a database notification action.
+const (
+	// InsertAction represents the creation of a new incident.
+	InsertAction EventAction = "INSERT"
+	// UpdateAction represents the update of an existing incident.
+	UpdateAction EventAction = "UPDATE"
+	// DeleteAction represents the deletion of an incident.
+	DeleteAction EventAction = "DELETE"
+)
+
+// Event represents an incident change received from the incidents notification channel.
+type Event struct {
+	Action     EventAction      // Action that happened to the incident.
+	IncidentID string           // ID of the changed incident.
+	Incident   *models.Incident // Current state of the incident; nil for the DELETE action.
+}
+
+// OnCallTargetPrefix is the prefix of the notification targets resolved to the user currently on call
+// for a schedule, e.g. "oncall:backend".
+const OnCallTargetPrefix = "oncall:"
+
+// OnCallResolver represents an interface for resolving the user currently on call for a schedule.
+type OnCallResolver interface {
+	// ResolveOnCallUser returns the user currently on call for the schedule with the given name.
+	ResolveOnCallUser(ctx context.Context, scheduleName string) (*models.OnCallUser, error)
+}
+
+// Notifier represents an interface for incident notifiers.
+type Notifier interface {
+	// Name returns a short name of the notifier used in logs.
+	Name() string
+
+	// Notify is a method that implementations of Notifier should define.
+	// It should deliver the incident change to the recipients of the notifier.
+	//
+	// ctx: a context.Context used for cancellation signals and deadlines.
+	// event: the incident change received from the database.
+	Notify(ctx context.Context, event *Event) error
+}
+
+// ParseEventPayload splits a notification payload in the "ACTION:ID" format into the action and the incident ID.
+func ParseEventPayload(payload string) (EventAction, string, error) {
+	parts := strings.SplitN(payload, ":", 2)
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("invalid payload in notification: %s", payload)
+	}
+
+	action := EventAction(parts[0])
+	switch action {
+	case InsertAction, UpdateAction, DeleteAction:
+		return action, parts[1], nil
+	default:
+		return "", "", fmt.Errorf("unknown action: %s", action)
+	}
+}
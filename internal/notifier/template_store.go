@@ -0,0 +1,333 @@
+package notifier // dnywonnt.me/alerts2incidents/internal/notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"dnywonnt.me/alerts2incidents/internal/models"
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// templateFileExt is the extension of the template files in a template directory.
+	templateFileExt = ".tmpl"
+	// partialFilePrefix is the prefix of the files with shared definitions that are parsed into every template.
+	partialFilePrefix = "_"
+	// defaultTemplateName is the name of the template used when no more specific template exists.
+	defaultTemplateName = "default"
+	// translationsFileName is the name of the optional file with translations of the enum values.
+	translationsFileName = "translations.yaml"
+)
+
+// TemplateStore holds the message templates of a directory and selects them by the incident status and type.
+// The directory contains "default.tmpl" and optional "<status>.tmpl", "<type>.tmpl" and "<type>_<status>.tmpl" files;
+// files starting with "_" hold shared definitions available to every template.
+// The optional "translations.yaml" file maps groups of enum values to their translations for the "tr" function.
+type TemplateStore struct {
+	dirpath   string                        // Path to the template directory.
+	sample    interface{}                   // Empty value of the rendered data used to check the templates.
+	templates map[string]*template.Template // Parsed templates by the file name without the extension.
+	mu        sync.RWMutex                  // Mutex for safe concurrent access.
+}
+
+// NewTemplateStore creates a new template store and loads the templates from the directory.
+// The sample is an empty value of the data the templates render, e.g. &models.Incident{}.
+// It returns an error if the default template is missing or can't be parsed.
+func NewTemplateStore(dirpath string, sample interface{}) (*TemplateStore, error) {
+	log.WithFields(log.Fields{
+		"dirpath": dirpath,
+	}).Debug("Initializing a new template store")
+
+	ts := &TemplateStore{
+		dirpath:   dirpath,
+		sample:    sample,
+		templates: make(map[string]*template.Template),
+	}
+	if err := ts.reload(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// Render renders the incident with the most specific template available for it.
+func (ts *TemplateStore) Render(incident *models.Incident) (string, error) {
+	return executeTemplate(ts.selectTemplate(incident), incident)
+}
+
+// RenderNamed renders the incident with the template of the given name, e.g. "escalation",
+// falling back to the template selected by Render if the directory doesn't have it.
+func (ts *TemplateStore) RenderNamed(name string, incident *models.Incident) (string, error) {
+	ts.mu.RLock()
+	tmpl, ok := ts.templates[name]
+	ts.mu.RUnlock()
+
+	if !ok {
+		return ts.Render(incident)
+	}
+	return executeTemplate(tmpl, incident)
+}
+
+// RenderDefault renders the data with the default template, e.g. for the stores of data other than incidents.
+func (ts *TemplateStore) RenderDefault(data interface{}) (string, error) {
+	ts.mu.RLock()
+	tmpl := ts.templates[defaultTemplateName]
+	ts.mu.RUnlock()
+
+	return executeTemplate(tmpl, data)
+}
+
+// executeTemplate executes the template with the data and returns the result as a string.
+func executeTemplate(tmpl *template.Template, data interface{}) (string, error) {
+	buf := bytes.Buffer{}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error executing template %s: %w", tmpl.Name(), err)
+	}
+
+	return buf.String(), nil
+}
+
+// Watch reloads the templates whenever the files of the directory change until the context is cancelled.
+func (ts *TemplateStore) Watch(ctx context.Context) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Error("Failed to create a template directory watcher; templates won't be reloaded")
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(ts.dirpath); err != nil {
+		log.WithFields(log.Fields{
+			"error":   err.Error(),
+			"dirpath": ts.dirpath,
+		}).Error("Failed to watch the template directory; templates won't be reloaded")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"dirpath": ts.dirpath,
+	}).Debug("Watching the template directory for changes")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.WithFields(log.Fields{
+				"dirpath": ts.dirpath,
+			}).Debug("Stopping watching the template directory")
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if (filepath.Ext(event.Name) != templateFileExt && filepath.Base(event.Name) != translationsFileName) ||
+				event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			log.WithFields(log.Fields{
+				"file": event.Name,
+				"op":   event.Op.String(),
+			}).Info("Template file changed; reloading templates")
+			if err := ts.reload(); err != nil {
+				log.WithFields(log.Fields{
+					"error":   err.Error(),
+					"dirpath": ts.dirpath,
+				}).Error("Failed to reload templates; keeping the previous versions")
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithFields(log.Fields{
+				"error":   err.Error(),
+				"dirpath": ts.dirpath,
+			}).Error("Template directory watcher failed")
+		}
+	}
+}
+
+// selectTemplate returns the template for the incident, from the most specific one to the default one.
+func (ts *TemplateStore) selectTemplate(incident *models.Incident) *template.Template {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	for _, name := range []string{
+		incident.Type + "_" + incident.Status,
+		incident.Status,
+		incident.Type,
+	} {
+		if tmpl, ok := ts.templates[name]; ok {
+			return tmpl
+		}
+	}
+
+	return ts.templates[defaultTemplateName]
+}
+
+// reload parses all templates of the directory and replaces the current ones.
+// A template that fails to parse is logged and its previous version stays in use.
+func (ts *TemplateStore) reload() error {
+	entries, err := os.ReadDir(ts.dirpath)
+	if err != nil {
+		return fmt.Errorf("error reading directory: %w", err)
+	}
+
+	// Read the shared definitions first, since every template is parsed together with them.
+	partials := make(map[string]string)
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != templateFileExt {
+			continue
+		}
+		if strings.HasPrefix(entry.Name(), partialFilePrefix) {
+			content, err := os.ReadFile(filepath.Join(ts.dirpath, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("error reading file %s: %w", entry.Name(), err)
+			}
+			partials[entry.Name()] = string(content)
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), templateFileExt))
+	}
+
+	translations, err := loadTranslations(filepath.Join(ts.dirpath, translationsFileName))
+	if err != nil {
+		return fmt.Errorf("error loading translations: %w", err)
+	}
+	funcs := TemplateFuncs()
+	funcs["tr"] = translateFunc(translations)
+
+	ts.mu.RLock()
+	previous := ts.templates
+	ts.mu.RUnlock()
+
+	templates := make(map[string]*template.Template)
+	for _, name := range names {
+		tmpl, err := parseTemplateWithPartials(name, filepath.Join(ts.dirpath, name+templateFileExt), partials, funcs, ts.sample)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":    err.Error(),
+				"template": name,
+			}).Error("Failed to parse template; rejecting it")
+			if prevTmpl, ok := previous[name]; ok {
+				templates[name] = prevTmpl
+			}
+			continue
+		}
+		templates[name] = tmpl
+	}
+
+	if _, ok := templates[defaultTemplateName]; !ok {
+		return errors.New("default template is missing or invalid")
+	}
+
+	ts.mu.Lock()
+	ts.templates = templates
+	ts.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"dirpath":        ts.dirpath,
+		"templatesCount": len(templates),
+	}).Debug("Templates have been loaded")
+
+	return nil
+}
+
+// parseTemplateWithPartials parses a template file together with the shared definitions.
+// The template is also executed against the sample to reject references to unknown fields and templates.
+func parseTemplateWithPartials(name, filePath string, partials map[string]string, funcs template.FuncMap, sample interface{}) (*template.Template, error) {
+	tmpl := template.New(name).Funcs(funcs)
+	for partialName, partial := range partials {
+		if _, err := tmpl.New(partialName).Parse(partial); err != nil {
+			return nil, fmt.Errorf("error parsing shared definitions %s: %w", partialName, err)
+		}
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	if _, err := tmpl.Parse(string(content)); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("error executing template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// loadTranslations reads the translations file of a template directory.
+// A missing file isn't an error, in which case every value is rendered as is.
+func loadTranslations(filePath string) (map[string]map[string]string, error) {
+	translations := make(map[string]map[string]string)
+
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return translations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(content, &translations); err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	return translations, nil
+}
+
+// translateFunc returns the "tr" template function, which translates a value of a group, e.g. {{tr "departament" .Departament}}.
+// Values without a translation are returned as is.
+func translateFunc(translations map[string]map[string]string) func(group, value string) string {
+	return func(group, value string) string {
+		if translation, ok := translations[group][value]; ok {
+			return translation
+		}
+		return value
+	}
+}
+
+// LoadLocaleTemplateStores creates a template store for each locale subdirectory of the directory, keyed by the locale.
+func LoadLocaleTemplateStores(dirpath string, sample interface{}) (map[string]*TemplateStore, error) {
+	entries, err := os.ReadDir(dirpath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory: %w", err)
+	}
+
+	stores := make(map[string]*TemplateStore)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		store, err := NewTemplateStore(filepath.Join(dirpath, entry.Name()), sample)
+		if err != nil {
+			return nil, fmt.Errorf("error loading templates of locale %s: %w", entry.Name(), err)
+		}
+		stores[entry.Name()] = store
+	}
+
+	if len(stores) == 0 {
+		return nil, errors.New("no locale directories found")
+	}
+
+	return stores, nil
+}
@@ -0,0 +1,87 @@
+package notifier // dnywonnt.me/alerts2incidents/internal/notifier
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	texttemplate "text/template"
+
+	"dnywonnt.me/alerts2incidents/internal/utils"
+)
+
+// TemplateFuncs returns the helper functions available inside all notification templates.
+// The "tr" function renders the values as is; the loaders replace it with the one of their translations.
+func TemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"tr":                  translateFunc(nil),
+		"joinWithCommas":      utils.JoinWithCommas,
+		"escapeMDV2":          utils.EscapeMarkdownV2,
+		"derefStr":            utils.DerefStr,
+		"prettyJSON":          utils.PrettyJSON,
+		"formatNumWithCommas": utils.FormatNumberWithCommas,
+		"sinceNow":            utils.FormatDurationSinceNow,
+	}
+}
+
+// templateFuncsWithTranslations returns the helper functions with the "tr" function of the translations file.
+// Without the file, i.e. if the filepath is empty, the values are rendered as is.
+func templateFuncsWithTranslations(translationsFilepath string) (map[string]interface{}, error) {
+	funcs := TemplateFuncs()
+	if translationsFilepath == "" {
+		return funcs, nil
+	}
+
+	// Unlike in a template directory, the configured translations file must exist
+	if _, err := os.Stat(translationsFilepath); err != nil {
+		return nil, fmt.Errorf("error reading translations file: %w", err)
+	}
+	translations, err := loadTranslations(translationsFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading translations: %w", err)
+	}
+	funcs["tr"] = translateFunc(translations)
+
+	return funcs, nil
+}
+
+// LoadTextTemplateFromFile loads a text template with the helper functions from a file.
+// The "tr" function translates the values with the translations file, if any, see TemplateStore for its format.
+func LoadTextTemplateFromFile(name, filepath, translationsFilepath string) (*texttemplate.Template, error) {
+	funcs, err := templateFuncsWithTranslations(translationsFilepath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	tmpl, err := texttemplate.New(name).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// LoadHTMLTemplateFromFile loads an HTML template with the helper functions from a file.
+// The "tr" function translates the values with the translations file, if any, see TemplateStore for its format.
+func LoadHTMLTemplateFromFile(name, filepath, translationsFilepath string) (*htmltemplate.Template, error) {
+	funcs, err := templateFuncsWithTranslations(translationsFilepath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	tmpl, err := htmltemplate.New(name).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	return tmpl, nil
+}
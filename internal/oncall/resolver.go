@@ -0,0 +1,100 @@
+package oncall // dnywonnt.me/alerts2incidents/internal/oncall
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// usersReader is the part of the on-call users repository used by the resolver.
+type usersReader interface {
+	GetOnCallUser(ctx context.Context, id string) (*models.OnCallUser, error)
+}
+
+// schedulesReader is the part of the on-call schedules repository used by the resolver.
+type schedulesReader interface {
+	GetOnCallScheduleByName(ctx context.Context, name string) (*models.OnCallSchedule, error)
+}
+
+// overridesReader is the part of the on-call overrides repository used by the resolver.
+type overridesReader interface {
+	GetActiveOnCallOverride(ctx context.Context, scheduleID string, at time.Time) (*models.OnCallOverride, error)
+}
+
+// Resolver resolves who is on call for a schedule, taking the overrides of the schedule into account.
+type Resolver struct {
+	usersRepo     usersReader     // Repository for on-call users
+	schedulesRepo schedulesReader // Repository for on-call schedules
+	overridesRepo overridesReader // Repository for on-call overrides
+}
+
+// NewResolver creates a new instance of Resolver with the provided repositories.
+func NewResolver(usersRepo *repositories.OnCallUsersRepository, schedulesRepo *repositories.OnCallSchedulesRepository,
+	overridesRepo *repositories.OnCallOverridesRepository) *Resolver {
+	log.Debug("Initializing the on-call resolver")
+
+	return &Resolver{
+		usersRepo:     usersRepo,
+		schedulesRepo: schedulesRepo,
+		overridesRepo: overridesRepo,
+	}
+}
+
+// ShiftAt returns the on-call shift of the schedule with the given name at the given time.
+// An active override of the schedule takes precedence over its rotation.
+func (r *Resolver) ShiftAt(ctx context.Context, scheduleName string, at time.Time) (*models.OnCallShift, error) {
+	schedule, err := r.schedulesRepo.GetOnCallScheduleByName(ctx, scheduleName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting schedule: %w", err)
+	}
+
+	shift := &models.OnCallShift{
+		Schedule: schedule,
+	}
+
+	override, err := r.overridesRepo.GetActiveOnCallOverride(ctx, schedule.ID, at)
+	if err != nil {
+		return nil, fmt.Errorf("error getting active override: %w", err)
+	}
+
+	userID := ""
+	if override != nil {
+		userID = override.UserID
+		shift.IsOverride = true
+		shift.FromAt = override.FromAt
+		shift.ToAt = override.ToAt
+	} else {
+		userID, shift.FromAt, shift.ToAt, err = schedule.ShiftAt(at)
+		if err != nil {
+			return nil, fmt.Errorf("error calculating shift: %w", err)
+		}
+	}
+
+	shift.User, err = r.usersRepo.GetOnCallUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user %s: %w", userID, err)
+	}
+
+	return shift, nil
+}
+
+// ResolveOnCallUser returns the user currently on call for the schedule with the given name.
+func (r *Resolver) ResolveOnCallUser(ctx context.Context, scheduleName string) (*models.OnCallUser, error) {
+	shift, err := r.ShiftAt(ctx, scheduleName, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"schedule":   scheduleName,
+		"userID":     shift.User.ID,
+		"isOverride": shift.IsOverride,
+	}).Debug("The on-call user has been resolved")
+
+	return shift.User, nil
+}
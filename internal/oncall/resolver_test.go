@@ -0,0 +1,140 @@
+package oncall // dnywonnt.me/alerts2incidents/internal/oncall
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dnywonnt.me/alerts2incidents/internal/database/repositories"
+	"dnywonnt.me/alerts2incidents/internal/models"
+)
+
+// fakeUsers is an in-memory repository of the on-call users by ID.
+type fakeUsers map[string]*models.OnCallUser
+
+func (f fakeUsers) GetOnCallUser(_ context.Context, id string) (*models.OnCallUser, error) {
+	if user, ok := f[id]; ok {
+		return user, nil
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// fakeSchedules is an in-memory repository of the on-call schedules by name.
+type fakeSchedules map[string]*models.OnCallSchedule
+
+func (f fakeSchedules) GetOnCallScheduleByName(_ context.Context, name string) (*models.OnCallSchedule, error) {
+	if schedule, ok := f[name]; ok {
+		return schedule, nil
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// fakeOverrides is an in-memory repository of the on-call overrides; like the query of the repository,
+// it returns the latest created override active at the time.
+type fakeOverrides struct {
+	overrides []*models.OnCallOverride
+	err       error
+}
+
+func (f *fakeOverrides) GetActiveOnCallOverride(_ context.Context, scheduleID string, at time.Time) (*models.OnCallOverride, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var active *models.OnCallOverride
+	for _, override := range f.overrides {
+		if override.ScheduleID != scheduleID || at.Before(override.FromAt) || !at.Before(override.ToAt) {
+			continue
+		}
+		if active == nil || override.CreatedAt.After(active.CreatedAt) {
+			active = override
+		}
+	}
+	return active, nil
+}
+
+func TestResolverShiftAt(t *testing.T) {
+	utc := func(day, hour int) time.Time {
+		return time.Date(2024, 4, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	users := fakeUsers{
+		"alice": {ID: "alice", Name: "Alice"},
+		"bob":   {ID: "bob", Name: "Bob"},
+		"carol": {ID: "carol", Name: "Carol"},
+		"dave":  {ID: "dave", Name: "Dave"},
+	}
+	// Daily shifts from 09:00 UTC: alice on April 1, bob on April 2, alice on April 3 and so on
+	schedules := fakeSchedules{
+		"backend": {ID: "s1", Name: "backend", Timezone: "UTC", Rotation: "daily", RotationStart: utc(1, 9), UserIDs: []string{"alice", "bob"}},
+	}
+	overrides := &fakeOverrides{overrides: []*models.OnCallOverride{
+		{ID: "o1", ScheduleID: "s1", UserID: "carol", FromAt: utc(2, 12), ToAt: utc(2, 18), CreatedAt: utc(1, 0)},
+		// A later override takes precedence where the overrides overlap
+		{ID: "o2", ScheduleID: "s1", UserID: "dave", FromAt: utc(2, 15), ToAt: utc(2, 20), CreatedAt: utc(1, 1)},
+	}}
+	resolver := &Resolver{usersRepo: users, schedulesRepo: schedules, overridesRepo: overrides}
+
+	tests := []struct {
+		name           string
+		at             time.Time
+		wantUserID     string
+		wantIsOverride bool
+		wantFromAt     time.Time
+		wantToAt       time.Time
+	}{
+		{"rotation", utc(2, 10), "bob", false, utc(2, 9), utc(3, 9)},
+		{"next shift of the rotation", utc(3, 10), "alice", false, utc(3, 9), utc(4, 9)},
+		{"override", utc(2, 13), "carol", true, utc(2, 12), utc(2, 18)},
+		{"override starts inclusively", utc(2, 12), "carol", true, utc(2, 12), utc(2, 18)},
+		{"latest of the overlapping overrides", utc(2, 16), "dave", true, utc(2, 15), utc(2, 20)},
+		{"override ends exclusively", utc(2, 20), "bob", false, utc(2, 9), utc(3, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift, err := resolver.ShiftAt(context.Background(), "backend", tt.at)
+			if err != nil {
+				t.Fatalf("error resolving the shift: %v", err)
+			}
+			if shift.User.ID != tt.wantUserID || shift.IsOverride != tt.wantIsOverride {
+				t.Errorf("user is %s (override: %t), want %s (override: %t)", shift.User.ID, shift.IsOverride, tt.wantUserID, tt.wantIsOverride)
+			}
+			if !shift.FromAt.Equal(tt.wantFromAt) || !shift.ToAt.Equal(tt.wantToAt) {
+				t.Errorf("shift is %v - %v, want %v - %v", shift.FromAt, shift.ToAt, tt.wantFromAt, tt.wantToAt)
+			}
+			if shift.Schedule.Name != "backend" {
+				t.Errorf("schedule is %s, want backend", shift.Schedule.Name)
+			}
+		})
+	}
+}
+
+func TestResolverShiftAtErrors(t *testing.T) {
+	at := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+	schedules := fakeSchedules{
+		"backend": {ID: "s1", Name: "backend", Timezone: "UTC", Rotation: "daily", RotationStart: at, UserIDs: []string{"ghost"}},
+	}
+	overridesErr := errors.New("connection refused")
+
+	tests := []struct {
+		name         string
+		scheduleName string
+		overrides    *fakeOverrides
+		wantErr      error
+	}{
+		{"unknown schedule", "frontend", &fakeOverrides{}, repositories.ErrNotFound},
+		{"unknown user", "backend", &fakeOverrides{}, repositories.ErrNotFound},
+		{"failed overrides", "backend", &fakeOverrides{err: overridesErr}, overridesErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := &Resolver{usersRepo: fakeUsers{}, schedulesRepo: schedules, overridesRepo: tt.overrides}
+			if _, err := resolver.ShiftAt(context.Background(), tt.scheduleName, at); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error is %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -96,6 +96,7 @@ func (zc *ZabbixCollector) fetchData(ctx context.Context) ([]byte, error) {
 	}).Debug("Fetching data from the Zabbix")
 
 	// Construct the request payload for the Zabbix JSON-RPC API.
+	// The triggers recently recovered (value 0) are requested as well, so the incidents are finished promptly.
 	requestPayload := map[string]interface{}{
 		"jsonrpc": "2.0",
 		"method":  "trigger.get",
@@ -107,9 +108,6 @@ func (zc *ZabbixCollector) fetchData(ctx context.Context) ([]byte, error) {
 			"expandDescription":           1,
 			"selectHosts":                 []string{"host"},
 			"monitored":                   1,
-			"filter": map[string]interface{}{
-				"value": 1,
-			},
 		},
 		"auth": zc.cfg.Token,
 		"id":   1,
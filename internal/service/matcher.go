@@ -10,6 +10,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// FindResolvedFingerprints returns the set of fingerprints of the alerts reported as resolved by their sources.
+func FindResolvedFingerprints(alerts []models.Alert) map[string]struct{} {
+	resolvedFingerprints := make(map[string]struct{})
+	for _, alert := range alerts {
+		if alert.IsResolved && alert.Fingerprint != "" {
+			resolvedFingerprints[alert.Fingerprint] = struct{}{}
+		}
+	}
+	return resolvedFingerprints
+}
+
 // FindMatchingAlerts checks a slice of alerts against a rule's conditions to find matches.
 func FindMatchingAlerts(alerts []models.Alert, rule *models.Rule) ([]models.Alert, error) {
 	// Logging the start of the matching process with relevant rule and alerts info.
@@ -30,8 +41,8 @@ func FindMatchingAlerts(alerts []models.Alert, rule *models.Rule) ([]models.Aler
 
 		// Check each alert against the current condition.
 		for j, alert := range alerts {
-			if _, used := usedAlertIndexes[j]; used {
-				continue // Skip alerts that have already been matched.
+			if _, used := usedAlertIndexes[j]; used || alert.IsResolved {
+				continue // Skip alerts that have already been matched or have been resolved.
 			}
 
 			// QuoteMeta escapes special characters in `condition` for safe use in
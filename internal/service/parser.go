@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strconv"
 	"time"
 
@@ -15,13 +17,18 @@ import (
 
 // AlertsParser is a struct that holds configuration details for parsing alerts.
 type AlertsParser struct {
-	cfg *config.AlertsParserConfig
+	cfg            *config.AlertsParserConfig
+	lastAMAlerts   map[string]models.Alert // Active Grafana Alertmanager alerts of the previous response by their fingerprints.
+	isAMAlertsSeen bool                    // Flag indicating if a Grafana Alertmanager response has been parsed yet.
 }
 
 // NewAlertsParser initializes a new instance of AlertsParser with the provided configuration.
 func NewAlertsParser(cfg *config.AlertsParserConfig) *AlertsParser {
 	log.Debug("Initializing the alerts parser")
-	return &AlertsParser{cfg: cfg}
+	return &AlertsParser{
+		cfg:          cfg,
+		lastAMAlerts: make(map[string]models.Alert),
+	}
 }
 
 // ParseAndAggregateAlerts listens for incoming alert data and aggregates them over a specified interval.
@@ -123,7 +130,9 @@ func (ap *AlertsParser) ParseAndAggregateAlerts(ctx context.Context, dataCh <-ch
 }
 
 // parseGrafanaAMAlerts parses alerts from Grafana Alertmanager's JSON data.
-func (ap AlertsParser) parseGrafanaAMAlerts(jsonData []byte) ([]models.Alert, error) {
+// The Alertmanager API leaves resolved alerts out, so the alerts of the previous response missing from this one
+// are reported as resolved, as well as the alerts whose end time has passed.
+func (ap *AlertsParser) parseGrafanaAMAlerts(jsonData []byte) ([]models.Alert, error) {
 	log.WithFields(log.Fields{
 		"parseField": ap.cfg.GrafanaAMParseField,
 	}).Debug("Parsing Grafana Alertmanager alerts")
@@ -136,7 +145,13 @@ func (ap AlertsParser) parseGrafanaAMAlerts(jsonData []byte) ([]models.Alert, er
 			Summary     *string `json:"summary,omitempty"`
 			Description *string `json:"description,omitempty"`
 		} `json:"annotations"`
-		StartsAt string `json:"startsAt"`
+		Labels      map[string]string `json:"labels"`
+		Fingerprint string            `json:"fingerprint"`
+		StartsAt    string            `json:"startsAt"`
+		EndsAt      string            `json:"endsAt"`
+		Status      struct {
+			State string `json:"state"`
+		} `json:"status"`
 	}
 
 	// Unmarshal the JSON data into the predefined structure.
@@ -144,6 +159,8 @@ func (ap AlertsParser) parseGrafanaAMAlerts(jsonData []byte) ([]models.Alert, er
 		return nil, fmt.Errorf("error unmarshaling response data: %w", err)
 	}
 
+	currentAMAlerts := make(map[string]models.Alert)
+
 	// Convert each alert from the JSON structure to the Alert model.
 	for _, grafanaAMAlert := range responseData {
 		startsAtTime, err := time.Parse(time.RFC3339, grafanaAMAlert.StartsAt)
@@ -165,11 +182,38 @@ func (ap AlertsParser) parseGrafanaAMAlerts(jsonData []byte) ([]models.Alert, er
 			}
 		}
 
-		alerts = append(alerts, models.Alert{
+		alert := models.Alert{
 			Summary:   summary,
+			Host:      labelsHost(grafanaAMAlert.Labels),
+			Severity:  models.SeverityFromAlert(grafanaAMAlert.Labels["severity"]),
 			CreatedAt: startsAtTime.UTC(),
-		})
+		}
+		if grafanaAMAlert.Fingerprint != "" {
+			alert.Fingerprint = "grafana-am:" + grafanaAMAlert.Fingerprint
+		}
+
+		// The alert is resolved if its end time has passed; active alerts have the end time in the future
+		endsAtTime, err := time.Parse(time.RFC3339, grafanaAMAlert.EndsAt)
+		if grafanaAMAlert.Status.State == "resolved" || (err == nil && !endsAtTime.IsZero() && endsAtTime.Before(time.Now())) {
+			alert.IsResolved = true
+		} else if alert.Fingerprint != "" {
+			currentAMAlerts[alert.Fingerprint] = alert
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	// Report the alerts that have disappeared since the previous response as resolved
+	if ap.isAMAlertsSeen {
+		for fingerprint, alert := range ap.lastAMAlerts {
+			if _, ok := currentAMAlerts[fingerprint]; !ok {
+				alert.IsResolved = true
+				alerts = append(alerts, alert)
+			}
+		}
 	}
+	ap.lastAMAlerts = currentAMAlerts
+	ap.isAMAlertsSeen = true
 
 	log.WithFields(log.Fields{
 		"parseField":  ap.cfg.GrafanaAMParseField,
@@ -195,8 +239,9 @@ func (ap *AlertsParser) parseGrafanaPrometheusAlerts(jsonData []byte) ([]models.
 					Summary     *string `json:"summary,omitempty"`
 					Description *string `json:"description,omitempty"`
 				} `json:"annotations"`
-				State    string `json:"state"`
-				ActiveAt string `json:"activeAt"`
+				Labels   map[string]string `json:"labels"`
+				State    string            `json:"state"`
+				ActiveAt string            `json:"activeAt"`
 			} `json:"alerts"`
 		} `json:"data"`
 	}
@@ -232,8 +277,11 @@ func (ap *AlertsParser) parseGrafanaPrometheusAlerts(jsonData []byte) ([]models.
 		}
 
 		alerts = append(alerts, models.Alert{
-			Summary:   summary,
-			CreatedAt: activeAtTime.UTC(),
+			Summary:     summary,
+			Host:        labelsHost(grafanaPrometheusAlert.Labels),
+			Severity:    models.SeverityFromAlert(grafanaPrometheusAlert.Labels["severity"]),
+			Fingerprint: labelsFingerprint("grafana-prometheus:", grafanaPrometheusAlert.Labels),
+			CreatedAt:   activeAtTime.UTC(),
 		})
 	}
 
@@ -254,8 +302,11 @@ func parseZabbixAlerts(jsonData []byte) ([]models.Alert, error) {
 	// Define the structure to which the JSON data will be unmarshaled.
 	var responseData struct {
 		Result []struct {
+			TriggerID   string `json:"triggerid"`
 			Description string `json:"description"`
 			LastChange  string `json:"lastchange"`
+			Value       string `json:"value"`
+			Priority    string `json:"priority"`
 			Hosts       []struct {
 				Host string `json:"host"`
 			} `json:"hosts"`
@@ -275,9 +326,14 @@ func parseZabbixAlerts(jsonData []byte) ([]models.Alert, error) {
 		}
 		lastChange := time.Unix(lastChangeTime, 0)
 
+		// The trigger value 0 means the problem has been recovered
 		alerts = append(alerts, models.Alert{
-			Summary:   fmt.Sprintf("[%s] %s", zabbixTrigger.Hosts[0].Host, zabbixTrigger.Description),
-			CreatedAt: lastChange.UTC(),
+			Summary:     fmt.Sprintf("[%s] %s", zabbixTrigger.Hosts[0].Host, zabbixTrigger.Description),
+			Host:        zabbixTrigger.Hosts[0].Host,
+			Severity:    zabbixPrioritySeverities[zabbixTrigger.Priority],
+			Fingerprint: "zabbix:" + zabbixTrigger.TriggerID,
+			IsResolved:  zabbixTrigger.Value == "0",
+			CreatedAt:   lastChange.UTC(),
 		})
 	}
 
@@ -287,3 +343,39 @@ func parseZabbixAlerts(jsonData []byte) ([]models.Alert, error) {
 
 	return alerts, nil
 }
+
+// zabbixPrioritySeverities maps the priorities of Zabbix triggers to the incident severities.
+var zabbixPrioritySeverities = map[string]string{
+	"5": "SEV1", // Disaster
+	"4": "SEV2", // High
+	"3": "SEV3", // Average
+	"2": "SEV4", // Warning
+	"1": "SEV4", // Information
+}
+
+// labelsHost returns the host of an alert from its "host" label, or from its "instance" label if there's no such label.
+func labelsHost(labels map[string]string) string {
+	if host, ok := labels["host"]; ok {
+		return host
+	}
+	return labels["instance"]
+}
+
+// labelsFingerprint returns the fingerprint of an alert identified by its labels, or an empty string if it has no labels.
+func labelsFingerprint(prefix string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := fnv.New64a()
+	for _, name := range names {
+		hash.Write([]byte(name + "\xff" + labels[name] + "\xff"))
+	}
+	return fmt.Sprintf("%s%016x", prefix, hash.Sum64())
+}
@@ -0,0 +1,217 @@
+package utils // dnywonnt.me/alerts2incidents/internal/utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes the bounds of a field of a cron expression.
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+// cronFields lists the fields of a cron expression in their order.
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// cronMacros maps the supported shorthand schedules to their cron expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// CronSchedule represents a parsed cron expression with a minute resolution.
+type CronSchedule struct {
+	minutes    uint64         // Bit set of the matching minutes.
+	hours      uint64         // Bit set of the matching hours.
+	daysOfMon  uint64         // Bit set of the matching days of the month.
+	months     uint64         // Bit set of the matching months.
+	daysOfWeek uint64         // Bit set of the matching days of the week, Sunday is 0.
+	anyDay     bool           // Flag indicating if either of the day fields is "*", so both of them must match.
+	location   *time.Location // Time zone the schedule is evaluated in.
+}
+
+// ParseCronSchedule parses a standard five-field cron expression ("minute hour day-of-month month day-of-week").
+// Fields support "*", values, ranges, lists and steps, e.g. "*/15 9-18 * * 1-5"; "7" is accepted as Sunday.
+// The expression may also be one of the macros like "@daily" and may be prefixed with "CRON_TZ=<zone>"
+// to be evaluated in a time zone other than the local one.
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	schedule := &CronSchedule{location: time.Local}
+
+	// Take the time zone prefix off the expression
+	if strings.HasPrefix(expr, "CRON_TZ=") {
+		tzAndExpr := strings.SplitN(strings.TrimPrefix(expr, "CRON_TZ="), " ", 2)
+		if len(tzAndExpr) != 2 {
+			return nil, errors.New("cron expression is missing after the time zone")
+		}
+		location, err := time.LoadLocation(tzAndExpr[0])
+		if err != nil {
+			return nil, fmt.Errorf("error loading time zone: %w", err)
+		}
+		schedule.location = location
+		expr = strings.TrimSpace(tzAndExpr[1])
+	}
+
+	if macroExpr, ok := cronMacros[expr]; ok {
+		expr = macroExpr
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression must have %d fields, got %d", len(cronFields), len(fields))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	schedule.minutes, schedule.hours, schedule.daysOfMon, schedule.months, schedule.daysOfWeek = sets[0], sets[1], sets[2], sets[3], sets[4]
+	schedule.anyDay = strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*")
+
+	return schedule, nil
+}
+
+// Next returns the first time matching the schedule strictly after the given time.
+// It returns the zero time if nothing matches within five years, e.g. for "0 0 31 2 *".
+// As in robfig/cron, the times skipped by a DST change don't match, and the times repeated by it match in both offsets.
+func (cs *CronSchedule) Next(t time.Time) time.Time {
+	t = t.In(cs.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if cs.months&(1<<uint(t.Month())) == 0 {
+			t = cs.wallClockTime(t.Year(), t.Month()+1, 1, 0)
+			continue
+		}
+		if !cs.matchesDay(t) {
+			t = cs.wallClockTime(t.Year(), t.Month(), t.Day()+1, 0)
+			continue
+		}
+		if cs.hours&(1<<uint(t.Hour())) == 0 {
+			t = cs.wallClockTime(t.Year(), t.Month(), t.Day(), t.Hour()+1)
+			continue
+		}
+		if cs.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// wallClockTime returns the start of the hour of the wall clock in the time zone of the schedule.
+// If a DST change skips the hour, time.Date moves it back before the change, which would make Next loop forever,
+// so the change itself, the first time after the skipped one, is returned instead.
+func (cs *CronSchedule) wallClockTime(year int, month time.Month, day, hour int) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, cs.location)
+
+	wallClock := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	if time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC).Before(wallClock) {
+		_, change := t.ZoneBounds()
+		return change
+	}
+
+	return t
+}
+
+// matchesDay checks the day fields; as in cron, if both of them are restricted, either of them has to match.
+func (cs *CronSchedule) matchesDay(t time.Time) bool {
+	domMatches := cs.daysOfMon&(1<<uint(t.Day())) != 0
+	dowMatches := cs.daysOfWeek&(1<<uint(t.Weekday())) != 0
+	if cs.anyDay {
+		return domMatches && dowMatches
+	}
+	return domMatches || dowMatches
+}
+
+// parseCronField parses a field of a cron expression into the bit set of the matching values.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var set uint64
+
+	for _, item := range strings.Split(field, ",") {
+		// Split the optional step off the range
+		rangeAndStep := strings.SplitN(item, "/", 2)
+		step := 1
+		if len(rangeAndStep) == 2 {
+			var err error
+			step, err = strconv.Atoi(rangeAndStep[1])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q in the %s field", rangeAndStep[1], bounds.name)
+			}
+		}
+
+		// Resolve the range of the item
+		start, end := bounds.min, bounds.max
+		if rangeAndStep[0] != "*" {
+			startAndEnd := strings.SplitN(rangeAndStep[0], "-", 2)
+			var err error
+			if start, err = parseCronValue(startAndEnd[0], bounds); err != nil {
+				return 0, err
+			}
+			end = start
+			if len(startAndEnd) == 2 {
+				if end, err = parseCronValue(startAndEnd[1], bounds); err != nil {
+					return 0, err
+				}
+			} else if len(rangeAndStep) == 2 {
+				// "a/n" means from a to the maximum with the step n
+				end = bounds.max
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q in the %s field", rangeAndStep[0], bounds.name)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+
+	// Sunday may be written as 7
+	if bounds.name == "day of week" && set&(1<<7) != 0 {
+		set = set&^(1<<7) | 1
+	}
+
+	return set, nil
+}
+
+// parseCronValue parses a single value of a cron field and checks its bounds.
+func parseCronValue(str string, bounds cronField) (int, error) {
+	value, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in the %s field", str, bounds.name)
+	}
+
+	max := bounds.max
+	if bounds.name == "day of week" {
+		max = 7
+	}
+	if value < bounds.min || value > max {
+		return 0, fmt.Errorf("value %d is out of the %d-%d range of the %s field", value, bounds.min, max, bounds.name)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,145 @@
+package utils // dnywonnt.me/alerts2incidents/internal/utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// mustLoadLocation loads a time zone of the tests.
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("error loading time zone %s: %v", name, err)
+	}
+	return location
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	local := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.Local)
+	}
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// Steps, ranges and lists
+		{"minute step", "*/15 * * * *", local(2024, 1, 15, 10, 7), local(2024, 1, 15, 10, 15)},
+		{"minute step over the hour", "*/15 * * * *", local(2024, 1, 15, 10, 45), local(2024, 1, 15, 11, 0)},
+		{"step from a value", "5/20 * * * *", local(2024, 1, 15, 10, 6), local(2024, 1, 15, 10, 25)},
+		{"step from a value over the hour", "5/20 * * * *", local(2024, 1, 15, 10, 45), local(2024, 1, 15, 11, 5)},
+		{"range with a step", "0 9-18/3 * * *", local(2024, 1, 15, 10, 0), local(2024, 1, 15, 12, 0)},
+		{"range with a step over the day", "0 9-18/3 * * *", local(2024, 1, 15, 18, 0), local(2024, 1, 16, 9, 0)},
+		{"list of days", "0 0 1,15 * *", local(2024, 1, 2, 0, 0), local(2024, 1, 15, 0, 0)},
+		{"range of week days", "30 8 * * 1-5", local(2024, 1, 19, 9, 0), local(2024, 1, 22, 8, 30)},
+		{"strictly after the given time", "0 12 * * *", local(2024, 1, 15, 12, 0), local(2024, 1, 16, 12, 0)},
+		{"seconds are truncated", "0 12 * * *", local(2024, 1, 15, 11, 59).Add(30 * time.Second), local(2024, 1, 15, 12, 0)},
+
+		// Day of month and day of week
+		{"both days restricted match the week day", "0 0 13 * 5", local(2024, 1, 1, 0, 0), local(2024, 1, 5, 0, 0)},
+		{"both days restricted match the month day", "0 0 13 * 5", local(2024, 1, 12, 0, 0), local(2024, 1, 13, 0, 0)},
+		{"only the week day restricted", "0 0 * * 1", local(2024, 1, 16, 0, 0), local(2024, 1, 22, 0, 0)},
+		{"only the month day restricted", "0 0 13 * *", local(2024, 1, 14, 0, 0), local(2024, 2, 13, 0, 0)},
+		// As in cron, a field starting with "*" makes both days match, so the 1st, 11th, 21st or 31st must be a Monday
+		{"stepped month day and the week day", "0 0 */10 * 1", local(2024, 1, 1, 0, 0), local(2024, 3, 11, 0, 0)},
+		{"seven is Sunday", "0 0 * * 7", local(2024, 1, 15, 0, 0), local(2024, 1, 21, 0, 0)},
+		{"leap day", "0 0 29 2 *", local(2024, 3, 1, 0, 0), local(2028, 2, 29, 0, 0)},
+		{"never matching", "0 0 31 2 *", local(2024, 1, 1, 0, 0), time.Time{}},
+
+		// Macros
+		{"weekly", "@weekly", local(2024, 1, 17, 10, 0), local(2024, 1, 21, 0, 0)},
+		{"daily", "@daily", local(2024, 1, 17, 23, 59), local(2024, 1, 18, 0, 0)},
+		{"midnight", "@midnight", local(2024, 1, 17, 0, 0), local(2024, 1, 18, 0, 0)},
+		{"hourly", "@hourly", local(2024, 1, 17, 10, 30), local(2024, 1, 17, 11, 0)},
+		{"monthly", "@monthly", local(2024, 1, 17, 10, 0), local(2024, 2, 1, 0, 0)},
+		{"yearly", "@yearly", local(2024, 1, 17, 10, 0), local(2025, 1, 1, 0, 0)},
+
+		// Time zones; 09:00 in Tokyo is 00:00 UTC
+		{"time zone", "CRON_TZ=Asia/Tokyo 0 9 * * *", utc(2024, 1, 14, 23, 0), utc(2024, 1, 15, 0, 0)},
+		{"time zone strictly after", "CRON_TZ=Asia/Tokyo 0 9 * * *", utc(2024, 1, 15, 0, 0), utc(2024, 1, 16, 0, 0)},
+		{"time zone with a macro", "CRON_TZ=Asia/Tokyo @daily", utc(2024, 1, 15, 10, 0), utc(2024, 1, 15, 15, 0)},
+
+		// On 2024-03-10 New York skips from 02:00 EST (07:00 UTC) to 03:00 EDT, so the times of the gap don't exist
+		{"gap skips the missing time", "CRON_TZ=America/New_York 30 2 * * *", utc(2024, 3, 9, 8, 0), utc(2024, 3, 11, 6, 30)},
+		{"gap before the missing time", "CRON_TZ=America/New_York 30 2 * * *", utc(2024, 3, 9, 6, 0), utc(2024, 3, 9, 7, 30)},
+		{"hourly over the gap", "CRON_TZ=America/New_York 0 * * * *", utc(2024, 3, 10, 6, 30), utc(2024, 3, 10, 7, 0)},
+		{"minutes over the gap", "CRON_TZ=America/New_York */20 * * * *", utc(2024, 3, 10, 6, 50), utc(2024, 3, 10, 7, 0)},
+		// On 2024-09-08 Santiago skips from 00:00 -04 (04:00 UTC) to 01:00 -03, so the midnight of that day doesn't exist
+		{"gap at midnight", "CRON_TZ=America/Santiago @daily", utc(2024, 9, 7, 16, 0), utc(2024, 9, 9, 3, 0)},
+		{"hourly over the gap at midnight", "CRON_TZ=America/Santiago 0 * * * *", utc(2024, 9, 8, 3, 30), utc(2024, 9, 8, 4, 0)},
+
+		// On 2024-11-03 New York goes back from 02:00 EDT (06:00 UTC) to 01:00 EST, so the times from 01:00 to 02:00 repeat;
+		// as in robfig/cron, a repeated time matches in both offsets
+		{"overlap in the first offset", "CRON_TZ=America/New_York 30 1 * * *", utc(2024, 11, 3, 4, 0), utc(2024, 11, 3, 5, 30)},
+		{"overlap in the second offset", "CRON_TZ=America/New_York 30 1 * * *", utc(2024, 11, 3, 5, 30), utc(2024, 11, 3, 6, 30)},
+		{"after the overlap", "CRON_TZ=America/New_York 30 1 * * *", utc(2024, 11, 3, 6, 30), utc(2024, 11, 4, 6, 30)},
+		{"hourly in the overlap", "CRON_TZ=America/New_York 0 * * * *", utc(2024, 11, 3, 5, 0), utc(2024, 11, 3, 6, 0)},
+		{"hourly after the overlap", "CRON_TZ=America/New_York 0 * * * *", utc(2024, 11, 3, 6, 0), utc(2024, 11, 3, 7, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("error parsing %q: %v", tt.expr, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) of %q = %v, want %v", tt.from, tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextIsInTheScheduleTimeZone(t *testing.T) {
+	schedule, err := ParseCronSchedule("CRON_TZ=Asia/Tokyo 0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := schedule.Next(time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC))
+	if next.Location().String() != mustLoadLocation(t, "Asia/Tokyo").String() || next.Hour() != 9 {
+		t.Errorf("Next returned %v, want 09:00 in Asia/Tokyo", next)
+	}
+}
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "* 24 * * *"},
+		{"day of month out of range", "* * 0 * *"},
+		{"month out of range", "* * * 13 *"},
+		{"day of week out of range", "* * * * 8"},
+		{"negative value", "-1 * * * *"},
+		{"not a number", "a * * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"invalid step", "*/x * * * *"},
+		{"reversed range", "5-1 * * * *"},
+		{"malformed range", "1-2-3 * * * *"},
+		{"empty list item", "1,,2 * * * *"},
+		{"unknown macro", "@every 5m"},
+		{"unknown time zone", "CRON_TZ=Nowhere/City * * * * *"},
+		{"time zone without an expression", "CRON_TZ=UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCronSchedule(tt.expr); err == nil {
+				t.Errorf("ParseCronSchedule(%q) succeeded, want an error", tt.expr)
+			}
+		})
+	}
+}
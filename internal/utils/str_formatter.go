@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // JoinWithCommas takes a slice of strings and joins them into a single string separated by commas.
@@ -78,3 +79,12 @@ func FormatNumberWithCommas(num int) string {
 
 	return result.String()
 }
+
+// FormatDurationSinceNow returns the time elapsed since t rounded to minutes, e.g. "1h5m".
+func FormatDurationSinceNow(t time.Time) string {
+	elapsed := time.Since(t).Round(time.Minute)
+	if elapsed < time.Minute {
+		return "0m"
+	}
+	return strings.TrimSuffix(elapsed.String(), "0s")
+}
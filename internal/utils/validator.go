@@ -14,6 +14,19 @@ var validate *validator.Validate
 func init() {
 	validate = validator.New()
 	validate.RegisterValidation("required_if_m", requiredIfM)
+	RegisterJSONFieldNames(validate)
+}
+
+// RegisterJSONFieldNames makes a validator report the fields by their JSON names, e.g. summary instead of Summary,
+// so the validation errors name the fields the way the clients send them; the fields without json tags keep their Go names
+func RegisterJSONFieldNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
 }
 
 // Custom validation function to check if the field value is required based on another field's value
@@ -0,0 +1,3 @@
+-- 20240315003_create_a2i_incident_events_table.down.sql
+DROP INDEX IF EXISTS a2i_incident_events_incident_id_idx;
+DROP TABLE IF EXISTS a2i_incident_events;
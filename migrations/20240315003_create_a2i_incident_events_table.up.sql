@@ -0,0 +1,12 @@
+-- 20240315003_create_a2i_incident_events_table.up.sql
+CREATE TABLE a2i_incident_events (
+    id                  VARCHAR(255) PRIMARY KEY,
+    incident_id         VARCHAR(255) NOT NULL,
+    type                VARCHAR(255) NOT NULL,
+    data                JSONB NOT NULL,
+    creator             VARCHAR(255) NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    CONSTRAINT fk_incident FOREIGN KEY(incident_id) REFERENCES a2i_incidents(id) ON DELETE CASCADE
+);
+
+CREATE INDEX a2i_incident_events_incident_id_idx ON a2i_incident_events (incident_id, type);
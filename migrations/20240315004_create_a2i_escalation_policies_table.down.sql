@@ -0,0 +1,2 @@
+-- 20240315004_create_a2i_escalation_policies_table.down.sql
+DROP TABLE IF EXISTS a2i_escalation_policies;
@@ -0,0 +1,12 @@
+-- 20240315004_create_a2i_escalation_policies_table.up.sql
+CREATE TABLE a2i_escalation_policies (
+    id                  VARCHAR(255) PRIMARY KEY,
+    description         TEXT NOT NULL,
+    rule_id             VARCHAR(255) UNIQUE,
+    departament         VARCHAR(255) UNIQUE,
+    steps               JSONB NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CONSTRAINT fk_rule FOREIGN KEY(rule_id) REFERENCES a2i_rules(id) ON DELETE CASCADE,
+    CONSTRAINT chk_target CHECK ((rule_id IS NULL) <> (departament IS NULL))
+);
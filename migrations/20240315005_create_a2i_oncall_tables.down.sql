@@ -0,0 +1,5 @@
+-- 20240315005_create_a2i_oncall_tables.down.sql
+DROP INDEX IF EXISTS a2i_oncall_overrides_schedule_id_idx;
+DROP TABLE IF EXISTS a2i_oncall_overrides;
+DROP TABLE IF EXISTS a2i_oncall_schedules;
+DROP TABLE IF EXISTS a2i_oncall_users;
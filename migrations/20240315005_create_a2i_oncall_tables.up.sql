@@ -0,0 +1,35 @@
+-- 20240315005_create_a2i_oncall_tables.up.sql
+CREATE TABLE a2i_oncall_users (
+    id                  VARCHAR(255) PRIMARY KEY,
+    name                VARCHAR(255) NOT NULL,
+    telegram_id         BIGINT NOT NULL,
+    email               VARCHAR(255) NOT NULL,
+    ldap_login          VARCHAR(255) NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE TABLE a2i_oncall_schedules (
+    id                  VARCHAR(255) PRIMARY KEY,
+    name                VARCHAR(255) NOT NULL UNIQUE,
+    description         TEXT NOT NULL,
+    timezone            VARCHAR(255) NOT NULL,
+    rotation            VARCHAR(255) NOT NULL,
+    rotation_start      TIMESTAMP NOT NULL,
+    user_ids            VARCHAR(255)[] NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE TABLE a2i_oncall_overrides (
+    id                  VARCHAR(255) PRIMARY KEY,
+    schedule_id         VARCHAR(255) NOT NULL,
+    user_id             VARCHAR(255) NOT NULL,
+    from_at             TIMESTAMP NOT NULL,
+    to_at               TIMESTAMP NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    CONSTRAINT fk_schedule FOREIGN KEY(schedule_id) REFERENCES a2i_oncall_schedules(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES a2i_oncall_users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX a2i_oncall_overrides_schedule_id_idx ON a2i_oncall_overrides (schedule_id, from_at, to_at);
@@ -0,0 +1,5 @@
+-- 20240315006_create_a2i_maintenance_tables.down.sql
+DROP TABLE IF EXISTS a2i_maintenance_suppressions;
+DROP TRIGGER IF EXISTS a2i_maintenance_windows_event_trigger ON a2i_maintenance_windows;
+DROP FUNCTION IF EXISTS notify_a2i_maintenance_windows_event();
+DROP TABLE IF EXISTS a2i_maintenance_windows;
@@ -0,0 +1,48 @@
+-- 20240315006_create_a2i_maintenance_tables.up.sql
+CREATE TABLE a2i_maintenance_windows (
+    id                  VARCHAR(255) PRIMARY KEY,
+    description         TEXT NOT NULL,
+    from_at             TIMESTAMP NOT NULL,
+    to_at               TIMESTAMP NOT NULL,
+    recurrence          VARCHAR(255) NOT NULL,
+    timezone            VARCHAR(255) NOT NULL,
+    rule_ids            VARCHAR(255)[] NOT NULL,
+    labels              VARCHAR(255)[] NOT NULL,
+    trouble_services    VARCHAR(255)[] NOT NULL,
+    creator             VARCHAR(255) NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION notify_a2i_maintenance_windows_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        PERFORM pg_notify('a2i_maintenance_windows_channel', 'INSERT:' || NEW.id);
+    ELSIF (TG_OP = 'UPDATE') THEN
+        PERFORM pg_notify('a2i_maintenance_windows_channel', 'UPDATE:' || NEW.id);
+    ELSIF (TG_OP = 'DELETE') THEN
+        PERFORM pg_notify('a2i_maintenance_windows_channel', 'DELETE:' || OLD.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER a2i_maintenance_windows_event_trigger
+AFTER INSERT OR UPDATE OR DELETE ON a2i_maintenance_windows
+FOR EACH ROW EXECUTE FUNCTION notify_a2i_maintenance_windows_event();
+
+-- Suppressed matches are kept after their window is deleted, so they can be audited afterwards
+CREATE TABLE a2i_maintenance_suppressions (
+    id                  VARCHAR(255) PRIMARY KEY,
+    window_id           VARCHAR(255) NOT NULL,
+    window_from_at      TIMESTAMP NOT NULL,
+    rule_id             VARCHAR(255) NOT NULL,
+    matching_count      INT NOT NULL,
+    first_matching_time TIMESTAMP NOT NULL,
+    last_matching_time  TIMESTAMP NOT NULL,
+    alerts_data         JSONB NOT NULL,
+    CONSTRAINT uq_window_occurrence_rule UNIQUE (window_id, window_from_at, rule_id)
+);
+
+CREATE INDEX a2i_maintenance_suppressions_rule_id_idx ON a2i_maintenance_suppressions (rule_id);
@@ -0,0 +1,7 @@
+-- 20240315007_add_a2i_rules_mute_columns.down.sql
+DROP INDEX IF EXISTS a2i_rules_mute_until_idx;
+
+ALTER TABLE a2i_rules
+    DROP COLUMN IF EXISTS mute_until,
+    DROP COLUMN IF EXISTS mute_reason,
+    DROP COLUMN IF EXISTS muted_by;
@@ -0,0 +1,7 @@
+-- 20240315007_add_a2i_rules_mute_columns.up.sql
+ALTER TABLE a2i_rules
+    ADD COLUMN mute_until   TIMESTAMP,
+    ADD COLUMN mute_reason  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN muted_by     VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX a2i_rules_mute_until_idx ON a2i_rules (mute_until) WHERE is_muted;
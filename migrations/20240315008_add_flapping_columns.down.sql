@@ -0,0 +1,9 @@
+-- 20240315008_add_flapping_columns.down.sql
+ALTER TABLE a2i_incidents
+    DROP COLUMN IF EXISTS is_flapping,
+    DROP COLUMN IF EXISTS flap_count,
+    DROP COLUMN IF EXISTS state_changes_at;
+
+ALTER TABLE a2i_rules
+    DROP COLUMN IF EXISTS flapping_window,
+    DROP COLUMN IF EXISTS flapping_threshold;
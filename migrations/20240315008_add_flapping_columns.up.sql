@@ -0,0 +1,9 @@
+-- 20240315008_add_flapping_columns.up.sql
+ALTER TABLE a2i_rules
+    ADD COLUMN flapping_window      INTERVAL NOT NULL DEFAULT '0',
+    ADD COLUMN flapping_threshold   INT NOT NULL DEFAULT 0;
+
+ALTER TABLE a2i_incidents
+    ADD COLUMN is_flapping          BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN flap_count           INT NOT NULL DEFAULT 0,
+    ADD COLUMN state_changes_at     TIMESTAMP[];
@@ -0,0 +1,2 @@
+-- 20240315009_create_a2i_incident_alerts_table.down.sql
+DROP TABLE IF EXISTS a2i_incident_alerts;
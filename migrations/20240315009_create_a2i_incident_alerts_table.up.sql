@@ -0,0 +1,15 @@
+-- 20240315009_create_a2i_incident_alerts_table.up.sql
+CREATE TABLE a2i_incident_alerts (
+    incident_id         VARCHAR(255) NOT NULL,
+    fingerprint         VARCHAR(255) NOT NULL,
+    summary             TEXT NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    matching_count      INT NOT NULL,
+    first_seen_at       TIMESTAMP NOT NULL,
+    last_seen_at        TIMESTAMP NOT NULL,
+    resolved_at         TIMESTAMP,
+    PRIMARY KEY (incident_id, fingerprint),
+    CONSTRAINT fk_incident FOREIGN KEY(incident_id) REFERENCES a2i_incidents(id) ON DELETE CASCADE
+);
+
+CREATE INDEX a2i_incident_alerts_fingerprint_idx ON a2i_incident_alerts (fingerprint);
@@ -0,0 +1,9 @@
+-- 20240315010_add_severity_columns.down.sql
+DROP INDEX IF EXISTS a2i_incidents_severity_idx;
+
+ALTER TABLE a2i_incidents
+    DROP COLUMN IF EXISTS severity;
+
+ALTER TABLE a2i_rules
+    DROP COLUMN IF EXISTS set_incident_severity,
+    DROP COLUMN IF EXISTS derive_incident_severity;
@@ -0,0 +1,9 @@
+-- 20240315010_add_severity_columns.up.sql
+ALTER TABLE a2i_rules
+    ADD COLUMN set_incident_severity        VARCHAR(255) NOT NULL DEFAULT 'SEV4',
+    ADD COLUMN derive_incident_severity     BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE a2i_incidents
+    ADD COLUMN severity                     VARCHAR(255) NOT NULL DEFAULT 'SEV4';
+
+CREATE INDEX a2i_incidents_severity_idx ON a2i_incidents (severity);
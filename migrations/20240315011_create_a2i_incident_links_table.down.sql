@@ -0,0 +1,6 @@
+-- 20240315011_create_a2i_incident_links_table.down.sql
+DROP TABLE IF EXISTS a2i_incident_links;
+
+ALTER TABLE a2i_incidents
+    DROP CONSTRAINT IF EXISTS fk_merged_into,
+    DROP COLUMN IF EXISTS merged_into_id;
@@ -0,0 +1,18 @@
+-- 20240315011_create_a2i_incident_links_table.up.sql
+ALTER TABLE a2i_incidents
+    ADD COLUMN merged_into_id       VARCHAR(255),
+    ADD CONSTRAINT fk_merged_into FOREIGN KEY(merged_into_id) REFERENCES a2i_incidents(id) ON DELETE SET NULL;
+
+CREATE TABLE a2i_incident_links (
+    id                  VARCHAR(255) PRIMARY KEY,
+    incident_id         VARCHAR(255) NOT NULL,
+    linked_incident_id  VARCHAR(255) NOT NULL,
+    type                VARCHAR(255) NOT NULL,
+    creator             VARCHAR(255) NOT NULL,
+    created_at          TIMESTAMP NOT NULL,
+    CONSTRAINT fk_incident FOREIGN KEY(incident_id) REFERENCES a2i_incidents(id) ON DELETE CASCADE,
+    CONSTRAINT fk_linked_incident FOREIGN KEY(linked_incident_id) REFERENCES a2i_incidents(id) ON DELETE CASCADE,
+    CONSTRAINT uq_incident_link UNIQUE (incident_id, linked_incident_id, type)
+);
+
+CREATE INDEX a2i_incident_links_linked_incident_id_idx ON a2i_incident_links (linked_incident_id);
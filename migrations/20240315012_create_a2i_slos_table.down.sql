@@ -0,0 +1,3 @@
+-- 20240315012_create_a2i_slos_table.down.sql
+DROP INDEX IF EXISTS a2i_incidents_is_downtime_idx;
+DROP TABLE IF EXISTS a2i_slos;
@@ -0,0 +1,16 @@
+-- 20240315012_create_a2i_slos_table.up.sql
+CREATE TABLE a2i_slos (
+    id                  VARCHAR(255) PRIMARY KEY,
+    description         TEXT NOT NULL,
+    target_type         VARCHAR(255) NOT NULL,
+    target              VARCHAR(255) NOT NULL,
+    objective           DOUBLE PRECISION NOT NULL,
+    period              VARCHAR(255) NOT NULL,
+    burn_alerted_at     TIMESTAMP,
+    created_at          TIMESTAMP NOT NULL,
+    updated_at          TIMESTAMP NOT NULL,
+    CONSTRAINT uq_slo_target UNIQUE (target_type, target, period),
+    CONSTRAINT chk_objective CHECK (objective > 0 AND objective < 100)
+);
+
+CREATE INDEX a2i_incidents_is_downtime_idx ON a2i_incidents (from_at) WHERE is_downtime;
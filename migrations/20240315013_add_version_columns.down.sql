@@ -0,0 +1,6 @@
+-- 20240315013_add_version_columns.down.sql
+ALTER TABLE a2i_incidents
+    DROP COLUMN IF EXISTS version;
+
+ALTER TABLE a2i_rules
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,6 @@
+-- 20240315013_add_version_columns.up.sql
+ALTER TABLE a2i_rules
+    ADD COLUMN version                      INT NOT NULL DEFAULT 1;
+
+ALTER TABLE a2i_incidents
+    ADD COLUMN version                      INT NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- 20240315014_add_closed_at_column.down.sql
+ALTER TABLE a2i_incidents
+    DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,10 @@
+-- 20240315014_add_closed_at_column.up.sql
+ALTER TABLE a2i_incidents
+    ADD COLUMN closed_at            TIMESTAMP;
+
+-- The incidents closed before don't keep the time of closing, their last update is the closest to it
+UPDATE a2i_incidents
+SET closed_at = updated_at
+WHERE status = 'closed';
+
+CREATE INDEX a2i_incidents_closed_at_idx ON a2i_incidents (closed_at);